@@ -0,0 +1,344 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/lease"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// AutoApplyStatusConfigMap is the name of the ConfigMap in kube-system that the status of the auto-apply controller is written to.
+	AutoApplyStatusConfigMap = "kops-controller-auto-apply"
+	// AutoApplyStatusKey is the key of the ConfigMap data holding the AutoApplyStatus.
+	AutoApplyStatusKey = "status"
+
+	// AutoApplyConditionConverged is true when the cloud matches the phases of the specs that are applied automatically.
+	AutoApplyConditionConverged = "Converged"
+
+	// AutoApplyReasonUpToDate is the reason when there were no changes to apply.
+	AutoApplyReasonUpToDate = "UpToDate"
+	// AutoApplyReasonApplied is the reason when changes were applied.
+	AutoApplyReasonApplied = "Applied"
+	// AutoApplyReasonPendingChanges is the reason when phases that are not applied automatically have changes.
+	AutoApplyReasonPendingChanges = "PendingChanges"
+	// AutoApplyReasonReadFailed is the reason when the specs could not be read from the state store.
+	AutoApplyReasonReadFailed = "ReadFailed"
+	// AutoApplyReasonStateStoreLocked is the reason when someone else was updating the cluster.
+	AutoApplyReasonStateStoreLocked = "StateStoreLocked"
+	// AutoApplyReasonApplyFailed is the reason when the changes could not be applied.
+	AutoApplyReasonApplyFailed = "ApplyFailed"
+)
+
+// AutoApplyStatus is the status of the auto-apply controller.
+type AutoApplyStatus struct {
+	// SpecHash is the hash of the cluster and instance group specs last read from the state store.
+	SpecHash string `json:"specHash,omitempty"`
+	// LastReconcileTime is when the specs were last compared to the cloud.
+	LastReconcileTime metav1.Time `json:"lastReconcileTime"`
+	// LastAppliedTime is when changes were last applied.
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// LastAppliedSpecHash is the hash of the specs when changes were last applied.
+	LastAppliedSpecHash string `json:"lastAppliedSpecHash,omitempty"`
+	// PendingChanges are the tasks of the phases whose policy is Never that do not match the cloud.
+	// They must be applied with `kops update cluster`.
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+	// Conditions are the conditions of the cluster, as observed by the controller.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NewAutoApplyReconciler is the constructor for an AutoApplyReconciler
+func NewAutoApplyReconciler(mgr manager.Manager, configBase string, options *config.AutoApplyOptions) (*AutoApplyReconciler, error) {
+	registryBase := strings.TrimSuffix(configBase, "/"+options.ClusterName)
+	if registryBase == configBase {
+		return nil, fmt.Errorf("ConfigBase %q is not the path of cluster %q in a state store", configBase, options.ClusterName)
+	}
+	basePath, err := vfs.Context.BuildVfsPath(registryBase)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse state store %q: %v", registryBase, err)
+	}
+
+	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building corev1 client: %v", err)
+	}
+
+	return &AutoApplyReconciler{
+		options:      options,
+		clientset:    vfsclientset.NewVFSClientset(basePath),
+		coreV1Client: coreClient,
+	}, nil
+}
+
+// AutoApplyReconciler periodically applies the cluster and instance group specs in the state store to the cloud,
+// as `kops update cluster --yes` does. The phases whose policy is Never are only checked.
+type AutoApplyReconciler struct {
+	// options configures which phases are applied, and how often
+	options *config.AutoApplyOptions
+
+	// clientset reads the specs from the state store
+	clientset simple.Clientset
+
+	// coreV1Client is a client-go client for writing the status
+	coreV1Client corev1client.CoreV1Interface
+
+	// status is the last status written
+	status AutoApplyStatus
+}
+
+var _ manager.LeaderElectionRunnable = &AutoApplyReconciler{}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that only one kops-controller applies changes.
+func (r *AutoApplyReconciler) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable, reconciling every interval until ctx is done.
+func (r *AutoApplyReconciler) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.reconcile, r.options.Interval.Duration)
+	return nil
+}
+
+// reconcile applies the specs once, and writes the outcome to the status.
+func (r *AutoApplyReconciler) reconcile(ctx context.Context) {
+	changes, pending, err := r.apply(ctx)
+	r.updateStatus(changes, pending, err)
+
+	if err := r.writeStatus(ctx); err != nil {
+		klog.Warningf("auto-apply: error writing status: %v", err)
+	}
+}
+
+// updateStatus records the outcome of apply in the status.
+func (r *AutoApplyReconciler) updateStatus(changes int, pending []string, err error) {
+	condition := metav1.Condition{
+		Type: AutoApplyConditionConverged,
+	}
+
+	now := metav1.Now()
+	r.status.LastReconcileTime = now
+	if err == nil {
+		r.status.PendingChanges = pending
+	}
+	if err == nil && changes != 0 {
+		klog.Infof("auto-apply: applied %d changes", changes)
+		r.status.LastAppliedTime = &now
+		r.status.LastAppliedSpecHash = r.status.SpecHash
+	}
+
+	var heldErr *lease.HeldError
+	switch {
+	case err == nil && len(pending) != 0:
+		klog.Infof("auto-apply: changes pending to phases that are not applied automatically: %s", strings.Join(pending, ", "))
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = AutoApplyReasonPendingChanges
+		condition.Message = fmt.Sprintf("%d changes to phases that are not applied automatically must be applied with kops update cluster", len(pending))
+		if changes != 0 {
+			condition.Message = fmt.Sprintf("Applied %d changes; ", changes) + condition.Message
+		}
+	case err == nil && changes == 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = AutoApplyReasonUpToDate
+		condition.Message = "No changes to apply"
+	case err == nil:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = AutoApplyReasonApplied
+		condition.Message = fmt.Sprintf("Applied %d changes", changes)
+	case errors.As(err, &heldErr):
+		klog.Infof("auto-apply: skipped: %v", err)
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = AutoApplyReasonStateStoreLocked
+		condition.Message = fmt.Sprintf("The state store is locked by %s for %q", heldErr.Lease.Holder, heldErr.Lease.Operation)
+	default:
+		klog.Warningf("auto-apply: %v", err)
+		var readErr *readSpecsError
+		if errors.As(err, &readErr) {
+			condition.Reason = AutoApplyReasonReadFailed
+		} else {
+			condition.Reason = AutoApplyReasonApplyFailed
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&r.status.Conditions, condition)
+}
+
+// readSpecsError is returned when the specs cannot be read from the state store.
+type readSpecsError struct {
+	err error
+}
+
+func (e *readSpecsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *readSpecsError) Unwrap() error {
+	return e.err
+}
+
+// apply compares the specs to the cloud, and applies the changes if there are any.
+// It returns the number of changes applied, and the tasks of the phases that are only
+// checked whose changes were not applied.
+func (r *AutoApplyReconciler) apply(ctx context.Context) (int, []string, error) {
+	cluster, err := r.clientset.GetCluster(ctx, r.options.ClusterName)
+	if err != nil {
+		return 0, nil, &readSpecsError{err: fmt.Errorf("error reading cluster %q: %w", r.options.ClusterName, err)}
+	}
+	list, err := r.clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, nil, &readSpecsError{err: fmt.Errorf("error reading instance groups: %w", err)}
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	specHash, err := cloudup.SpecHash(cluster, instanceGroups)
+	if err != nil {
+		return 0, nil, &readSpecsError{err: err}
+	}
+	r.status.SpecHash = specHash
+
+	// Hold the state store lock as `kops update cluster` does, so we don't apply concurrently with it
+	configBase, err := r.clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return 0, nil, &readSpecsError{err: err}
+	}
+	lock, err := lease.Acquire(configBase, cluster, lease.Options{
+		Operation: "kops-controller auto-apply",
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			klog.Warningf("error releasing the state store lock: %v", err)
+		}
+	}()
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// The dry run finds whether there are changes, so that we don't write to the state store and the cloud if there are none
+	dryRunCmd := r.newApplyCmd(cloud, cluster, instanceGroups, cloudup.TargetDryRun)
+	if err := dryRunCmd.Run(ctx); err != nil {
+		return 0, nil, fmt.Errorf("error comparing the specs to the cloud: %w", err)
+	}
+	target, ok := dryRunCmd.Target.(*fi.DryRunTarget)
+	if !ok {
+		return 0, nil, fmt.Errorf("unexpected target type %T", dryRunCmd.Target)
+	}
+	created, updated := target.Changes()
+	changes := len(created) + len(updated) + len(target.Deletions())
+	pending := target.UnappliedChanges()
+	if changes == 0 {
+		return 0, pending, nil
+	}
+
+	applyCmd := r.newApplyCmd(cloud, cluster, instanceGroups, cloudup.TargetDirect)
+	if err := applyCmd.Run(ctx); err != nil {
+		return 0, nil, fmt.Errorf("error applying %d changes: %w", changes, err)
+	}
+	return changes, pending, nil
+}
+
+// newApplyCmd builds an ApplyClusterCmd that applies the phases according to their policy.
+func (r *AutoApplyReconciler) newApplyCmd(cloud fi.Cloud, cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, targetName string) *cloudup.ApplyClusterCmd {
+	// ApplyClusterCmd populates the specs it is given
+	var igs []*kops.InstanceGroup
+	for _, ig := range instanceGroups {
+		igs = append(igs, ig.DeepCopy())
+	}
+
+	return &cloudup.ApplyClusterCmd{
+		Cloud:          cloud,
+		Clientset:      r.clientset,
+		Cluster:        cluster.DeepCopy(),
+		InstanceGroups: igs,
+		DryRun:         targetName == cloudup.TargetDryRun,
+		DryRunOut:      io.Discard,
+		TargetName:     targetName,
+		PhaseLifecycles: map[cloudup.Phase]fi.Lifecycle{
+			cloudup.PhaseNetwork:  policyLifecycle(r.options.Network),
+			cloudup.PhaseSecurity: policyLifecycle(r.options.Security),
+			cloudup.PhaseCluster:  policyLifecycle(r.options.Cluster),
+		},
+	}
+}
+
+// policyLifecycle returns the lifecycle of the tasks of a phase with the policy.
+func policyLifecycle(policy kops.AutoApplyPolicy) fi.Lifecycle {
+	if policy == kops.AutoApplyPolicyAuto {
+		return fi.LifecycleSync
+	}
+	return fi.LifecycleExistsAndWarnIfChanges
+}
+
+// writeStatus writes the status to the AutoApplyStatusConfigMap.
+func (r *AutoApplyReconciler) writeStatus(ctx context.Context) error {
+	b, err := json.Marshal(&r.status)
+	if err != nil {
+		return fmt.Errorf("error encoding status: %w", err)
+	}
+
+	configMaps := r.coreV1Client.ConfigMaps(metav1.NamespaceSystem)
+	configMap, err := configMaps.Get(ctx, AutoApplyStatusConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      AutoApplyStatusConfigMap,
+				Namespace: metav1.NamespaceSystem,
+			},
+			Data: map[string]string{AutoApplyStatusKey: string(b)},
+		}
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[AutoApplyStatusKey] = string(b)
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
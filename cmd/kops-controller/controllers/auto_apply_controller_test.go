@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/lease"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const testClusterName = "minimal.example.com"
+
+func newTestAutoApplyReconciler(t *testing.T, objects ...*corev1.ConfigMap) (*AutoApplyReconciler, *fake.Clientset) {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	require.NoError(t, err)
+
+	k8sClient := fake.NewSimpleClientset()
+	for _, object := range objects {
+		_, err := k8sClient.CoreV1().ConfigMaps(object.Namespace).Create(context.Background(), object, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	r := &AutoApplyReconciler{
+		options: &config.AutoApplyOptions{
+			ClusterName: testClusterName,
+			Network:     kops.AutoApplyPolicyNever,
+			Security:    kops.AutoApplyPolicyNever,
+			Cluster:     kops.AutoApplyPolicyAuto,
+		},
+		clientset:    vfsclientset.NewVFSClientset(basePath),
+		coreV1Client: k8sClient.CoreV1(),
+	}
+	return r, k8sClient
+}
+
+// readStatus reads the status written to the AutoApplyStatusConfigMap.
+func readStatus(t *testing.T, k8sClient *fake.Clientset) *AutoApplyStatus {
+	configMap, err := k8sClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(context.Background(), AutoApplyStatusConfigMap, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	status := &AutoApplyStatus{}
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data[AutoApplyStatusKey]), status))
+	return status
+}
+
+func TestAutoApplyReconcileReadFailed(t *testing.T) {
+	r, k8sClient := newTestAutoApplyReconciler(t)
+
+	r.reconcile(context.Background())
+
+	status := readStatus(t, k8sClient)
+	condition := meta.FindStatusCondition(status.Conditions, AutoApplyConditionConverged)
+	require.NotNil(t, condition, "Converged condition")
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, AutoApplyReasonReadFailed, condition.Reason)
+	assert.Contains(t, condition.Message, testClusterName)
+	assert.False(t, status.LastReconcileTime.IsZero(), "LastReconcileTime")
+	assert.Nil(t, status.LastAppliedTime, "LastAppliedTime")
+}
+
+func TestAutoApplyReconcileStateStoreLocked(t *testing.T) {
+	r, k8sClient := newTestAutoApplyReconciler(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AutoApplyStatusConfigMap,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string]string{"other": "kept"},
+	})
+	ctx := context.Background()
+
+	cluster := testutils.BuildMinimalCluster(testClusterName)
+	_, err := r.clientset.CreateCluster(ctx, cluster)
+	require.NoError(t, err)
+
+	configBase, err := r.clientset.ConfigBaseFor(cluster)
+	require.NoError(t, err)
+	lock, err := lease.Acquire(configBase, cluster, lease.Options{Operation: "update cluster"})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, lock.Release())
+	}()
+
+	r.reconcile(ctx)
+
+	status := readStatus(t, k8sClient)
+	condition := meta.FindStatusCondition(status.Conditions, AutoApplyConditionConverged)
+	require.NotNil(t, condition, "Converged condition")
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, AutoApplyReasonStateStoreLocked, condition.Reason)
+	assert.Contains(t, condition.Message, `"update cluster"`)
+	assert.NotEmpty(t, status.SpecHash, "SpecHash")
+	assert.Empty(t, status.LastAppliedSpecHash, "LastAppliedSpecHash")
+
+	configMap, err := k8sClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(ctx, AutoApplyStatusConfigMap, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "kept", configMap.Data["other"], "other keys of the ConfigMap")
+
+	// The lease is still held by the other holder
+	_, err = lease.Acquire(configBase, cluster, lease.Options{Operation: "test"})
+	assert.Error(t, err, "the reconciler must not take over the lease")
+}
+
+func TestAutoApplyUpdateStatus(t *testing.T) {
+	grid := []struct {
+		name           string
+		changes        int
+		pending        []string
+		err            error
+		status         metav1.ConditionStatus
+		reason         string
+		applied        bool
+		pendingChanges []string
+	}{
+		{
+			name:   "no changes",
+			status: metav1.ConditionTrue,
+			reason: AutoApplyReasonUpToDate,
+		},
+		{
+			name:    "applied",
+			changes: 2,
+			status:  metav1.ConditionTrue,
+			reason:  AutoApplyReasonApplied,
+			applied: true,
+		},
+		{
+			name:           "changes to a phase that is not applied",
+			pending:        []string{"SecurityGroup/nodes.minimal.example.com"},
+			status:         metav1.ConditionFalse,
+			reason:         AutoApplyReasonPendingChanges,
+			pendingChanges: []string{"SecurityGroup/nodes.minimal.example.com"},
+		},
+		{
+			name:           "applied with changes to a phase that is not applied",
+			changes:        1,
+			pending:        []string{"VPC/minimal.example.com"},
+			status:         metav1.ConditionFalse,
+			reason:         AutoApplyReasonPendingChanges,
+			applied:        true,
+			pendingChanges: []string{"VPC/minimal.example.com"},
+		},
+		{
+			name:   "apply failed",
+			err:    fmt.Errorf("error applying 1 changes: boom"),
+			status: metav1.ConditionFalse,
+			reason: AutoApplyReasonApplyFailed,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			r := &AutoApplyReconciler{}
+			r.status.SpecHash = "hash"
+
+			r.updateStatus(g.changes, g.pending, g.err)
+
+			condition := meta.FindStatusCondition(r.status.Conditions, AutoApplyConditionConverged)
+			require.NotNil(t, condition, "Converged condition")
+			assert.Equal(t, g.status, condition.Status, "Status")
+			assert.Equal(t, g.reason, condition.Reason, "Reason")
+			assert.Equal(t, g.pendingChanges, r.status.PendingChanges, "PendingChanges")
+			if g.applied {
+				assert.NotNil(t, r.status.LastAppliedTime, "LastAppliedTime")
+				assert.Equal(t, "hash", r.status.LastAppliedSpecHash, "LastAppliedSpecHash")
+			} else {
+				assert.Nil(t, r.status.LastAppliedTime, "LastAppliedTime")
+			}
+		})
+	}
+}
+
+func TestPolicyLifecycle(t *testing.T) {
+	grid := []struct {
+		policy   kops.AutoApplyPolicy
+		expected fi.Lifecycle
+	}{
+		{
+			policy:   kops.AutoApplyPolicyAuto,
+			expected: fi.LifecycleSync,
+		},
+		{
+			policy:   kops.AutoApplyPolicyNever,
+			expected: fi.LifecycleExistsAndWarnIfChanges,
+		},
+	}
+	for _, g := range grid {
+		assert.Equal(t, g.expected, policyLifecycle(g.policy), "policy %q", g.policy)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// UserRolesConfigMap is the name of the ConfigMap in kube-system that the user-roles addon lists its ClusterRoleBindings in.
+	UserRolesConfigMap = "kops-user-roles"
+	// UserRolesBindingsKey is the key of the ConfigMap data holding the names of the ClusterRoleBindings, one per line.
+	UserRolesBindingsKey = "bindings"
+
+	// userRolesSelector selects the ClusterRoleBindings applied by the user-roles addon.
+	userRolesSelector = "addon.kops.k8s.io/name=user-roles.rbac.addons.k8s.io"
+	// userRolesInterval is how often the ClusterRoleBindings are pruned.
+	userRolesInterval = time.Minute
+)
+
+// NewUserRolesReconciler is the constructor for a UserRolesReconciler
+func NewUserRolesReconciler(mgr manager.Manager) (*UserRolesReconciler, error) {
+	client, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	return &UserRolesReconciler{
+		client: client,
+	}, nil
+}
+
+// UserRolesReconciler deletes the ClusterRoleBindings of the user-roles addon that the addon no longer lists,
+// as applying an addon does not delete the objects removed from it.
+type UserRolesReconciler struct {
+	// client is a client-go client for reading the ConfigMap and deleting the ClusterRoleBindings
+	client kubernetes.Interface
+}
+
+var _ manager.LeaderElectionRunnable = &UserRolesReconciler{}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that only one kops-controller deletes bindings.
+func (r *UserRolesReconciler) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable, reconciling every interval until ctx is done.
+func (r *UserRolesReconciler) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.reconcile(ctx); err != nil {
+			klog.Warningf("error pruning user role bindings: %v", err)
+		}
+	}, userRolesInterval)
+	return nil
+}
+
+// reconcile deletes the ClusterRoleBindings of the addon that are not listed in the UserRolesConfigMap.
+// Nothing is deleted until the addon has written the ConfigMap.
+func (r *UserRolesReconciler) reconcile(ctx context.Context) error {
+	configMap, err := r.client.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(ctx, UserRolesConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading ConfigMap %s: %v", UserRolesConfigMap, err)
+	}
+	data, found := configMap.Data[UserRolesBindingsKey]
+	if !found {
+		return fmt.Errorf("ConfigMap %s has no %q key", UserRolesConfigMap, UserRolesBindingsKey)
+	}
+
+	expected := sets.NewString()
+	for _, line := range strings.Split(data, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			expected.Insert(name)
+		}
+	}
+
+	bindings, err := r.client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: userRolesSelector})
+	if err != nil {
+		return fmt.Errorf("error listing ClusterRoleBindings: %v", err)
+	}
+	for _, binding := range bindings.Items {
+		if expected.Has(binding.Name) {
+			continue
+		}
+		klog.Infof("deleting ClusterRoleBinding %q of a group role removed from the cluster spec", binding.Name)
+		if err := r.client.RbacV1().ClusterRoleBindings().Delete(ctx, binding.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting ClusterRoleBinding %q: %v", binding.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func userRoleBinding(name string, labels map[string]string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func TestUserRolesReconcile(t *testing.T) {
+	addonLabels := map[string]string{"addon.kops.k8s.io/name": "user-roles.rbac.addons.k8s.io"}
+	bindings := []runtime.Object{
+		userRoleBinding("kops:user-roles:developers:view", addonLabels),
+		userRoleBinding("kops:user-roles:developers:edit", addonLabels),
+		userRoleBinding("kops:user-roles:operators:admin", addonLabels),
+		userRoleBinding("cluster-admin", nil),
+	}
+
+	grid := []struct {
+		name      string
+		configMap *corev1.ConfigMap
+		expected  []string
+	}{
+		{
+			name: "removed group roles",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: UserRolesConfigMap, Namespace: metav1.NamespaceSystem},
+				Data:       map[string]string{UserRolesBindingsKey: "kops:user-roles:developers:view\n"},
+			},
+			expected: []string{"cluster-admin", "kops:user-roles:developers:view"},
+		},
+		{
+			name: "no group roles",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: UserRolesConfigMap, Namespace: metav1.NamespaceSystem},
+				Data:       map[string]string{UserRolesBindingsKey: ""},
+			},
+			expected: []string{"cluster-admin"},
+		},
+		{
+			name:     "addon not applied",
+			expected: []string{"cluster-admin", "kops:user-roles:developers:edit", "kops:user-roles:developers:view", "kops:user-roles:operators:admin"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			objects := append([]runtime.Object{}, bindings...)
+			if g.configMap != nil {
+				objects = append(objects, g.configMap)
+			}
+			client := fake.NewSimpleClientset(objects...)
+			r := &UserRolesReconciler{client: client}
+
+			require.NoError(t, r.reconcile(context.Background()))
+
+			remaining, err := client.RbacV1().ClusterRoleBindings().List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err)
+			var names []string
+			for _, binding := range remaining.Items {
+				names = append(names, binding.Name)
+			}
+			sort.Strings(names)
+			assert.Equal(t, g.expected, names)
+		})
+	}
+}
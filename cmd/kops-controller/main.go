@@ -24,6 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
@@ -98,7 +99,13 @@ func main() {
 			klog.Fatalf("server cloud provider config not provided")
 		}
 
-		srv, err := server.NewServer(&opt, verifier)
+		kubeClient, err := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+		if err != nil {
+			setupLog.Error(err, "unable to create kubernetes client")
+			os.Exit(1)
+		}
+
+		srv, err := server.NewServer(&opt, verifier, kubeClient)
 		if err != nil {
 			setupLog.Error(err, "unable to create server")
 			os.Exit(1)
@@ -130,6 +137,20 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeController")
 		os.Exit(1)
 	}
+
+	if opt.AutoApply != nil {
+		if err := addAutoApplyController(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AutoApplyController")
+			os.Exit(1)
+		}
+	}
+
+	if opt.PruneUserRoles {
+		if err := addUserRolesController(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "UserRolesController")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -212,3 +233,23 @@ func addNodeController(mgr manager.Manager, opt *config.Options) error {
 
 	return nil
 }
+
+func addAutoApplyController(mgr manager.Manager, opt *config.Options) error {
+	if opt.ConfigBase == "" {
+		return fmt.Errorf("must specify configBase")
+	}
+
+	autoApplyController, err := controllers.NewAutoApplyReconciler(mgr, opt.ConfigBase, opt.AutoApply)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(autoApplyController)
+}
+
+func addUserRolesController(mgr manager.Manager) error {
+	userRolesController, err := controllers.NewUserRolesReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(userRolesController)
+}
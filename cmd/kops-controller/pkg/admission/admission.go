@@ -0,0 +1,237 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Request is a node bootstrap request whose instance identity has been verified.
+type Request struct {
+	// NodeName is the name the node is authorized to use.
+	NodeName string `json:"nodeName"`
+	// InstanceGroupName is the name of the kops InstanceGroup the node is a member of.
+	InstanceGroupName string `json:"instanceGroupName"`
+	// RemoteAddr is the network address the request was sent from.
+	RemoteAddr string `json:"remoteAddr"`
+	// CertNames are the names of the certificates the node requests.
+	CertNames []string `json:"certNames"`
+	// Renewal is true if the node has registered and renews its certificates.
+	Renewal bool `json:"renewal,omitempty"`
+}
+
+// Admitter decides whether a node bootstrap request is issued certificates.
+type Admitter interface {
+	// Admit returns an error describing why the request is denied, or nil if it is admitted.
+	Admit(ctx context.Context, req *Request) error
+}
+
+// Chain admits a request if all of its admitters admit it. The admitters are called in order,
+// and the first denial is returned.
+type Chain []Admitter
+
+var _ Admitter = Chain{}
+
+func (c Chain) Admit(ctx context.Context, req *Request) error {
+	for _, admitter := range c {
+		if err := admitter.Admit(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// instanceGroupAdmitter admits requests from nodes of the allow-listed instance groups.
+type instanceGroupAdmitter struct {
+	instanceGroups sets.String
+}
+
+// NewInstanceGroupAdmitter returns an Admitter that admits requests from nodes of the named instance groups only.
+func NewInstanceGroupAdmitter(instanceGroups []string) Admitter {
+	return &instanceGroupAdmitter{instanceGroups: sets.NewString(instanceGroups...)}
+}
+
+func (a *instanceGroupAdmitter) Admit(ctx context.Context, req *Request) error {
+	if !a.instanceGroups.Has(req.InstanceGroupName) {
+		return fmt.Errorf("instance group %q is not allowed to bootstrap nodes", req.InstanceGroupName)
+	}
+	return nil
+}
+
+// rateLimitAdmitter admits requests at a limited rate.
+type rateLimitAdmitter struct {
+	limiter flowcontrol.RateLimiter
+}
+
+// NewRateLimitAdmitter returns an Admitter that admits up to requestsPerMinute requests per minute, with bursts of up to burst requests.
+func NewRateLimitAdmitter(requestsPerMinute int, burst int) Admitter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimitAdmitter{limiter: flowcontrol.NewTokenBucketRateLimiter(float32(requestsPerMinute)/60, burst)}
+}
+
+func (a *rateLimitAdmitter) Admit(ctx context.Context, req *Request) error {
+	if !a.limiter.TryAccept() {
+		return fmt.Errorf("bootstrap rate limit exceeded")
+	}
+	return nil
+}
+
+// NodeLister lists the names of the nodes registered in an instance group.
+type NodeLister func(ctx context.Context, instanceGroupName string) ([]string, error)
+
+// PendingNodeExpiry is how long an admitted node is counted towards the maximum of its instance group
+// before it has registered.
+const PendingNodeExpiry = 15 * time.Minute
+
+// maxNodesAdmitter limits the number of nodes of each instance group.
+type maxNodesAdmitter struct {
+	maxNodes map[string]int
+	lister   NodeLister
+	now      func() time.Time
+
+	mutex sync.Mutex
+	// pending holds the nodes admitted per instance group, and when they were admitted.
+	pending map[string]map[string]time.Time
+}
+
+// NewMaxNodesAdmitter returns an Admitter that denies requests from new nodes of an instance group that
+// already has maxNodes nodes. Nodes that were admitted but have not registered yet are counted too, so
+// that concurrent requests cannot exceed the maximum. Instance groups not in maxNodes are not limited.
+func NewMaxNodesAdmitter(maxNodes map[string]int, lister NodeLister) Admitter {
+	return &maxNodesAdmitter{
+		maxNodes: maxNodes,
+		lister:   lister,
+		now:      time.Now,
+		pending:  make(map[string]map[string]time.Time),
+	}
+}
+
+func (a *maxNodesAdmitter) Admit(ctx context.Context, req *Request) error {
+	max, found := a.maxNodes[req.InstanceGroupName]
+	if !found {
+		return nil
+	}
+
+	registered, err := a.lister(ctx, req.InstanceGroupName)
+	if err != nil {
+		return fmt.Errorf("listing nodes of instance group %q: %w", req.InstanceGroupName, err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	now := a.now()
+	pending := a.pending[req.InstanceGroupName]
+	if pending == nil {
+		pending = make(map[string]time.Time)
+		a.pending[req.InstanceGroupName] = pending
+	}
+
+	registeredNodes := sets.NewString(registered...)
+	nodes := sets.NewString(registered...)
+	for name, admitted := range pending {
+		if registeredNodes.Has(name) || now.Sub(admitted) > PendingNodeExpiry {
+			delete(pending, name)
+			continue
+		}
+		nodes.Insert(name)
+	}
+
+	// A node that is already counted bootstraps again, e.g. after a reboot
+	if !nodes.Has(req.NodeName) && nodes.Len() >= max {
+		return fmt.Errorf("instance group %q already has %d nodes, the maximum", req.InstanceGroupName, nodes.Len())
+	}
+
+	if !registeredNodes.Has(req.NodeName) {
+		pending[req.NodeName] = now
+	}
+	return nil
+}
+
+// Response is the response of an admission webhook.
+type Response struct {
+	// Allowed is true if the request is admitted.
+	Allowed bool `json:"allowed"`
+	// Reason describes why the request is denied.
+	Reason string `json:"reason,omitempty"`
+}
+
+// webhookAdmitter asks an external webhook whether to admit requests.
+type webhookAdmitter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookAdmitter returns an Admitter that posts each request as JSON to url and admits it if the
+// webhook responds with a Response that allows it. Requests are denied if the webhook fails or times out.
+func NewWebhookAdmitter(url string, timeout time.Duration) Admitter {
+	return &webhookAdmitter{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *webhookAdmitter) Admit(ctx context.Context, req *Request) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encoding admission webhook request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building admission webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := a.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("calling admission webhook: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("reading admission webhook response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("admission webhook returned status %d", httpResp.StatusCode)
+	}
+
+	resp := &Response{}
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return fmt.Errorf("decoding admission webhook response: %w", err)
+	}
+	if !resp.Allowed {
+		if resp.Reason == "" {
+			return fmt.Errorf("denied by admission webhook")
+		}
+		return fmt.Errorf("denied by admission webhook: %s", resp.Reason)
+	}
+	return nil
+}
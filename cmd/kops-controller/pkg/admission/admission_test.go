@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInstanceGroupAdmitter(t *testing.T) {
+	admitter := NewInstanceGroupAdmitter([]string{"nodes"})
+	ctx := context.Background()
+
+	if err := admitter.Admit(ctx, &Request{InstanceGroupName: "nodes"}); err != nil {
+		t.Errorf("unexpected denial of allow-listed instance group: %v", err)
+	}
+	if err := admitter.Admit(ctx, &Request{InstanceGroupName: "bastions"}); err == nil {
+		t.Errorf("expected denial of instance group not in the allow list")
+	}
+}
+
+func TestRateLimitAdmitter(t *testing.T) {
+	admitter := NewRateLimitAdmitter(1, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := admitter.Admit(ctx, &Request{}); err != nil {
+			t.Errorf("unexpected denial of request %d within burst: %v", i, err)
+		}
+	}
+	if err := admitter.Admit(ctx, &Request{}); err == nil {
+		t.Errorf("expected denial of request exceeding the burst")
+	}
+}
+
+func TestMaxNodesAdmitter(t *testing.T) {
+	registered := []string{"node-a"}
+	lister := func(ctx context.Context, instanceGroupName string) ([]string, error) {
+		return registered, nil
+	}
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	admitter := NewMaxNodesAdmitter(map[string]int{"nodes": 2}, lister).(*maxNodesAdmitter)
+	admitter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	grid := []struct {
+		Description string
+		Request     Request
+		Admitted    bool
+	}{
+		{
+			Description: "registered node bootstrapping again",
+			Request:     Request{NodeName: "node-a", InstanceGroupName: "nodes"},
+			Admitted:    true,
+		},
+		{
+			Description: "new node below the maximum",
+			Request:     Request{NodeName: "node-b", InstanceGroupName: "nodes"},
+			Admitted:    true,
+		},
+		{
+			Description: "pending node bootstrapping again",
+			Request:     Request{NodeName: "node-b", InstanceGroupName: "nodes"},
+			Admitted:    true,
+		},
+		{
+			Description: "new node above the maximum",
+			Request:     Request{NodeName: "node-c", InstanceGroupName: "nodes"},
+			Admitted:    false,
+		},
+		{
+			Description: "instance group without maximum",
+			Request:     Request{NodeName: "node-d", InstanceGroupName: "other"},
+			Admitted:    true,
+		},
+	}
+	for _, g := range grid {
+		err := admitter.Admit(ctx, &g.Request)
+		if g.Admitted && err != nil {
+			t.Errorf("%s: unexpected denial: %v", g.Description, err)
+		}
+		if !g.Admitted && err == nil {
+			t.Errorf("%s: expected denial", g.Description)
+		}
+	}
+
+	// Pending nodes that never registered stop counting after they expire
+	now = now.Add(PendingNodeExpiry + time.Minute)
+	if err := admitter.Admit(ctx, &Request{NodeName: "node-c", InstanceGroupName: "nodes"}); err != nil {
+		t.Errorf("unexpected denial after pending node expired: %v", err)
+	}
+}
+
+func TestWebhookAdmitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &Request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch req.NodeName {
+		case "allowed":
+			_ = json.NewEncoder(w).Encode(&Response{Allowed: true})
+		case "denied":
+			_ = json.NewEncoder(w).Encode(&Response{Allowed: false, Reason: "unknown node"})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	admitter := NewWebhookAdmitter(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	if err := admitter.Admit(ctx, &Request{NodeName: "allowed"}); err != nil {
+		t.Errorf("unexpected denial: %v", err)
+	}
+	if err := admitter.Admit(ctx, &Request{NodeName: "denied"}); err == nil {
+		t.Errorf("expected denial by the webhook")
+	}
+	if err := admitter.Admit(ctx, &Request{NodeName: "error"}); err == nil {
+		t.Errorf("expected denial when the webhook fails")
+	}
+}
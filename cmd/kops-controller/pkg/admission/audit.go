@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Decision is the outcome of a node bootstrap request.
+type Decision string
+
+const (
+	// DecisionAdmitted means the request passed admission, and the node is issued certificates.
+	DecisionAdmitted Decision = "Admitted"
+	// DecisionDenied means the request was refused.
+	DecisionDenied Decision = "Denied"
+)
+
+// AuditEvent is an entry of the audit log of node bootstrap requests.
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+	Request
+	Decision Decision `json:"decision"`
+	// Reason describes why the request was denied.
+	Reason string `json:"reason,omitempty"`
+}
+
+// AuditLog records node bootstrap requests and their decisions, one JSON object per line.
+type AuditLog struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+// NewAuditLog returns an AuditLog appending to the file at path, or writing to the klog output if path is empty.
+func NewAuditLog(path string) (*AuditLog, error) {
+	if path == "" {
+		return &AuditLog{}, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log %q: %w", path, err)
+	}
+	return &AuditLog{out: f}, nil
+}
+
+// Record writes event to the audit log. Failures are logged, as they must not prevent handling the request.
+func (l *AuditLog) Record(event *AuditEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		klog.Warningf("failed to encode audit event: %v", err)
+		return
+	}
+
+	if l.out == nil {
+		klog.Infof("bootstrap audit: %s", b)
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, err := l.out.Write(append(b, '\n')); err != nil {
+		klog.Warningf("failed to write audit event %s: %v", b, err)
+	}
+}
@@ -16,13 +16,23 @@ limitations under the License.
 
 package config
 
-import "k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
 
 type Options struct {
 	Cloud                 string         `json:"cloud,omitempty"`
 	ConfigBase            string         `json:"configBase,omitempty"`
 	Server                *ServerOptions `json:"server,omitempty"`
 	CacheNodeidentityInfo bool           `json:"cacheNodeidentityInfo,omitempty"`
+
+	// AutoApply configures kops-controller to apply the cluster spec to the cloud.
+	AutoApply *AutoApplyOptions `json:"autoApply,omitempty"`
+
+	// PruneUserRoles enables deleting the ClusterRoleBindings of the group roles removed from the cluster spec.
+	PruneUserRoles bool `json:"pruneUserRoles,omitempty"`
 }
 
 func (o *Options) PopulateDefaults() {
@@ -44,8 +54,47 @@ type ServerOptions struct {
 	CABasePath string `json:"caBasePath"`
 	// SigningCAs is the list of active signing CAs.
 	SigningCAs []string `json:"signingCAs"`
+	// KeyStore is the location of a Vault keystore whose PKI secrets engines hold signing CAs.
+	// If set, the signing CAs are read from it instead of from CABasePath.
+	KeyStore string `json:"keyStore,omitempty"`
 	// CertNames is the list of active certificate names.
 	CertNames []string `json:"certNames"`
+
+	// Admission configures which bootstrap requests are admitted.
+	Admission *AdmissionOptions `json:"admission,omitempty"`
+	// AuditLogPath is the path of the file bootstrap requests and their decisions are appended to.
+	// If empty, they are logged.
+	AuditLogPath string `json:"auditLogPath,omitempty"`
+}
+
+// AdmissionOptions configures which bootstrap requests are admitted, in addition to the verification of the node identity.
+type AdmissionOptions struct {
+	// InstanceGroups is the list of instance groups whose nodes may bootstrap. If empty, all are allowed.
+	InstanceGroups []string `json:"instanceGroups,omitempty"`
+	// MaxNodes is the maximum number of nodes of each instance group. Instance groups not listed are not limited.
+	MaxNodes map[string]int `json:"maxNodes,omitempty"`
+	// RequestsPerMinute is the number of requests admitted per minute. If zero, requests are not rate limited.
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	// Burst is the number of requests admitted at once when rate limited.
+	Burst int `json:"burst,omitempty"`
+	// WebhookURL is the URL of a webhook that approves or denies each request.
+	WebhookURL string `json:"webhookURL,omitempty"`
+	// WebhookTimeout is how long to wait for the webhook before denying the request.
+	WebhookTimeout *metav1.Duration `json:"webhookTimeout,omitempty"`
+}
+
+// AutoApplyOptions configures the periodic application of the cluster and instance group specs in the state store.
+type AutoApplyOptions struct {
+	// ClusterName is the name of the cluster in the state store.
+	ClusterName string `json:"clusterName"`
+	// Interval is how often the specs are applied.
+	Interval metav1.Duration `json:"interval"`
+	// Network is the policy for the network phase.
+	Network kops.AutoApplyPolicy `json:"network"`
+	// Security is the policy for the security phase.
+	Security kops.AutoApplyPolicy `json:"security"`
+	// Cluster is the policy for the cluster phase.
+	Cluster kops.AutoApplyPolicy `json:"cluster"`
 }
 
 type ServerProviderOptions struct {
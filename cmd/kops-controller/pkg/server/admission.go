@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kops/cmd/kops-controller/pkg/admission"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// defaultWebhookTimeout is how long to wait for the admission webhook if no timeout is configured.
+const defaultWebhookTimeout = 10 * time.Second
+
+// buildAdmitter returns the Admitter for the bootstrap requests that passed verification.
+func (s *Server) buildAdmitter(opt *config.AdmissionOptions) admission.Admitter {
+	var chain admission.Chain
+	if opt == nil {
+		return chain
+	}
+
+	if len(opt.InstanceGroups) != 0 {
+		chain = append(chain, admission.NewInstanceGroupAdmitter(opt.InstanceGroups))
+	}
+	if opt.RequestsPerMinute > 0 {
+		chain = append(chain, admission.NewRateLimitAdmitter(opt.RequestsPerMinute, opt.Burst))
+	}
+	if opt.WebhookURL != "" {
+		timeout := defaultWebhookTimeout
+		if opt.WebhookTimeout != nil {
+			timeout = opt.WebhookTimeout.Duration
+		}
+		chain = append(chain, admission.NewWebhookAdmitter(opt.WebhookURL, timeout))
+	}
+	// The number of nodes is checked last, as admitting a node counts it towards the maximum
+	if len(opt.MaxNodes) != 0 {
+		chain = append(chain, admission.NewMaxNodesAdmitter(opt.MaxNodes, s.listInstanceGroupNodes))
+	}
+
+	return chain
+}
+
+// listInstanceGroupNodes lists the names of the nodes registered in an instance group.
+func (s *Server) listInstanceGroupNodes(ctx context.Context, instanceGroupName string) ([]string, error) {
+	nodes, err := s.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{kops.NodeLabelInstanceGroup: instanceGroupName}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for i := range nodes.Items {
+		names = append(names, nodes.Items[i].Name)
+	}
+	return names, nil
+}
+
+// audit records a bootstrap request and its decision in the audit log.
+func (s *Server) audit(req *admission.Request, decision admission.Decision, reason string) {
+	s.auditLog.Record(&admission.AuditEvent{
+		Request:  *req,
+		Decision: decision,
+		Reason:   reason,
+	})
+}
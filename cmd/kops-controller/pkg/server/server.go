@@ -27,15 +27,20 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime/debug"
+	"sort"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/admission"
 	"k8s.io/kops/cmd/kops-controller/pkg/config"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/rbac"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/vaultstore"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -48,9 +53,16 @@ type Server struct {
 
 	// configBase is the base of the configuration storage.
 	configBase vfs.Path
+
+	// kubeClient is used to count the nodes of instance groups.
+	kubeClient kubernetes.Interface
+	// admitter decides whether verified bootstrap requests are issued certificates.
+	admitter admission.Admitter
+	// auditLog records bootstrap requests and their decisions.
+	auditLog *admission.AuditLog
 }
 
-func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
+func NewServer(opt *config.Options, verifier fi.Verifier, kubeClient kubernetes.Interface) (*Server, error) {
 	server := &http.Server{
 		Addr: opt.Server.Listen,
 		TLSConfig: &tls.Config{
@@ -64,6 +76,8 @@ func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
 		certNames: sets.NewString(opt.Server.CertNames...),
 		server:    server,
 		verifier:  verifier,
+
+		kubeClient: kubeClient,
 	}
 
 	configBase, err := vfs.Context.BuildVfsPath(opt.ConfigBase)
@@ -72,68 +86,130 @@ func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
 	}
 	s.configBase = configBase
 
+	s.auditLog, err = admission.NewAuditLog(opt.Server.AuditLogPath)
+	if err != nil {
+		return nil, err
+	}
+	s.admitter = s.buildAdmitter(opt.Server.Admission)
+
 	r := http.NewServeMux()
 	r.Handle("/bootstrap", http.HandlerFunc(s.bootstrap))
+	r.Handle("/renew", http.HandlerFunc(s.renew))
 	server.Handler = recovery(r)
 
 	return s, nil
 }
 
 func (s *Server) Start() error {
-	var err error
-	s.keystore, err = newKeystore(s.opt.Server.CABasePath, s.opt.Server.SigningCAs)
-	if err != nil {
-		return err
+	if s.opt.Server.KeyStore != "" {
+		keystore, err := vaultstore.NewKeyStore(nil, s.opt.Server.KeyStore)
+		if err != nil {
+			return err
+		}
+		s.keystore = keystore
+	} else {
+		var err error
+		s.keystore, err = newKeystore(s.opt.Server.CABasePath, s.opt.Server.SigningCAs)
+		if err != nil {
+			return err
+		}
 	}
 
 	return s.server.ListenAndServeTLS(s.opt.Server.ServerCertificatePath, s.opt.Server.ServerKeyPath)
 }
 
+// bootstrap issues certificates to a node that is bootstrapping.
 func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
+	s.serveCerts(w, r, "bootstrap")
+}
+
+// renew issues new certificates to a node that has registered, so that it can replace them before they expire.
+func (s *Server) renew(w http.ResponseWriter, r *http.Request) {
+	s.serveCerts(w, r, "renew")
+}
+
+func (s *Server) serveCerts(w http.ResponseWriter, r *http.Request, op string) {
+	renewal := op == "renew"
+
 	if r.Body == nil {
-		klog.Infof("bootstrap %s no body", r.RemoteAddr)
+		klog.Infof("%s %s no body", op, r.RemoteAddr)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		klog.Infof("bootstrap %s read err: %v", r.RemoteAddr, err)
+		klog.Infof("%s %s read err: %v", op, r.RemoteAddr, err)
 		w.WriteHeader(http.StatusBadRequest)
-		_, _ = w.Write([]byte(fmt.Sprintf("bootstrap %s failed to read body: %v", r.RemoteAddr, err)))
+		_, _ = w.Write([]byte(fmt.Sprintf("%s %s failed to read body: %v", op, r.RemoteAddr, err)))
 		return
 	}
 
+	admissionRequest := &admission.Request{
+		RemoteAddr: r.RemoteAddr,
+		Renewal:    renewal,
+	}
+
 	id, err := s.verifier.VerifyToken(r.Header.Get("Authorization"), body)
 	if err != nil {
-		klog.Infof("bootstrap %s verify err: %v", r.RemoteAddr, err)
+		klog.Infof("%s %s verify err: %v", op, r.RemoteAddr, err)
+		s.audit(admissionRequest, admission.DecisionDenied, fmt.Sprintf("failed to verify token: %v", err))
 		w.WriteHeader(http.StatusForbidden)
 		_, _ = w.Write([]byte(fmt.Sprintf("failed to verify token: %v", err)))
 		return
 	}
+	admissionRequest.NodeName = id.NodeName
+	admissionRequest.InstanceGroupName = id.InstanceGroupName
 
 	req := &nodeup.BootstrapRequest{}
 	err = json.Unmarshal(body, req)
 	if err != nil {
-		klog.Infof("bootstrap %s decode err: %v", r.RemoteAddr, err)
+		klog.Infof("%s %s decode err: %v", op, r.RemoteAddr, err)
+		s.audit(admissionRequest, admission.DecisionDenied, fmt.Sprintf("failed to decode: %v", err))
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte(fmt.Sprintf("failed to decode: %v", err)))
 		return
 	}
 
 	if req.APIVersion != nodeup.BootstrapAPIVersion {
-		klog.Infof("bootstrap %s wrong APIVersion", r.RemoteAddr)
+		klog.Infof("%s %s wrong APIVersion", op, r.RemoteAddr)
+		s.audit(admissionRequest, admission.DecisionDenied, "unexpected APIVersion")
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte("unexpected APIVersion"))
 		return
 	}
 
+	if renewal {
+		// Only nodes that have registered renew certificates; new nodes must bootstrap.
+		if _, err := s.kubeClient.CoreV1().Nodes().Get(r.Context(), id.NodeName, metav1.GetOptions{}); err != nil {
+			klog.Infof("renew %s node %q not found: %v", r.RemoteAddr, id.NodeName, err)
+			s.audit(admissionRequest, admission.DecisionDenied, fmt.Sprintf("node is not registered: %v", err))
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(fmt.Sprintf("node %q is not registered", id.NodeName)))
+			return
+		}
+	}
+
+	for name := range req.Certs {
+		admissionRequest.CertNames = append(admissionRequest.CertNames, name)
+	}
+	sort.Strings(admissionRequest.CertNames)
+
+	if err := s.admitter.Admit(r.Context(), admissionRequest); err != nil {
+		klog.Infof("%s %s %s denied: %v", op, r.RemoteAddr, id.NodeName, err)
+		s.audit(admissionRequest, admission.DecisionDenied, err.Error())
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(fmt.Sprintf("%s request denied: %v", op, err)))
+		return
+	}
+	s.audit(admissionRequest, admission.DecisionAdmitted, "")
+
 	resp := &nodeup.BootstrapResponse{
 		Certs: map[string]string{},
 	}
 
 	// Support for nodes that have no access to the state store
-	if req.IncludeNodeConfig {
+	if req.IncludeNodeConfig && !renewal {
 		nodeConfig, err := s.getNodeConfig(r.Context(), req, id)
 		if err != nil {
 			klog.Infof("bootstrap failed to build node config: %v", err)
@@ -152,29 +228,37 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
 
 	for name, pubKey := range req.Certs {
-		cert, err := s.issueCert(name, pubKey, id, validHours)
+		cert, key, err := s.issueCert(name, pubKey, id, validHours)
 		if err != nil {
-			klog.Infof("bootstrap %s cert %q issue err: %v", r.RemoteAddr, name, err)
+			klog.Infof("%s %s cert %q issue err: %v", op, r.RemoteAddr, name, err)
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte(fmt.Sprintf("failed to issue %q: %v", name, err)))
 			return
 		}
 		resp.Certs[name] = cert
+		if key != "" {
+			if resp.Keys == nil {
+				resp.Keys = map[string]string{}
+			}
+			resp.Keys[name] = key
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
-	klog.Infof("bootstrap %s %s success", r.RemoteAddr, id.NodeName)
+	klog.Infof("%s %s %s success", op, r.RemoteAddr, id.NodeName)
 }
 
-func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, validHours uint32) (string, error) {
+// issueCert issues the named certificate for the public key. If the signer is held remotely, it cannot
+// sign a bare public key, so a private key is generated for the certificate and returned with it.
+func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, validHours uint32) (string, string, error) {
 	block, _ := pem.Decode([]byte(pubKey))
 	if block.Type != "RSA PUBLIC KEY" {
-		return "", fmt.Errorf("unexpected key type %q", block.Type)
+		return "", "", fmt.Errorf("unexpected key type %q", block.Type)
 	}
 	key, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return "", fmt.Errorf("parsing key: %v", err)
+		return "", "", fmt.Errorf("parsing key: %v", err)
 	}
 
 	issueReq := &pki.IssueCertRequest{
@@ -185,7 +269,7 @@ func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, vali
 	}
 
 	if !s.certNames.Has(name) {
-		return "", fmt.Errorf("key name not enabled")
+		return "", "", fmt.Errorf("key name not enabled")
 	}
 	switch name {
 	case "etcd-client-cilium":
@@ -213,15 +297,36 @@ func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, vali
 			CommonName: rbac.KubeRouter,
 		}
 	default:
-		return "", fmt.Errorf("unexpected key name")
+		return "", "", fmt.Errorf("unexpected key name")
 	}
 
-	cert, _, _, err := pki.IssueCert(issueReq, s.keystore)
+	if remoteSigner, ok := s.keystore.(pki.RemoteSigner); ok {
+		remote, err := remoteSigner.IsRemoteSigner(issueReq.Signer)
+		if err != nil {
+			return "", "", err
+		}
+		if remote {
+			issueReq.PublicKey = nil
+		}
+	}
+
+	cert, privateKey, _, err := pki.IssueCert(issueReq, s.keystore)
 	if err != nil {
-		return "", fmt.Errorf("issuing certificate: %v", err)
+		return "", "", fmt.Errorf("issuing certificate: %v", err)
 	}
 
-	return cert.AsString()
+	certData, err := cert.AsString()
+	if err != nil {
+		return "", "", err
+	}
+	if issueReq.PublicKey != nil {
+		return certData, "", nil
+	}
+	keyData, err := privateKey.AsString()
+	if err != nil {
+		return "", "", err
+	}
+	return certData, keyData, nil
 }
 
 // recovery is responsible for ensuring we don't exit on a panic.
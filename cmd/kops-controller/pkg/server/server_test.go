@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// remoteKeystore holds the kubernetes-ca remotely, signing certificate requests like the Vault PKI secrets engine.
+type remoteKeystore struct {
+	t    *testing.T
+	cert *pki.Certificate
+	key  *pki.PrivateKey
+}
+
+var _ pki.RemoteSigner = &remoteKeystore{}
+
+func (k *remoteKeystore) FindPrimaryKeypair(name string) (*pki.Certificate, *pki.PrivateKey, error) {
+	k.t.Errorf("FindPrimaryKeypair called for remote signer %q", name)
+	return nil, nil, nil
+}
+
+func (k *remoteKeystore) IsRemoteSigner(name string) (bool, error) {
+	return name == fi.CertificateIDCA, nil
+}
+
+func (k *remoteKeystore) SignCertificateRequest(name string, csr *x509.CertificateRequest, template *x509.Certificate) (*pki.Certificate, *pki.Certificate, error) {
+	signed := *template
+	signed.Subject = csr.Subject
+	signed.DNSNames = csr.DNSNames
+	signed.SerialNumber = big.NewInt(1)
+	signed.NotBefore = time.Now()
+	der, err := x509.CreateCertificate(rand.Reader, &signed, k.cert.Certificate, csr.PublicKey, k.key.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := pki.ParsePEMCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, k.cert, nil
+}
+
+func TestIssueCertRemoteSigner(t *testing.T) {
+	caCertificate, caPrivateKey, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+	}, nil)
+	require.NoError(t, err)
+
+	s := &Server{
+		certNames: sets.NewString("kubelet"),
+		keystore: &remoteKeystore{
+			t:    t,
+			cert: caCertificate,
+			key:  caPrivateKey,
+		},
+	}
+
+	nodeKey, err := pki.GeneratePrivateKey()
+	require.NoError(t, err)
+	pkData, err := x509.MarshalPKIXPublicKey(nodeKey.Key.Public())
+	require.NoError(t, err)
+	pubKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkData}))
+
+	certData, keyData, err := s.issueCert("kubelet", pubKey, &fi.VerifyResult{NodeName: "node-1"}, 24)
+	require.NoError(t, err)
+	require.NotEmpty(t, keyData, "a private key is returned for a remote signer")
+
+	cert, err := pki.ParsePEMCertificate([]byte(certData))
+	require.NoError(t, err)
+	key, err := pki.ParsePEMPrivateKey([]byte(keyData))
+	require.NoError(t, err)
+
+	assert.NoError(t, cert.Certificate.CheckSignatureFrom(caCertificate.Certificate), "check signature")
+	assert.Equal(t, "system:node:node-1", cert.Subject.CommonName, "Subject")
+	assert.Equal(t, []string{"system:nodes"}, cert.Subject.Organization, "Organization")
+	assert.Equal(t, key.Key.Public(), cert.PublicKey, "certificate is for the returned key")
+}
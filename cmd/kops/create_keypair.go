@@ -83,6 +83,7 @@ var rotatableKeysets = sets.NewString(
 	"etcd-clients-ca-cilium",
 	"kubernetes-ca",
 	"service-account",
+	"users-ca",
 )
 
 func rotatableKeysetFilter(name string, _ *fi.Keyset) bool {
@@ -265,7 +265,7 @@ func RunDeleteInstance(ctx context.Context, f *util.Factory, out io.Writer, opti
 
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
-		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
+		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient, clientset)
 		if err != nil {
 			return fmt.Errorf("cannot create cluster validator: %v", err)
 		}
@@ -50,11 +50,20 @@ var (
 
 	# export using the internal DNS name, bypassing the cloud load balancer
 	kops export kubecfg k8s-cluster.example.com --internal
+
+	# export a user that authenticates with the cluster's OpenID Connect identity provider
+	kops export kubecfg k8s-cluster.example.com --auth=oidc
+
+	# export a short-lived user certificate for alice in the developers group, signed by the users CA
+	kops export kubecfg k8s-cluster.example.com --identity alice --groups developers --lifetime 8h
 		`))
 
 	exportKubecfgShort = i18n.T(`Export kubecfg.`)
 )
 
+// exportKubecfgAuthOIDC is the --auth mode that obtains tokens from the cluster's OpenID Connect identity provider.
+const exportKubecfgAuthOIDC = "oidc"
+
 type ExportKubecfgOptions struct {
 	KubeConfigPath string
 	all            bool
@@ -64,10 +73,22 @@ type ExportKubecfgOptions struct {
 
 	// UseKopsAuthenticationPlugin controls whether we should use the kOps auth helper instead of a static credential
 	UseKopsAuthenticationPlugin bool
+
+	// Auth is the authentication mode of the exported user; "oidc" obtains tokens from the cluster's OpenID Connect identity provider
+	Auth string
+
+	// Identity is the name of the user a certificate signed by the users CA is issued to
+	Identity string
+	// Groups are the groups of the user certificate
+	Groups []string
+	// Lifetime is the validity of the user certificate
+	Lifetime time.Duration
 }
 
 func NewCmdExportKubecfg(f *util.Factory, out io.Writer) *cobra.Command {
-	options := &ExportKubecfgOptions{}
+	options := &ExportKubecfgOptions{
+		Lifetime: kubeconfig.DefaultKubecfgUserLifetime,
+	}
 
 	cmd := &cobra.Command{
 		Use:     "kubecfg CLUSTERNAME",
@@ -90,6 +111,13 @@ func NewCmdExportKubecfg(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.user, "user", options.user, "re-use an existing user in kubeconfig.  Value must specify an existing user block in your kubeconfig file.")
 	cmd.Flags().BoolVar(&options.internal, "internal", options.internal, "use the cluster's internal DNS name")
 	cmd.Flags().BoolVar(&options.UseKopsAuthenticationPlugin, "auth-plugin", options.UseKopsAuthenticationPlugin, "use the kOps authentication plugin")
+	cmd.Flags().StringVar(&options.Auth, "auth", options.Auth, "authenticate the user with the given mode. Supported values: oidc")
+	cmd.RegisterFlagCompletionFunc("auth", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{exportKubecfgAuthOIDC}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.Identity, "identity", options.Identity, "export a user certificate for the given identity, signed by the cluster's users CA")
+	cmd.Flags().StringSliceVar(&options.Groups, "groups", options.Groups, "groups of the user certificate exported with --identity")
+	cmd.Flags().DurationVar(&options.Lifetime, "lifetime", options.Lifetime, "lifetime of the user certificate exported with --identity")
 
 	return cmd
 }
@@ -107,6 +135,28 @@ func RunExportKubecfg(ctx context.Context, f *util.Factory, out io.Writer, optio
 	if options.admin != 0 && options.user != "" {
 		return fmt.Errorf("cannot use both --admin and --user")
 	}
+	switch options.Auth {
+	case "":
+	case exportKubecfgAuthOIDC:
+		if options.admin != 0 || options.user != "" || options.UseKopsAuthenticationPlugin {
+			return fmt.Errorf("cannot use --auth with --admin, --user or --auth-plugin")
+		}
+	default:
+		return fmt.Errorf("unsupported --auth value %q; supported values: %s", options.Auth, exportKubecfgAuthOIDC)
+	}
+	var userCertificate *kubeconfig.UserCertificate
+	if options.Identity != "" {
+		if options.admin != 0 || options.user != "" || options.UseKopsAuthenticationPlugin || options.Auth != "" {
+			return fmt.Errorf("cannot use --identity with --admin, --user, --auth-plugin or --auth")
+		}
+		userCertificate = &kubeconfig.UserCertificate{
+			Identity: options.Identity,
+			Groups:   options.Groups,
+			Lifetime: options.Lifetime,
+		}
+	} else if len(options.Groups) != 0 {
+		return fmt.Errorf("--groups requires --identity")
+	}
 
 	var clusterList []*kopsapi.Cluster
 	if options.all {
@@ -144,16 +194,15 @@ func RunExportKubecfg(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
-		conf, err := kubeconfig.BuildKubecfg(
-			cluster,
-			keyStore,
-			secretStore,
-			cloud,
-			options.admin,
-			options.user,
-			options.internal,
-			f.KopsStateStore(),
-			options.UseKopsAuthenticationPlugin)
+		conf, err := kubeconfig.BuildKubecfg(cluster, keyStore, secretStore, cloud, kubeconfig.BuildKubecfgOptions{
+			Admin:                       options.admin,
+			User:                        options.user,
+			Internal:                    options.internal,
+			KopsStateStore:              f.KopsStateStore(),
+			UseKopsAuthenticationPlugin: options.UseKopsAuthenticationPlugin,
+			UseOIDCAuthentication:       options.Auth == exportKubecfgAuthOIDC,
+			UserCertificate:             userCertificate,
+		})
 		if err != nil {
 			return err
 		}
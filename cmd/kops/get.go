@@ -68,6 +68,7 @@ type GetOptions struct {
 const (
 	OutputYaml  = "yaml"
 	OutputTable = "table"
+	OutputWide  = "wide"
 	OutputJSON  = "json"
 )
 
@@ -104,11 +105,14 @@ func NewCmdGet(f *util.Factory, out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, "output format.  One of: table, yaml, json")
+	addOutputFlag(cmd, options)
 
 	// create subcommands
 	cmd.AddCommand(NewCmdGetAssets(f, out, options))
+	cmd.AddCommand(NewCmdGetCertificates(f, out, options))
 	cmd.AddCommand(NewCmdGetCluster(f, out, options))
+	cmd.AddCommand(NewCmdGetDrift(f, out, options))
+	cmd.AddCommand(NewCmdGetHistory(f, out, options))
 	cmd.AddCommand(NewCmdGetInstanceGroups(f, out, options))
 	cmd.AddCommand(NewCmdGetKeypairs(f, out, options))
 	cmd.AddCommand(NewCmdGetSecrets(f, out, options))
@@ -117,6 +121,16 @@ func NewCmdGet(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
+// addOutputFlag declares the output flag of a get command.
+func addOutputFlag(cmd *cobra.Command, options *GetOptions) {
+	cmd.Flags().StringVarP(&options.output, "output", "o", options.output, "output format.  One of: table, yaml, json")
+}
+
+// addWideOutputFlag declares the output flag of a get command that also implements wide output.
+func addWideOutputFlag(cmd *cobra.Command, options *GetOptions) {
+	cmd.Flags().StringVarP(&options.output, "output", "o", options.output, "output format.  One of: table, wide, yaml, json")
+}
+
 func RunGet(ctx context.Context, f commandutils.Factory, out io.Writer, options *GetOptions) error {
 
 	client, err := f.Clientset()
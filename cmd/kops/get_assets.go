@@ -91,6 +91,7 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 		},
 	}
 
+	addOutputFlag(cmd, getOptions)
 	cmd.Flags().BoolVar(&options.Copy, "copy", options.Copy, "copy assets to local repository")
 
 	return cmd
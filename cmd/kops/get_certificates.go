@@ -0,0 +1,244 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/certinventory"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getCertificatesLong = templates.LongDesc(i18n.T(`
+	Display the certificates of a cluster and when they expire.
+
+	The certificates of all keysets in the keystore are listed, including
+	distrusted ones. Unless keysets are named or --nodes=false is specified,
+	the certificates nodeup issued on each node are listed as well. Those are
+	kept on the nodes only, so their expiry is only estimated from when the
+	node registered, and does not account for renewals.
+
+	If --expiring-within is specified, the command exits with status 2 if any
+	trusted certificate expires within that duration.`))
+
+	getCertificatesExample = templates.Examples(i18n.T(`
+	# Display the certificates of a cluster.
+	kops get certificates --name k8s-cluster.example.com
+
+	# Display the certificates of the kubernetes-ca keyset.
+	kops get certificates kubernetes-ca --name k8s-cluster.example.com
+
+	# Fail if any certificate expires within 30 days, e.g. in a scheduled job.
+	kops get certificates --name k8s-cluster.example.com --expiring-within 720h
+	`))
+
+	getCertificatesShort = i18n.T(`Display the certificates of a cluster and when they expire.`)
+)
+
+type GetCertificatesOptions struct {
+	*GetOptions
+
+	// Nodes includes the certificates issued by nodeup on the nodes.
+	Nodes bool
+	// ExpiringWithin is the duration within which no trusted certificate may expire, or 0 to not check.
+	ExpiringWithin time.Duration
+}
+
+func NewCmdGetCertificates(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := &GetCertificatesOptions{
+		GetOptions: getOptions,
+		Nodes:      true,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "certificates [KEYSET]...",
+		Aliases: []string{"certificate", "certs"},
+		Short:   getCertificatesShort,
+		Long:    getCertificatesLong,
+		Example: getCertificatesExample,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeGetKeypairs(&GetKeypairsOptions{GetOptions: getOptions}, args, toComplete)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			expiring, err := RunGetCertificates(ctx, f, out, options, args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			// Like validate, we exit non-zero if certificates are expiring, even though there was no error.
+			if len(expiring) != 0 {
+				os.Exit(2)
+			}
+		},
+	}
+
+	addOutputFlag(cmd, getOptions)
+	cmd.Flags().BoolVar(&options.Nodes, "nodes", options.Nodes, "Include the certificates issued by nodeup on the nodes")
+	cmd.Flags().DurationVar(&options.ExpiringWithin, "expiring-within", options.ExpiringWithin, "Exit with status 2 if any trusted certificate expires within this duration")
+
+	return cmd
+}
+
+// RunGetCertificates displays the certificates of a cluster.
+// It returns the certificates that expire within options.ExpiringWithin, if set.
+func RunGetCertificates(ctx context.Context, f *util.Factory, out io.Writer, options *GetCertificatesOptions, args []string) ([]*certinventory.Certificate, error) {
+	clusterName := rootCommand.ClusterName(true)
+	options.clusterName = clusterName
+	if clusterName == "" {
+		return nil, fmt.Errorf("--name is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets: %v", err)
+	}
+	if len(args) != 0 {
+		selected := make(map[string]*fi.Keyset)
+		for _, name := range args {
+			keyset := keysets[name]
+			if keyset == nil {
+				return nil, fmt.Errorf("keyset %q not found", name)
+			}
+			selected[name] = keyset
+		}
+		keysets = selected
+	}
+
+	certificates := certinventory.FromKeysets(keysets)
+
+	if options.Nodes && len(args) == 0 {
+		k8sClient, err := createK8sClient(cluster)
+		if err != nil {
+			klog.Warningf("cannot list the certificates issued on nodes: %v", err)
+		} else {
+			nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				klog.Warningf("cannot list the certificates issued on nodes. Kubernetes API unavailable: %v", err)
+			} else {
+				certificates = append(certificates, certinventory.FromNodes(nodeList.Items)...)
+			}
+		}
+	}
+
+	var expiring []*certinventory.Certificate
+	if options.ExpiringWithin != 0 {
+		expiring = certinventory.Expiring(certificates, time.Now().Add(options.ExpiringWithin))
+	}
+
+	switch options.output {
+	case OutputTable:
+		if len(certificates) == 0 {
+			return nil, fmt.Errorf("no certificates found")
+		}
+		t := &tables.Table{}
+		t.AddColumn("SOURCE", func(c *certinventory.Certificate) string {
+			return string(c.Source)
+		})
+		t.AddColumn("NAME", func(c *certinventory.Certificate) string {
+			return c.Name
+		})
+		t.AddColumn("SUBJECT", func(c *certinventory.Certificate) string {
+			return c.Subject
+		})
+		t.AddColumn("ALTERNATENAMES", func(c *certinventory.Certificate) string {
+			return strings.Join(c.AlternateNames, ",")
+		})
+		t.AddColumn("ISSUER", func(c *certinventory.Certificate) string {
+			return c.Issuer
+		})
+		t.AddColumn("SERIAL", func(c *certinventory.Certificate) string {
+			return c.Serial
+		})
+		t.AddColumn("EXPIRES", func(c *certinventory.Certificate) string {
+			return c.NotAfter.Local().Format("2006-01-02")
+		})
+		t.AddColumn("STATE", func(c *certinventory.Certificate) string {
+			switch {
+			case c.Estimated:
+				return "estimated"
+			case c.Distrusted:
+				return "distrusted"
+			case c.Primary:
+				return "primary"
+			default:
+				return "trusted"
+			}
+		})
+		if err := t.Render(certificates, out, "SOURCE", "NAME", "SUBJECT", "ALTERNATENAMES", "ISSUER", "SERIAL", "EXPIRES", "STATE"); err != nil {
+			return nil, err
+		}
+		if options.ExpiringWithin != 0 {
+			if len(expiring) == 0 {
+				fmt.Fprintf(out, "\nNo trusted certificates expire within %v\n", options.ExpiringWithin)
+			} else {
+				fmt.Fprintf(out, "\n%d trusted certificates expire within %v\n", len(expiring), options.ExpiringWithin)
+			}
+		}
+	case OutputYaml:
+		y, err := yaml.Marshal(certificates)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(certificates)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", options.output)
+	}
+
+	return expiring, nil
+}
@@ -25,14 +25,17 @@ import (
 
 	"github.com/spf13/cobra"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -49,6 +52,9 @@ var (
 	# Get a cluster
 	kops get cluster k8s-cluster.example.com
 
+	# Get whether a cluster is up to date with its spec, and the outcome of the last rolling update
+	kops get cluster k8s-cluster.example.com -o wide
+
 	# Get a cluster YAML desired configuration
 	kops get cluster k8s-cluster.example.com -o yaml
 
@@ -116,6 +122,7 @@ func NewCmdGetCluster(f *util.Factory, out io.Writer, getOptions *GetOptions) *c
 		},
 	}
 
+	addWideOutputFlag(cmd, options.GetOptions)
 	cmd.Flags().BoolVar(&options.FullSpec, "full", options.FullSpec, "Show fully populated configuration")
 
 	return cmd
@@ -168,7 +175,7 @@ func RunGetClusters(ctx context.Context, f commandutils.Factory, out io.Writer,
 	}
 
 	var obj []runtime.Object
-	if options.output != OutputTable {
+	if options.output != OutputTable && options.output != OutputWide {
 		for _, c := range clusters {
 			obj = append(obj, c)
 		}
@@ -177,6 +184,8 @@ func RunGetClusters(ctx context.Context, f commandutils.Factory, out io.Writer,
 	switch options.output {
 	case OutputTable:
 		return clusterOutputTable(clusters, out)
+	case OutputWide:
+		return clusterOutputWideTable(ctx, client, clusters, out)
 	case OutputYaml:
 		return fullOutputYAML(out, obj...)
 	case OutputJSON:
@@ -211,6 +220,45 @@ func filterClustersByName(clusterNames []string, clusters []*kopsapi.Cluster) ([
 }
 
 func clusterOutputTable(clusters []*kopsapi.Cluster, out io.Writer) error {
+	t := clusterTable()
+	return t.Render(clusters, out, "NAME", "CLOUD", "ZONES")
+}
+
+// clusterOutputWideTable also outputs the status recorded when the clusters were last applied and rolling-updated.
+func clusterOutputWideTable(ctx context.Context, clientset simple.Clientset, clusters []*kopsapi.Cluster, out io.Writer) error {
+	statuses := make(map[string]*simple.RecordedStatus)
+	applied := make(map[string]string)
+	for _, cluster := range clusters {
+		status, err := clientset.GetRecordedStatus(ctx, cluster)
+		if err != nil {
+			return fmt.Errorf("error reading status of cluster %q: %v", cluster.ObjectMeta.Name, err)
+		}
+		statuses[cluster.ObjectMeta.Name] = status
+
+		applied[cluster.ObjectMeta.Name], err = clusterApplied(ctx, clientset, cluster, status)
+		if err != nil {
+			return err
+		}
+	}
+
+	t := clusterTable()
+	t.AddColumn("APPLIED", func(c *kopsapi.Cluster) string {
+		return applied[c.ObjectMeta.Name]
+	})
+	t.AddColumn("KOPSVERSION", func(c *kopsapi.Cluster) string {
+		if v := statuses[c.ObjectMeta.Name].Cluster.AppliedKopsVersion; v != "" {
+			return v
+		}
+		return "-"
+	})
+	t.AddColumn("ROLLINGUPDATE", func(c *kopsapi.Cluster) string {
+		return rollingUpdateOutcome(statuses[c.ObjectMeta.Name].Cluster.Conditions, kopsapi.ClusterConditionRollingUpdateSucceeded)
+	})
+
+	return t.Render(clusters, out, "NAME", "CLOUD", "ZONES", "APPLIED", "KOPSVERSION", "ROLLINGUPDATE")
+}
+
+func clusterTable() *tables.Table {
 	t := &tables.Table{}
 	t.AddColumn("NAME", func(c *kopsapi.Cluster) string {
 		return c.ObjectMeta.Name
@@ -227,8 +275,50 @@ func clusterOutputTable(clusters []*kopsapi.Cluster, out io.Writer) error {
 		}
 		return strings.Join(zones.List(), ",")
 	})
+	return t
+}
 
-	return t.Render(clusters, out, "NAME", "CLOUD", "ZONES")
+// clusterApplied returns whether the current specs of the cluster were the last ones applied in full.
+func clusterApplied(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster, status *simple.RecordedStatus) (string, error) {
+	if status.Cluster.AppliedSpecHash == "" {
+		return "-", nil
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	var instanceGroups []*kopsapi.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	specHash, err := cloudup.SpecHash(cluster, instanceGroups)
+	if err != nil {
+		return "", err
+	}
+	if specHash == status.Cluster.AppliedSpecHash {
+		return "Yes", nil
+	}
+	return "No", nil
+}
+
+// rollingUpdateOutcome summarizes the condition recording the outcome of the last rolling update.
+func rollingUpdateOutcome(conditions []metav1.Condition, conditionType string) string {
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil {
+		return "-"
+	}
+	switch condition.Reason {
+	case kopsapi.RollingUpdateReasonSucceeded:
+		return "Succeeded"
+	case kopsapi.RollingUpdateReasonFailed:
+		return "Failed"
+	case kopsapi.RollingUpdateReasonStopped:
+		return "Stopped"
+	default:
+		return condition.Reason
+	}
 }
 
 // fullOutputJson outputs the marshalled JSON of a list of clusters and instance groups.  It will handle
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/drift"
+	resourceops "k8s.io/kops/pkg/resources/ops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+func NewCmdGetDrift(f *util.Factory, out io.Writer, options *GetOptions) *cobra.Command {
+	getDriftShort := i18n.T(`Display cloud resources that have drifted from the cluster model.`)
+
+	getDriftLong := templates.LongDesc(i18n.T(`
+	Display cloud resources that have drifted from the cluster model.
+
+	The cloud resources are compared with the model built from the cluster and
+	instance group definitions, without making any changes. Resources whose
+	fields differ from the model, resources of the model that are missing, and
+	resources tagged for the cluster that are not part of the model are reported.
+
+	The command exits with status 2 if drift was found.`))
+
+	getDriftExample := templates.Examples(i18n.T(`
+	# Display the drift of a cluster.
+	kops get drift --name k8s-cluster.example.com
+
+	# Display the drift as JSON, e.g. for a scheduled job.
+	kops get drift --name k8s-cluster.example.com -o json
+	`))
+
+	cmd := &cobra.Command{
+		Use:     "drift",
+		Short:   getDriftShort,
+		Long:    getDriftLong,
+		Example: getDriftExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			found, err := RunGetDrift(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			// Like validate, we exit non-zero if drift was found, even though there was no error.
+			if len(found) != 0 {
+				os.Exit(2)
+			}
+		},
+	}
+
+	addOutputFlag(cmd, options)
+
+	return cmd
+}
+
+func RunGetDrift(ctx context.Context, f *util.Factory, out io.Writer, options *GetOptions) ([]*drift.Drift, error) {
+	clusterName := rootCommand.ClusterName(true)
+	options.clusterName = clusterName
+	if clusterName == "" {
+		return nil, fmt.Errorf("--name is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// A dry run only records the differences between the model and the cloud
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:      cloud,
+		Clientset:  clientset,
+		Cluster:    cluster,
+		DryRun:     true,
+		DryRunOut:  io.Discard,
+		TargetName: cloudup.TargetDryRun,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return nil, err
+	}
+
+	target := applyCmd.Target.(*fi.DryRunTarget)
+	report, err := target.Report(applyCmd.TaskMap)
+	if err != nil {
+		return nil, err
+	}
+	found := drift.FromDryRunReport(report)
+
+	clusterResources, err := resourceops.ListResources(cloud, cluster, cloud.Region())
+	if err != nil {
+		return nil, fmt.Errorf("error listing cluster resources: %v", err)
+	}
+	found = append(found, drift.FindUnowned(cluster.ObjectMeta.Name, applyCmd.TaskMap, clusterResources)...)
+
+	switch options.output {
+	case OutputTable:
+		if len(found) == 0 {
+			fmt.Fprintf(out, "No drift found\n")
+			return nil, nil
+		}
+		t := &tables.Table{}
+		t.AddColumn("KIND", func(d *drift.Drift) string {
+			return string(d.Kind)
+		})
+		t.AddColumn("TYPE", func(d *drift.Drift) string {
+			return d.Type
+		})
+		t.AddColumn("NAME", func(d *drift.Drift) string {
+			return d.Name
+		})
+		t.AddColumn("ID", func(d *drift.Drift) string {
+			return d.ID
+		})
+		t.AddColumn("FIELDS", func(d *drift.Drift) string {
+			var fields []string
+			for _, field := range d.Fields {
+				fields = append(fields, field.Name)
+			}
+			return strings.Join(fields, ",")
+		})
+		if err := t.Render(found, out, "KIND", "TYPE", "NAME", "ID", "FIELDS"); err != nil {
+			return nil, err
+		}
+	case OutputYaml:
+		y, err := yaml.Marshal(found)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(found)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", options.output)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getHistoryClusterLong = templates.LongDesc(i18n.T(`
+	Display the revisions of a cluster and its instance groups.
+
+	A revision is recorded in the state store every time the cluster or one of
+	its instance groups is written, with the author, the time and the difference
+	from the previous version. Use -o yaml to see the differences.`))
+
+	getHistoryClusterExample = templates.Examples(i18n.T(`
+	# Display the revisions of a cluster
+	kops get history cluster k8s-cluster.example.com
+
+	# Display the revisions with their differences
+	kops get history cluster k8s-cluster.example.com -o yaml
+	`))
+
+	getHistoryClusterShort = i18n.T(`Display the revisions of a cluster.`)
+)
+
+type GetHistoryClusterOptions struct {
+	*GetOptions
+	ClusterName string
+}
+
+func NewCmdGetHistory(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: i18n.T(`Display the revisions of resources.`),
+	}
+
+	cmd.AddCommand(NewCmdGetHistoryCluster(f, out, getOptions))
+
+	return cmd
+}
+
+func NewCmdGetHistoryCluster(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := &GetHistoryClusterOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             getHistoryClusterShort,
+		Long:              getHistoryClusterLong,
+		Example:           getHistoryClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetHistoryCluster(context.TODO(), f, out, options)
+		},
+	}
+
+	addOutputFlag(cmd, getOptions)
+
+	return cmd
+}
+
+func RunGetHistoryCluster(ctx context.Context, f *util.Factory, out io.Writer, options *GetHistoryClusterOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	revisions, err := clientset.ListClusterRevisions(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	switch options.output {
+	case OutputTable:
+		if len(revisions) == 0 {
+			fmt.Fprintf(out, "No revisions found\n")
+			return nil
+		}
+		t := &tables.Table{}
+		t.AddColumn("REVISION", func(r *simple.ClusterRevision) string {
+			return strconv.Itoa(r.Revision)
+		})
+		t.AddColumn("TIMESTAMP", func(r *simple.ClusterRevision) string {
+			return r.Timestamp.Format(time.RFC3339)
+		})
+		t.AddColumn("AUTHOR", func(r *simple.ClusterRevision) string {
+			return r.Author
+		})
+		t.AddColumn("KIND", func(r *simple.ClusterRevision) string {
+			return r.Kind
+		})
+		t.AddColumn("NAME", func(r *simple.ClusterRevision) string {
+			return r.Name
+		})
+		return t.Render(revisions, out, "REVISION", "TIMESTAMP", "AUTHOR", "KIND", "NAME")
+	case OutputYaml:
+		y, err := yaml.Marshal(revisions)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(revisions)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.output)
+	}
+	return nil
+}
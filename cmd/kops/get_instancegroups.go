@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/formatter"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -45,6 +46,9 @@ var (
 	# Get a cluster's instancegroup
 	kops get ig --name k8s-cluster.example.com nodes
 
+	# Get how many instances of a cluster's instancegroups need updating
+	kops get ig --name k8s-cluster.example.com -o wide
+
 	# Save a cluster's instancegroups desired configuration to YAML file
 	kops get ig --name k8s-cluster.example.com -o yaml > instancegroups-desired-config.yaml
 	`))
@@ -76,6 +80,8 @@ func NewCmdGetInstanceGroups(f *util.Factory, out io.Writer, getOptions *GetOpti
 		},
 	}
 
+	addWideOutputFlag(cmd, options.GetOptions)
+
 	return cmd
 }
 
@@ -116,7 +122,7 @@ func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions
 	}
 
 	var obj []runtime.Object
-	if options.output != OutputTable {
+	if options.output != OutputTable && options.output != OutputWide {
 		for _, c := range instancegroups {
 			obj = append(obj, c)
 		}
@@ -125,6 +131,8 @@ func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions
 	switch options.output {
 	case OutputTable:
 		return igOutputTable(cluster, instancegroups, out)
+	case OutputWide:
+		return igOutputWideTable(ctx, clientset, cluster, instancegroups, out)
 	case OutputYaml:
 		return fullOutputYAML(out, obj...)
 	case OutputJSON:
@@ -162,6 +170,41 @@ func filterInstanceGroupsByName(instanceGroupNames []string, list []api.Instance
 }
 
 func igOutputTable(cluster *api.Cluster, instancegroups []*api.InstanceGroup, out io.Writer) error {
+	t := igTable(cluster)
+	// SUBNETS is not selected by default - not as useful as ZONES
+	return t.Render(instancegroups, os.Stdout, "NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES")
+}
+
+// igOutputWideTable also outputs the status recorded when the instance groups were last rolling-updated.
+func igOutputWideTable(ctx context.Context, clientset simple.Clientset, cluster *api.Cluster, instancegroups []*api.InstanceGroup, out io.Writer) error {
+	status, err := clientset.GetRecordedStatus(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("error reading status of cluster %q: %v", cluster.ObjectMeta.Name, err)
+	}
+
+	t := igTable(cluster)
+	t.AddColumn("INSTANCES", func(c *api.InstanceGroup) string {
+		if igStatus := status.InstanceGroups[c.ObjectMeta.Name]; igStatus != nil {
+			return strconv.Itoa(int(igStatus.Instances))
+		}
+		return "-"
+	})
+	t.AddColumn("NEEDUPDATE", func(c *api.InstanceGroup) string {
+		if igStatus := status.InstanceGroups[c.ObjectMeta.Name]; igStatus != nil {
+			return strconv.Itoa(int(igStatus.NeedUpdate))
+		}
+		return "-"
+	})
+	t.AddColumn("ROLLINGUPDATE", func(c *api.InstanceGroup) string {
+		if igStatus := status.InstanceGroups[c.ObjectMeta.Name]; igStatus != nil {
+			return rollingUpdateOutcome(igStatus.Conditions, api.InstanceGroupConditionInstancesUpToDate)
+		}
+		return "-"
+	})
+	return t.Render(instancegroups, out, "NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES", "INSTANCES", "NEEDUPDATE", "ROLLINGUPDATE")
+}
+
+func igTable(cluster *api.Cluster) *tables.Table {
 	t := &tables.Table{}
 	t.AddColumn("NAME", func(c *api.InstanceGroup) string {
 		return c.ObjectMeta.Name
@@ -180,8 +223,7 @@ func igOutputTable(cluster *api.Cluster, instancegroups []*api.InstanceGroup, ou
 	t.AddColumn("MAX", func(c *api.InstanceGroup) string {
 		return int32PointerToString(c.Spec.MaxSize)
 	})
-	// SUBNETS is not selected by default - not as useful as ZONES
-	return t.Render(instancegroups, os.Stdout, "NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES")
+	return t
 }
 
 func int32PointerToString(v *int32) string {
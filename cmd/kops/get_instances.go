@@ -73,6 +73,8 @@ func NewCmdGetInstances(f *util.Factory, out io.Writer, options *GetOptions) *co
 		},
 	}
 
+	addOutputFlag(cmd, options)
+
 	return cmd
 }
 
@@ -67,6 +67,7 @@ func NewCmdGetKeypairs(f *util.Factory, out io.Writer, getOptions *GetOptions) *
 		},
 	}
 
+	addOutputFlag(cmd, getOptions)
 	cmd.Flags().BoolVar(&options.Distrusted, "distrusted", options.Distrusted, "Include distrusted keypairs")
 
 	return cmd
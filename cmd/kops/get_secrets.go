@@ -76,6 +76,7 @@ func NewCmdGetSecrets(f *util.Factory, out io.Writer, getOptions *GetOptions) *c
 		},
 	}
 
+	addOutputFlag(cmd, getOptions)
 	cmd.Flags().StringVarP(&options.Type, "type", "", "", "Filter by secret type")
 	return cmd
 }
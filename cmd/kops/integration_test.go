@@ -175,6 +175,7 @@ func (i *integrationTest) withAddons(addons ...string) *integrationTest {
 func TestMinimal(t *testing.T) {
 	newIntegrationTest("minimal.example.com", "minimal").runTestTerraformAWS(t)
 	newIntegrationTest("minimal.example.com", "minimal").runTestCloudformation(t)
+	newIntegrationTest("minimal.example.com", "minimal").runTestPulumi(t)
 }
 
 // TestMinimal runs the test on a minimum gossip configuration
@@ -1096,6 +1097,109 @@ func (i *integrationTest) runTestCloudformation(t *testing.T) {
 	}
 }
 
+func (i *integrationTest) runTestPulumi(t *testing.T) {
+	ctx := context.Background()
+
+	i.srcDir = updateClusterTestBase + i.srcDir
+	var stdout bytes.Buffer
+
+	inputYAML := "in-" + i.version + ".yaml"
+	expectedPulumiPath := "pulumi.yaml"
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	h.SetupMockAWS()
+
+	factory := i.setupCluster(t, inputYAML, ctx, stdout)
+
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.Target = "pulumi"
+		options.OutDir = path.Join(h.TempDir, "out")
+		options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
+
+		// We don't test it here, and it adds a dependency on kubectl
+		options.CreateKubecfg = false
+		options.ClusterName = i.clusterName
+		options.LifecycleOverrides = i.lifecycleOverrides
+
+		_, err := RunUpdateCluster(ctx, factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running update cluster %q: %v", i.clusterName, err)
+		}
+	}
+
+	// Compare main files
+	{
+		files, err := ioutil.ReadDir(path.Join(h.TempDir, "out"))
+		if err != nil {
+			t.Fatalf("failed to read dir: %v", err)
+		}
+
+		var fileNames []string
+		for _, f := range files {
+			fileNames = append(fileNames, f.Name())
+		}
+		sort.Strings(fileNames)
+
+		actualFilenames := strings.Join(fileNames, ",")
+		expectedFilenames := "Pulumi.yaml"
+		if actualFilenames != expectedFilenames {
+			t.Fatalf("unexpected files.  actual=%q, expected=%q", actualFilenames, expectedFilenames)
+		}
+
+		actualPath := path.Join(h.TempDir, "out", "Pulumi.yaml")
+		actualProgram, err := ioutil.ReadFile(actualPath)
+		if err != nil {
+			t.Fatalf("unexpected error reading actual pulumi output: %v", err)
+		}
+
+		// Expand out the userData base64 blob, as otherwise testing is painful
+		var program struct {
+			Name        string                            `json:"name"`
+			Runtime     string                            `json:"runtime"`
+			Description string                            `json:"description,omitempty"`
+			Resources   map[string]map[string]interface{} `json:"resources"`
+		}
+		if err := yaml.Unmarshal(actualProgram, &program); err != nil {
+			t.Fatalf("unexpected error parsing pulumi output: %v", err)
+		}
+
+		extracted := make(map[string]string)
+		for name, resource := range program.Resources {
+			properties, ok := resource["properties"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if s, ok := properties["userData"].(string); ok {
+				vBytes, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					t.Fatalf("error decoding userData: %v", err)
+				}
+				// Strip carriage return as expectedValue is stored in a yaml string literal
+				// and yaml block quoting doesn't seem to support \r in a string
+				extracted["resources."+name+".properties.userData"] = strings.Replace(string(vBytes), "\r", "", -1)
+				properties["userData"] = "extracted"
+			}
+		}
+
+		actualProgram, err = yaml.Marshal(program)
+		if err != nil {
+			t.Fatalf("error serializing yaml: %v", err)
+		}
+		golden.AssertMatchesFile(t, string(actualProgram), path.Join(i.srcDir, expectedPulumiPath))
+
+		actualExtracted, err := yaml.Marshal(extracted)
+		if err != nil {
+			t.Fatalf("error serializing yaml: %v", err)
+		}
+		golden.AssertMatchesFile(t, string(actualExtracted), path.Join(i.srcDir, expectedPulumiPath+".extracted.yaml"))
+	}
+}
+
 func MakeSSHKeyPair(publicKeyPath string, privateKeyPath string) error {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
 	if err != nil {
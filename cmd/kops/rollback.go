@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollbackLong = templates.LongDesc(i18n.T(`
+	Roll back resources to an earlier revision.`))
+
+	rollbackExample = templates.Examples(i18n.T(`
+	# Roll back the cluster spec to revision 3
+	kops rollback cluster k8s-cluster.example.com --to-revision 3
+	`))
+
+	rollbackShort = i18n.T(`Roll back resources to an earlier revision.`)
+)
+
+func NewCmdRollback(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Short:   rollbackShort,
+		Long:    rollbackLong,
+		Example: rollbackExample,
+	}
+
+	cmd.AddCommand(NewCmdRollbackCluster(f, out))
+
+	return cmd
+}
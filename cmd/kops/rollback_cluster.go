@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollbackClusterLong = templates.LongDesc(i18n.T(`
+	Roll back the cluster spec to the version it had at a revision.
+
+	The cluster spec of the latest Cluster revision not newer than the given
+	revision is written back to the state store, as a new revision. Instance
+	groups are not changed. Use kops get history cluster to list the revisions,
+	and kops update cluster to apply the rolled back spec to the cloud.`))
+
+	rollbackClusterExample = templates.Examples(i18n.T(`
+	# List the revisions of a cluster
+	kops get history cluster k8s-cluster.example.com
+
+	# Roll back the cluster spec to revision 3 and apply it
+	kops rollback cluster k8s-cluster.example.com --to-revision 3
+	kops update cluster k8s-cluster.example.com --yes
+	`))
+
+	rollbackClusterShort = i18n.T(`Roll back the cluster spec to an earlier revision.`)
+)
+
+type RollbackClusterOptions struct {
+	ClusterName string
+	ToRevision  int
+}
+
+func NewCmdRollbackCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RollbackClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             rollbackClusterShort,
+		Long:              rollbackClusterLong,
+		Example:           rollbackClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRollbackCluster(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.ToRevision, "to-revision", options.ToRevision, "Revision to roll the cluster spec back to")
+	cmd.MarkFlagRequired("to-revision")
+
+	return cmd
+}
+
+func RunRollbackCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollbackClusterOptions) error {
+	if options.ToRevision <= 0 {
+		return fmt.Errorf("--to-revision must be a positive revision number")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	revisions, err := clientset.ListClusterRevisions(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	revision := findClusterRevision(revisions, options.ToRevision)
+	if revision == nil {
+		return fmt.Errorf("no revision of cluster %q found at or before revision %d", cluster.Name, options.ToRevision)
+	}
+
+	o, _, err := kopscodecs.Decode([]byte(revision.Object), nil)
+	if err != nil {
+		return fmt.Errorf("error parsing revision %d: %v", revision.Revision, err)
+	}
+	rolledBack, ok := o.(*kopsapi.Cluster)
+	if !ok {
+		return fmt.Errorf("revision %d is a %T, not a cluster", revision.Revision, o)
+	}
+
+	// Keep the current metadata, so the write is an update of the current cluster
+	rolledBack.ObjectMeta = cluster.ObjectMeta
+
+	if _, err := clientset.UpdateCluster(ctx, rolledBack, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Cluster %q has been rolled back to revision %d.\n", cluster.Name, revision.Revision)
+	fmt.Fprintf(out, "Run kops update cluster to apply the changes to the cloud.\n")
+	return nil
+}
+
+// findClusterRevision returns the latest Cluster revision that is not newer than toRevision.
+func findClusterRevision(revisions []*simple.ClusterRevision, toRevision int) *simple.ClusterRevision {
+	var found *simple.ClusterRevision
+	for _, r := range revisions {
+		if r.Kind != "Cluster" || r.Revision > toRevision {
+			continue
+		}
+		if found == nil || r.Revision > found.Revision {
+			found = r
+		}
+	}
+	return found
+}
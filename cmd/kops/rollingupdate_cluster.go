@@ -86,6 +86,14 @@ var (
 		# Update only the "nodes-1a" instance group of the k8s-cluster.example.com kOps cluster.
 		kops rolling-update cluster k8s-cluster.example.com --yes \
 		  --instance-group nodes-1a
+
+		# Update the k8s-cluster.example.com kOps cluster, emitting
+		# progress as a stream of JSON events.
+		kops rolling-update cluster k8s-cluster.example.com --yes -o json
+
+		# Resume an interrupted rolling update of the k8s-cluster.example.com kOps cluster,
+		# skipping the instance groups and instances that were already updated.
+		kops rolling-update cluster k8s-cluster.example.com --yes --resume
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -128,6 +136,9 @@ type RollingUpdateOptions struct {
 	// Interactive rolling-update prompts user to continue after each instances is updated.
 	Interactive bool
 
+	// Resume continues a previously interrupted rolling-update from its persisted progress.
+	Resume bool
+
 	ClusterName string
 
 	// InstanceGroups is the list of instance groups to rolling-update;
@@ -137,6 +148,10 @@ type RollingUpdateOptions struct {
 	// InstanceGroupRoles is the list of roles we should rolling-update
 	// if not specified, all instance groups will be updated
 	InstanceGroupRoles []string
+
+	// Output is the output format; table prints human-readable progress,
+	// json emits a stream of rolling update events, one per line
+	Output string
 }
 
 func (o *RollingUpdateOptions) InitDefaults() {
@@ -154,6 +169,8 @@ func (o *RollingUpdateOptions) InitDefaults() {
 	o.PostDrainDelay = 5 * time.Second
 	o.ValidationTimeout = 15 * time.Minute
 	o.ValidateCount = 2
+
+	o.Output = OutputTable
 }
 
 func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -189,6 +206,7 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.BastionInterval, "bastion-interval", options.BastionInterval, "Time to wait between restarting bastions")
 	cmd.Flags().DurationVar(&options.PostDrainDelay, "post-drain-delay", options.PostDrainDelay, "Time to wait after draining each node")
 	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Resume an interrupted rolling update, skipping the instance groups and instances already updated")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to update (defaults to all if not specified)")
 	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(&options))
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to update ("+strings.Join(allRoles, ",")+")")
@@ -196,6 +214,11 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		return sets.NewString(allRoles...).Delete(options.InstanceGroupRoles...).List(), cobra.ShellCompDirectiveNoFileComp
 	})
 
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of table|json; json emits a stream of progress events")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputTable, OutputJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+
 	cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", true, "Fail if draining a node fails")
 	cmd.Flags().BoolVar(&options.FailOnValidate, "fail-on-validate-error", true, "Fail if the cluster fails to validate")
 
@@ -213,6 +236,9 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 }
 
 func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollingUpdateOptions) error {
+	if options.Output != OutputTable && options.Output != OutputJSON {
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
 
 	clientset, err := f.Clientset()
 	if err != nil {
@@ -320,6 +346,11 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return err
 	}
 
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
 	d := &instancegroups.RollingUpdateCluster{
 		Clientset:         clientset,
 		Ctx:               ctx,
@@ -338,17 +369,31 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		PostDrainDelay:    options.PostDrainDelay,
 		ValidationTimeout: options.ValidationTimeout,
 		ValidateCount:     int(options.ValidateCount),
+		CheckpointPath:    configBase.Join("rolling-update-checkpoint"),
+		Resume:            options.Resume,
+		RecordStatus:      true,
+		Selection: instancegroups.RollingUpdateSelection{
+			InstanceGroups:     options.InstanceGroups,
+			InstanceGroupRoles: options.InstanceGroupRoles,
+		},
 		// TODO should we expose this to the UI?
 		ValidateTickDuration:    30 * time.Second,
 		ValidateSuccessDuration: 10 * time.Second,
 	}
 
+	// Informational messages must not be mixed into the JSON event stream
+	messages := os.Stdout
+	if options.Output == OutputJSON {
+		d.Events = out
+		messages = os.Stderr
+	}
+
 	err = d.AdjustNeedUpdate(groups)
 	if err != nil {
 		return err
 	}
 
-	{
+	if options.Output == OutputTable {
 		t := &tables.Table{}
 		t.AddColumn("NAME", func(r *cloudinstances.CloudInstanceGroup) string {
 			return r.InstanceGroup.ObjectMeta.Name
@@ -408,18 +453,18 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 	}
 
 	if !needUpdate && !options.Force {
-		fmt.Printf("\nNo rolling-update required.\n")
+		fmt.Fprintf(messages, "\nNo rolling-update required.\n")
 		return nil
 	}
 
 	if !options.Yes {
-		fmt.Printf("\nMust specify --yes to rolling-update.\n")
+		fmt.Fprintf(messages, "\nMust specify --yes to rolling-update.\n")
 		return nil
 	}
 
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
-		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
+		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient, clientset)
 		if err != nil {
 			return fmt.Errorf("cannot create cluster validator: %v", err)
 		}
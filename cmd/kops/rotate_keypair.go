@@ -0,0 +1,492 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rotateKeypairLong = templates.LongDesc(i18n.T(`
+	Rotate a keyset to a new keypair.
+
+	The rotation creates a new keypair, updates the cluster to trust it,
+	promotes it to be the primary, updates the cluster to use it for signing,
+	distrusts the keypairs it replaces and updates the cluster to stop trusting them.
+	Each update applies the cluster configuration, performs a rolling update
+	and validates the cluster before the next phase starts.
+
+	The progress of the rotation is recorded in the state store. An interrupted
+	rotation is continued with --resume.
+	`))
+
+	rotateKeypairExample = templates.Examples(i18n.T(`
+	# Show the phases of rotating the kubernetes-ca keyset.
+	kops rotate keypair kubernetes-ca \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Rotate the kubernetes-ca keyset.
+	kops rotate keypair kubernetes-ca --yes \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Continue an interrupted rotation of the kubernetes-ca keyset.
+	kops rotate keypair kubernetes-ca --yes --resume \
+		--name k8s-cluster.example.com --state s3://my-state-store
+	`))
+
+	rotateKeypairShort = i18n.T(`Rotate a keyset to a new keypair.`)
+)
+
+type RotateKeypairOptions struct {
+	ClusterName string
+	Keyset      string
+	Yes         bool
+
+	// Resume continues a rotation that was interrupted.
+	Resume bool
+
+	// ValidationTimeout is the amount of time to wait for the cluster to validate after each update.
+	ValidationTimeout time.Duration
+}
+
+func (o *RotateKeypairOptions) InitDefaults() {
+	o.Yes = false
+	o.Resume = false
+	o.ValidationTimeout = 15 * time.Minute
+}
+
+// NewCmdRotateKeypair returns a rotate keypair command.
+func NewCmdRotateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RotateKeypairOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "keypair KEYSET",
+		Short:   rotateKeypairShort,
+		Long:    rotateKeypairLong,
+		Example: rotateKeypairExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) == 0 {
+				return fmt.Errorf("must specify name of keyset to rotate")
+			}
+			if len(args) != 1 {
+				return fmt.Errorf("can only rotate one keyset at a time")
+			}
+			options.Keyset = args[0]
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeRotateKeyset(options, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.TODO()
+
+			return RunRotateKeypair(ctx, f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the rotation")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Continue an interrupted rotation from the recorded progress")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for the cluster to validate after each update")
+
+	return cmd
+}
+
+func completeRotateKeyset(options *RotateKeypairOptions, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	commandutils.ConfigureKlogForCompletion()
+	ctx := context.TODO()
+
+	cluster, clientSet, completions, directive := GetClusterForCompletion(ctx, &rootCommand, "")
+	if cluster == nil {
+		return completions, directive
+	}
+
+	keyset, _, completions, directive := completeKeyset(cluster, clientSet, args, rotatableKeysetFilter)
+	if keyset == nil {
+		return completions, directive
+	}
+
+	if len(args) > 1 {
+		return commandutils.CompletionError("too many arguments", nil)
+	}
+
+	flags := []string{"--yes"}
+	if !options.Resume {
+		flags = append(flags, "--resume")
+	}
+	return flags, cobra.ShellCompDirectiveNoFileComp
+}
+
+// KeypairRotationProgress records the progress of a keypair rotation, so that an interrupted
+// rotation can be resumed where it stopped.
+type KeypairRotationProgress struct {
+	// Keyset is the name of the keyset being rotated.
+	Keyset string `json:"keyset"`
+	// StartTime is when the rotation started.
+	StartTime time.Time `json:"startTime"`
+	// PreviousKeypairIDs are the IDs of the keypairs in the keyset before the rotation started.
+	PreviousKeypairIDs []string `json:"previousKeypairIDs,omitempty"`
+	// NewKeypairID is the ID of the keypair created by the rotation.
+	NewKeypairID string `json:"newKeypairID,omitempty"`
+	// CurrentPhase is the phase that was started but has not completed, if any.
+	CurrentPhase string `json:"currentPhase,omitempty"`
+	// CompletedPhases are the names of the phases that have completed.
+	CompletedPhases []string `json:"completedPhases,omitempty"`
+}
+
+// keypairRotationPhase is a step of a keypair rotation.
+type keypairRotationPhase struct {
+	name        string
+	description string
+	// run performs the phase. resume is true if a previous attempt of the phase was interrupted.
+	run func(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions, progress *KeypairRotationProgress, resume bool) error
+}
+
+// keypairRotationPhases are the phases of a keypair rotation, in the order they are performed.
+var keypairRotationPhases = []keypairRotationPhase{
+	{
+		name:        "create-keypair",
+		description: "Create a new keypair in the keyset",
+		run:         rotateCreateKeypair,
+	},
+	{
+		name:        "apply-trust",
+		description: "Update the cluster to trust the new keypair",
+		run:         rotateApply(false),
+	},
+	{
+		name:        "promote-keypair",
+		description: "Promote the new keypair to be the primary",
+		run:         rotatePromoteKeypair,
+	},
+	{
+		name:        "apply-promotion",
+		description: "Update the cluster and replace all instances to use the new keypair for signing",
+		run:         rotateApply(true),
+	},
+	{
+		name:        "distrust-keypairs",
+		description: "Distrust the keypairs replaced by the new keypair",
+		run:         rotateDistrustKeypairs,
+	},
+	{
+		name:        "apply-distrust",
+		description: "Update the cluster to stop trusting the replaced keypairs",
+		run:         rotateApply(false),
+	},
+}
+
+// RunRotateKeypair rotates a keyset to a new keypair.
+func RunRotateKeypair(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions) error {
+	if !rotatableKeysets.Has(options.Keyset) {
+		return fmt.Errorf("rotating keypairs for %q is not supported", options.Keyset)
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	clientSet, err := f.Clientset()
+	if err != nil {
+		return fmt.Errorf("getting clientset: %v", err)
+	}
+
+	configBase, err := clientSet.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	progressPath := configBase.Join("rotations", options.Keyset)
+
+	progress, err := readKeypairRotationProgress(progressPath)
+	if err != nil {
+		return err
+	}
+	if progress != nil && !options.Resume {
+		return fmt.Errorf("a rotation of keyset %q started at %s has not completed; use --resume to continue it", options.Keyset, progress.StartTime.Format(time.RFC3339))
+	}
+	if progress == nil && options.Resume {
+		klog.Infof("No rotation of keyset %q in progress; starting from the beginning.", options.Keyset)
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Rotating keyset %s will:\n", options.Keyset)
+		for i, phase := range keypairRotationPhases {
+			status := ""
+			if progress != nil && progress.isCompleted(phase.name) {
+				status = " (completed)"
+			}
+			fmt.Fprintf(out, "  %d. %s%s\n", i+1, phase.description, status)
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to rotate the keyset.\n")
+		return nil
+	}
+
+	resuming := progress != nil
+	if progress == nil {
+		keyset, err := findRotationKeyset(ctx, f, options)
+		if err != nil {
+			return err
+		}
+
+		progress = &KeypairRotationProgress{
+			Keyset:    options.Keyset,
+			StartTime: time.Now().UTC(),
+		}
+		for id := range keyset.Items {
+			progress.PreviousKeypairIDs = append(progress.PreviousKeypairIDs, id)
+		}
+		sort.Strings(progress.PreviousKeypairIDs)
+		if err := progress.write(progressPath, cluster); err != nil {
+			return err
+		}
+	}
+
+	for i, phase := range keypairRotationPhases {
+		if progress.isCompleted(phase.name) {
+			continue
+		}
+
+		fmt.Fprintf(out, "Phase %d/%d: %s\n", i+1, len(keypairRotationPhases), phase.description)
+
+		resumePhase := resuming && progress.CurrentPhase == phase.name
+		progress.CurrentPhase = phase.name
+		if err := progress.write(progressPath, cluster); err != nil {
+			return err
+		}
+
+		if err := phase.run(ctx, f, out, options, progress, resumePhase); err != nil {
+			return fmt.Errorf("rotating keyset %q failed in phase %q; fix the problem and continue with --resume: %v", options.Keyset, phase.name, err)
+		}
+
+		progress.CurrentPhase = ""
+		progress.CompletedPhases = append(progress.CompletedPhases, phase.name)
+		if err := progress.write(progressPath, cluster); err != nil {
+			return err
+		}
+	}
+
+	if err := progressPath.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing rotation progress %s: %v", progressPath, err)
+	}
+
+	fmt.Fprintf(out, "Rotated keyset %s to keypair %s\n", options.Keyset, progress.NewKeypairID)
+	return nil
+}
+
+// readKeypairRotationProgress reads the keypair rotation progress at the specified path.
+// It returns nil if there is no rotation in progress.
+func readKeypairRotationProgress(p vfs.Path) (*KeypairRotationProgress, error) {
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rotation progress %s: %v", p, err)
+	}
+
+	progress := &KeypairRotationProgress{}
+	if err := json.Unmarshal(b, progress); err != nil {
+		return nil, fmt.Errorf("error parsing rotation progress %s: %v", p, err)
+	}
+	return progress, nil
+}
+
+func (p *KeypairRotationProgress) write(path vfs.Path, cluster *kops.Cluster) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing rotation progress: %v", err)
+	}
+	acl, err := acls.GetACL(path, cluster)
+	if err != nil {
+		return err
+	}
+	if err := path.WriteFile(bytes.NewReader(b), acl); err != nil {
+		return fmt.Errorf("error writing rotation progress %s: %v", path, err)
+	}
+	return nil
+}
+
+func (p *KeypairRotationProgress) isCompleted(phase string) bool {
+	for _, completed := range p.CompletedPhases {
+		if completed == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// findRotationKeyset reads the keyset being rotated.
+// A new keystore is built on every call, so that changes made by the other commands are observed.
+func findRotationKeyset(ctx context.Context, f *util.Factory, options *RotateKeypairOptions) (*fi.Keyset, error) {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	clientSet, err := f.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("getting clientset: %v", err)
+	}
+
+	keyStore, err := clientSet.KeyStore(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("getting keystore: %v", err)
+	}
+
+	keyset, err := keyStore.FindKeyset(options.Keyset)
+	if err != nil {
+		return nil, fmt.Errorf("reading keyset: %v", err)
+	} else if keyset == nil {
+		return nil, fmt.Errorf("keyset %q not found", options.Keyset)
+	}
+	return keyset, nil
+}
+
+// newKeypairID returns the ID of a keypair in keyset that is not one of previousIDs, or "" if there is none.
+func newKeypairID(keyset *fi.Keyset, previousIDs []string) string {
+	previous := sets.NewString(previousIDs...)
+	var ids []string
+	for id := range keyset.Items {
+		if !previous.Has(id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	sort.Strings(ids)
+	return ids[len(ids)-1]
+}
+
+func rotateCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions, progress *KeypairRotationProgress, resume bool) error {
+	if resume {
+		// An interrupted attempt may already have created the keypair.
+		keyset, err := findRotationKeyset(ctx, f, options)
+		if err != nil {
+			return err
+		}
+		if id := newKeypairID(keyset, progress.PreviousKeypairIDs); id != "" {
+			progress.NewKeypairID = id
+			return nil
+		}
+	}
+
+	if err := RunCreateKeypair(ctx, f, out, &CreateKeypairOptions{
+		ClusterName: options.ClusterName,
+		Keyset:      options.Keyset,
+	}); err != nil {
+		return err
+	}
+
+	keyset, err := findRotationKeyset(ctx, f, options)
+	if err != nil {
+		return err
+	}
+	progress.NewKeypairID = newKeypairID(keyset, progress.PreviousKeypairIDs)
+	if progress.NewKeypairID == "" {
+		return fmt.Errorf("new keypair not found in keyset %q", options.Keyset)
+	}
+	fmt.Fprintf(out, "Created keypair %s\n", progress.NewKeypairID)
+	return nil
+}
+
+func rotatePromoteKeypair(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions, progress *KeypairRotationProgress, resume bool) error {
+	if progress.NewKeypairID == "" {
+		return fmt.Errorf("no new keypair was recorded for keyset %q", options.Keyset)
+	}
+
+	if err := RunPromoteKeypair(ctx, f, out, &PromoteKeypairOptions{
+		ClusterName: options.ClusterName,
+		Keyset:      options.Keyset,
+		KeypairID:   progress.NewKeypairID,
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+func rotateDistrustKeypairs(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions, progress *KeypairRotationProgress, resume bool) error {
+	return RunDistrustKeypair(ctx, f, out, &DistrustKeypairOptions{
+		ClusterName: options.ClusterName,
+		Keyset:      options.Keyset,
+	})
+}
+
+// rotateApply returns a phase that updates the cluster, performs a rolling update and validates the cluster.
+// If force is true, all instances are replaced, so that they are issued certificates signed by the new primary.
+func rotateApply(force bool) func(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions, progress *KeypairRotationProgress, resume bool) error {
+	return func(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions, progress *KeypairRotationProgress, resume bool) error {
+		updateClusterOptions := &UpdateClusterOptions{}
+		updateClusterOptions.InitDefaults()
+		updateClusterOptions.Yes = true
+		updateClusterOptions.ClusterName = options.ClusterName
+		updateClusterOptions.CreateKubecfg = true
+		updateClusterOptions.admin = kubeconfig.DefaultKubecfgAdminLifetime
+		if _, err := RunUpdateCluster(ctx, f, out, updateClusterOptions); err != nil {
+			return fmt.Errorf("updating cluster: %v", err)
+		}
+
+		rollingUpdateOptions := &RollingUpdateOptions{}
+		rollingUpdateOptions.InitDefaults()
+		rollingUpdateOptions.Yes = true
+		rollingUpdateOptions.Force = force
+		rollingUpdateOptions.Resume = resume
+		rollingUpdateOptions.ClusterName = options.ClusterName
+		rollingUpdateOptions.ValidationTimeout = options.ValidationTimeout
+		if err := RunRollingUpdateCluster(ctx, f, out, rollingUpdateOptions); err != nil {
+			return fmt.Errorf("rolling update: %v", err)
+		}
+
+		validateClusterOptions := &ValidateClusterOptions{}
+		validateClusterOptions.InitDefaults()
+		validateClusterOptions.ClusterName = options.ClusterName
+		validateClusterOptions.wait = options.ValidationTimeout
+		if _, err := RunValidateCluster(ctx, f, out, validateClusterOptions); err != nil {
+			return fmt.Errorf("validating cluster: %v", err)
+		}
+		return nil
+	}
+}
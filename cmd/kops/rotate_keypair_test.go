@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestKeypairRotationProgressRoundTrip(t *testing.T) {
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster.example.com/rotations/kubernetes-ca")
+
+	progress, err := readKeypairRotationProgress(p)
+	if err != nil {
+		t.Fatalf("unexpected error reading missing progress: %v", err)
+	}
+	if progress != nil {
+		t.Fatalf("expected no progress, got %v", progress)
+	}
+
+	expected := &KeypairRotationProgress{
+		Keyset:             "kubernetes-ca",
+		StartTime:          time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		PreviousKeypairIDs: []string{"6971339731237183120"},
+		NewKeypairID:       "6982820025135291416",
+		CurrentPhase:       "apply-trust",
+		CompletedPhases:    []string{"create-keypair"},
+	}
+	if err := expected.write(p, &kops.Cluster{}); err != nil {
+		t.Fatalf("unexpected error writing progress: %v", err)
+	}
+
+	actual, err := readKeypairRotationProgress(p)
+	if err != nil {
+		t.Fatalf("unexpected error reading progress: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected progress: got %+v, expected %+v", actual, expected)
+	}
+
+	if !actual.isCompleted("create-keypair") {
+		t.Errorf("expected create-keypair to be completed")
+	}
+	if actual.isCompleted("apply-trust") {
+		t.Errorf("expected apply-trust not to be completed")
+	}
+}
+
+func TestNewKeypairID(t *testing.T) {
+	keyset := &fi.Keyset{
+		Items: map[string]*fi.KeysetItem{
+			"6971339731237183120": {Id: "6971339731237183120"},
+			"6982820025135291416": {Id: "6982820025135291416"},
+		},
+	}
+
+	grid := []struct {
+		previous []string
+		expected string
+	}{
+		{
+			previous: []string{"6971339731237183120"},
+			expected: "6982820025135291416",
+		},
+		{
+			previous: []string{"6971339731237183120", "6982820025135291416"},
+			expected: "",
+		},
+		{
+			previous: nil,
+			expected: "6982820025135291416",
+		},
+	}
+	for _, g := range grid {
+		actual := newKeypairID(keyset, g.previous)
+		if actual != g.expected {
+			t.Errorf("previous %v: got %q, expected %q", g.previous, actual, g.expected)
+		}
+	}
+}
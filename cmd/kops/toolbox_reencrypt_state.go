@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/lease"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxReencryptStateLong = templates.LongDesc(i18n.T(`
+	Rewrites the private keys and secrets of a cluster in the state store,
+	encrypting them with the key named by spec.stateEncryptionKey of the cluster.
+
+	The key is an AWS KMS key (aws-kms://ARN) or a GCP Cloud KMS key (gcp-kms://RESOURCE_NAME).
+	Apply the key with "kops update cluster" first, so that the control plane and nodes
+	are allowed to decrypt with it. If spec.stateEncryptionKey is not set,
+	the private keys and secrets are written in plaintext.`))
+
+	toolboxReencryptStateExample = templates.Examples(i18n.T(`
+	# Encrypt the private keys and secrets of a cluster with an AWS KMS key
+	kops edit cluster k8s-cluster.example.com
+	# set spec.stateEncryptionKey: aws-kms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+	kops update cluster k8s-cluster.example.com --yes
+	kops toolbox reencrypt-state k8s-cluster.example.com
+	`))
+
+	toolboxReencryptStateShort = i18n.T(`Encrypt or re-encrypt the private keys and secrets in the state store`)
+)
+
+type ToolboxReencryptStateOptions struct {
+	ClusterName string
+
+	// ForceUnlock takes over the state store lock, even if it is held by another update.
+	ForceUnlock bool
+}
+
+func NewCmdToolboxReencryptState(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxReencryptStateOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "reencrypt-state [CLUSTER]",
+		Short:             toolboxReencryptStateShort,
+		Long:              toolboxReencryptStateLong,
+		Example:           toolboxReencryptStateExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxReencryptState(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.ForceUnlock, "force-unlock", options.ForceUnlock, "Take over the state store lock, even if it is held by another update")
+
+	return cmd
+}
+
+func RunToolboxReencryptState(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxReencryptStateOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	if cluster.Spec.StateEncryptionKey == "" {
+		klog.Warningf("spec.stateEncryptionKey is not set; private keys and secrets will be written in plaintext")
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	lock, err := lease.Acquire(configBase, cluster, lease.Options{
+		Operation: "toolbox reencrypt-state",
+		Force:     options.ForceUnlock,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			klog.Warningf("error releasing the state store lock: %v", err)
+		}
+	}()
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return fmt.Errorf("error listing keysets: %v", err)
+	}
+	var keysetNames []string
+	for name := range keysets {
+		keysetNames = append(keysetNames, name)
+	}
+	sort.Strings(keysetNames)
+	for _, name := range keysetNames {
+		if err := keyStore.StoreKeyset(name, keysets[name]); err != nil {
+			return fmt.Errorf("error writing keyset %q: %v", name, err)
+		}
+		fmt.Fprintf(out, "Rewrote keyset %s\n", name)
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	secretNames, err := secretStore.ListSecrets()
+	if err != nil {
+		return fmt.Errorf("error listing secrets: %v", err)
+	}
+	sort.Strings(secretNames)
+	for _, name := range secretNames {
+		secret, err := secretStore.FindSecret(name)
+		if err != nil {
+			return fmt.Errorf("error reading secret %q: %v", name, err)
+		}
+		if secret == nil {
+			continue
+		}
+		if _, err := secretStore.ReplaceSecret(name, secret); err != nil {
+			return fmt.Errorf("error writing secret %q: %v", name, err)
+		}
+		fmt.Fprintf(out, "Rewrote secret %s\n", name)
+	}
+
+	return nil
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -35,12 +36,14 @@ import (
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/lease"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/upup/pkg/kutil"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -55,6 +58,11 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After the cluster has been edited or upgraded, update the cloud resources with:
 	kops update cluster k8s-cluster.example.com --yes --state=s3://my-state-store --yes
+
+	# Save the changes to a plan file, review them, and later apply exactly that plan,
+	# refusing if the cloud or the state store changed in the meantime:
+	kops update cluster k8s-cluster.example.com --out-plan=plan.json
+	kops update cluster k8s-cluster.example.com --plan=plan.json --yes
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -82,6 +90,20 @@ type UpdateClusterOptions struct {
 	// LifecycleOverrides is a slice of taskName=lifecycle name values.  This slice is used
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
+
+	// OutPlan is the path to which the plan of a dry run is written.
+	OutPlan string
+	// Plan is the path of a plan written by a previous dry run; the update is refused if the cluster has drifted since.
+	Plan string
+
+	// Output is the format of the dry run report: table, json or yaml.
+	Output string
+
+	// ForceUnlock takes over the state store lock, even if it is held by another update.
+	ForceUnlock bool
+
+	// EmitImports writes a script importing the existing cloud resources into the terraform state.
+	EmitImports bool
 }
 
 func (o *UpdateClusterOptions) InitDefaults() {
@@ -93,6 +115,8 @@ func (o *UpdateClusterOptions) InitDefaults() {
 	// By default we export a kubecfg, but it doesn't have a static/eternal credential in it any more.
 	o.CreateKubecfg = true
 
+	o.Output = OutputTable
+
 	o.RunTasksOptions.InitDefaults()
 }
 
@@ -114,9 +138,9 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Create cloud resources, without --yes update is in dry run mode")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation, pulumi")
 	cmd.RegisterFlagCompletionFunc("target", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{cloudup.TargetDirect, cloudup.TargetDryRun, cloudup.TargetTerraform, cloudup.TargetCloudformation}, cobra.ShellCompDirectiveNoFileComp
+		return []string{cloudup.TargetDirect, cloudup.TargetDryRun, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetPulumi}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (deprecated: use kops create secret instead)")
 	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
@@ -136,6 +160,14 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	viper.BindPFlag("lifecycle-overrides", cmd.Flags().Lookup("lifecycle-overrides"))
 	viper.BindEnv("lifecycle-overrides", "KOPS_LIFECYCLE_OVERRIDES")
 	cmd.RegisterFlagCompletionFunc("lifecycle-overrides", completeLifecycleOverrides)
+	cmd.Flags().BoolVar(&options.EmitImports, "emit-imports", options.EmitImports, "Write a script importing the existing cloud resources into the terraform state, only with --target=terraform")
+	cmd.Flags().StringVar(&options.OutPlan, "out-plan", options.OutPlan, "Path to write the plan of changes to, for a later --plan")
+	cmd.Flags().StringVar(&options.Plan, "plan", options.Plan, "Path of a plan written by --out-plan; refuse to update the cluster if it has drifted since the plan was made")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format of the dry run report. One of json|yaml|table.")
+	cmd.Flags().BoolVar(&options.ForceUnlock, "force-unlock", options.ForceUnlock, "Take over the state store lock, even if it is held by another update")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputJSON, OutputYaml, OutputTable}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
@@ -192,11 +224,53 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		targetName = cloudup.TargetDryRun
 	}
 
+	structuredOutput := false
+	switch c.Output {
+	case "", OutputTable:
+	case OutputJSON, OutputYaml:
+		if !isDryrun {
+			return nil, fmt.Errorf("--output=%s can only be used for a dry run", c.Output)
+		}
+		structuredOutput = true
+	default:
+		return nil, fmt.Errorf("unknown output format: %q", c.Output)
+	}
+
+	if c.EmitImports && c.Target != cloudup.TargetTerraform {
+		return nil, fmt.Errorf("--emit-imports can only be used with --target=%s", cloudup.TargetTerraform)
+	}
+
+	if c.OutPlan != "" || c.Plan != "" {
+		if c.Target != cloudup.TargetDirect {
+			return nil, fmt.Errorf("--out-plan and --plan can only be used with --target=%s", cloudup.TargetDirect)
+		}
+		if c.OutPlan != "" && c.Plan != "" {
+			return nil, fmt.Errorf("cannot use both --out-plan and --plan")
+		}
+		if c.OutPlan != "" && c.Yes {
+			return nil, fmt.Errorf("--out-plan cannot be used with --yes")
+		}
+	}
+
+	var plan *fi.Plan
+	if c.Plan != "" {
+		b, err := ioutil.ReadFile(c.Plan)
+		if err != nil {
+			return nil, fmt.Errorf("error reading plan %q: %v", c.Plan, err)
+		}
+		plan, err = fi.ReadPlan(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("error reading plan %q: %v", c.Plan, err)
+		}
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
+		} else if c.Target == cloudup.TargetPulumi {
+			c.OutDir = "out/pulumi"
 		} else {
 			c.OutDir = "out"
 		}
@@ -212,6 +286,26 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	if !isDryrun {
+		// Hold the state store lock while we write to it, so concurrent updates don't overwrite each other
+		configBase, err := clientset.ConfigBaseFor(cluster)
+		if err != nil {
+			return results, err
+		}
+		lock, err := lease.Acquire(configBase, cluster, lease.Options{
+			Operation: "update cluster",
+			Force:     c.ForceUnlock,
+		})
+		if err != nil {
+			return results, err
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				klog.Warningf("error releasing the state store lock: %v", err)
+			}
+		}()
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -293,8 +387,15 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		TargetName:         targetName,
 		LifecycleOverrides: lifecycleOverrideMap,
 		GetAssets:          c.GetAssets,
+		EmitImports:        c.EmitImports,
+	}
+	if !isDryrun {
+		// The tasks are checked against the plan before they are applied
+		applyCmd.Plan = plan
+	}
+	if structuredOutput {
+		applyCmd.DryRunOut = io.Discard
 	}
-
 	if err := applyCmd.Run(ctx); err != nil {
 		return results, err
 	}
@@ -307,6 +408,43 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 
 	if isDryrun && !c.GetAssets {
 		target := applyCmd.Target.(*fi.DryRunTarget)
+		if structuredOutput {
+			report, err := target.Report(applyCmd.TaskMap)
+			if err != nil {
+				return results, err
+			}
+			if err := updateClusterReportOutput(report, out, c.Output); err != nil {
+				return results, err
+			}
+		}
+		if plan != nil {
+			if err := target.VerifyPlan(plan, cluster.ObjectMeta.Name, applyCmd.TaskMap); err != nil {
+				return results, err
+			}
+			if !structuredOutput {
+				fmt.Fprintf(out, "The cluster has not drifted since the plan was made\n")
+			}
+		}
+		if c.OutPlan != "" {
+			newPlan, err := target.BuildPlan(cluster.ObjectMeta.Name, applyCmd.TaskMap)
+			if err != nil {
+				return results, err
+			}
+			var b bytes.Buffer
+			if err := fi.WritePlan(&b, newPlan); err != nil {
+				return results, err
+			}
+			if err := ioutil.WriteFile(c.OutPlan, b.Bytes(), 0600); err != nil {
+				return results, fmt.Errorf("error writing plan %q: %v", c.OutPlan, err)
+			}
+			if !structuredOutput {
+				fmt.Fprintf(out, "Plan has been written to %s; apply it with --plan=%s --yes\n", c.OutPlan, c.OutPlan)
+			}
+			return results, nil
+		}
+		if structuredOutput {
+			return results, nil
+		}
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
 		} else {
@@ -327,18 +465,14 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 
 		klog.Infof("Exporting kubecfg for cluster")
 
-		// TODO: Another flag?
-		useKopsAuthenticationPlugin := false
-		conf, err := kubeconfig.BuildKubecfg(
-			cluster,
-			keyStore,
-			secretStore,
-			cloud,
-			c.admin,
-			c.user,
-			c.internal,
-			f.KopsStateStore(),
-			useKopsAuthenticationPlugin)
+		conf, err := kubeconfig.BuildKubecfg(cluster, keyStore, secretStore, cloud, kubeconfig.BuildKubecfgOptions{
+			Admin:          c.admin,
+			User:           c.user,
+			Internal:       c.internal,
+			KopsStateStore: f.KopsStateStore(),
+			// TODO: Another flag?
+			UseKopsAuthenticationPlugin: false,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -363,6 +497,10 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			if firstRun {
 				fmt.Fprintf(sb, "Run these commands to apply the configuration:\n")
 				fmt.Fprintf(sb, "   cd %s\n", c.OutDir)
+				if c.EmitImports {
+					fmt.Fprintf(sb, "   terraform init\n")
+					fmt.Fprintf(sb, "   sh import.sh\n")
+				}
 				fmt.Fprintf(sb, "   terraform plan\n")
 				fmt.Fprintf(sb, "   terraform apply\n")
 				fmt.Fprintf(sb, "\n")
@@ -378,6 +516,18 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 				fmt.Fprintf(sb, "   aws cloudformation create-stack --capabilities CAPABILITY_NAMED_IAM --stack-name %s --template-body file://%s\n", cfName, cfPath)
 				fmt.Fprintf(sb, "\n")
 			}
+		} else if c.Target == cloudup.TargetPulumi {
+			fmt.Fprintf(sb, "\n")
+			fmt.Fprintf(sb, "Pulumi output has been placed into %s\n", c.OutDir)
+
+			if firstRun {
+				fmt.Fprintf(sb, "Run these commands to apply the configuration:\n")
+				fmt.Fprintf(sb, "   cd %s\n", c.OutDir)
+				fmt.Fprintf(sb, "   pulumi stack init\n")
+				fmt.Fprintf(sb, "   pulumi config set aws:region %s\n", cloud.Region())
+				fmt.Fprintf(sb, "   pulumi up\n")
+				fmt.Fprintf(sb, "\n")
+			}
 		} else if firstRun {
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Cluster is starting.  It should be ready in a few minutes.\n")
@@ -432,6 +582,32 @@ func parseLifecycle(lifecycle string) (fi.Lifecycle, error) {
 	return "", fmt.Errorf("unknown lifecycle %q, available lifecycle: %s", lifecycle, strings.Join(fi.Lifecycles.List(), ","))
 }
 
+// updateClusterReportOutput writes the dry run report in the json or yaml output format.
+func updateClusterReportOutput(report *fi.DryRunReport, out io.Writer, output string) error {
+	switch output {
+	case OutputYaml:
+		y, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		j = append(j, '\n')
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", output)
+	}
+	return nil
+}
+
 func usesBastion(instanceGroups []*kops.InstanceGroup) bool {
 	for _, ig := range instanceGroups {
 		if ig.Spec.Role == kops.InstanceGroupRoleBastion {
@@ -21,8 +21,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"k8s.io/kops/pkg/commands/commandutils"
@@ -30,6 +34,8 @@ import (
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,7 +51,7 @@ import (
 
 var (
 	validateClusterLong = templates.LongDesc(i18n.T(`
-		This commands validates the following components:
+		This commands validates the following components, once or, with --watch, every --interval until interrupted:
 	
 		1. All control plane nodes are running and have "Ready" status.
 		2. All worker nodes are running and have "Ready" status.
@@ -56,7 +62,11 @@ var (
 	validateClusterExample = templates.Examples(i18n.T(`
 	# Validate the cluster set as the current context of the kube config.
 	# Kops will try for 10 minutes to validate the cluster 3 times.
-	kops validate cluster --wait 10m --count 3`))
+	kops validate cluster --wait 10m --count 3
+
+	# Validate the cluster every minute until interrupted, exporting
+	# the results as Prometheus metrics on port 9090.
+	kops validate cluster --watch --interval 1m --metrics-addr :9090`))
 
 	validateClusterShort = i18n.T(`Validate a kOps cluster.`)
 )
@@ -67,10 +77,17 @@ type ValidateClusterOptions struct {
 	wait        time.Duration
 	count       int
 	kubeconfig  string
+
+	// watch validates the cluster repeatedly, every interval, until interrupted.
+	watch    bool
+	interval time.Duration
+	// metricsAddr is the address on which to serve Prometheus metrics in watch mode.
+	metricsAddr string
 }
 
 func (o *ValidateClusterOptions) InitDefaults() {
 	o.output = OutputTable
+	o.interval = 30 * time.Second
 }
 
 func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -92,7 +109,8 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 			// We want the validate command to exit non-zero if validation found a problem,
 			// even if we didn't really hit an error during validation.
-			if len(result.Failures) != 0 {
+			// There is no result when watching, which only stops when interrupted.
+			if result != nil && len(result.Failures) != 0 {
 				os.Exit(2)
 			}
 			return nil
@@ -106,11 +124,25 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.wait, "wait", options.wait, "Amount of time to wait for the cluster to become ready")
 	cmd.Flags().IntVar(&options.count, "count", options.count, "Number of consecutive successful validations required")
 	cmd.Flags().StringVar(&options.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().BoolVar(&options.watch, "watch", options.watch, "Validate the cluster repeatedly until interrupted")
+	cmd.Flags().DurationVar(&options.interval, "interval", options.interval, "Time between validations when watching")
+	cmd.Flags().StringVar(&options.metricsAddr, "metrics-addr", options.metricsAddr, "Address on which to serve Prometheus metrics when watching, for example :9090")
 
 	return cmd
 }
 
 func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ValidateClusterOptions) (*validation.ValidationCluster, error) {
+	if options.watch {
+		if options.wait != 0 || options.count != 0 {
+			return nil, fmt.Errorf("--watch cannot be used together with --wait or --count")
+		}
+		if options.interval <= 0 {
+			return nil, fmt.Errorf("--interval must be positive")
+		}
+	} else if options.metricsAddr != "" {
+		return nil, fmt.Errorf("--metrics-addr can only be used together with --watch")
+	}
+
 	clientSet, err := f.Clientset()
 	if err != nil {
 		return nil, err
@@ -166,11 +198,15 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 	timeout := time.Now().Add(options.wait)
 	pollInterval := 10 * time.Second
 
-	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
+	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient, clientSet)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating validatior: %v", err)
 	}
 
+	if options.watch {
+		return nil, watchValidateCluster(ctx, validator, cluster, instanceGroups, out, options)
+	}
+
 	consecutive := 0
 	for {
 		if options.wait > 0 && time.Now().After(timeout) {
@@ -189,29 +225,8 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 			}
 		}
 
-		switch options.output {
-		case OutputTable:
-			if err := validateClusterOutputTable(result, cluster, instanceGroups, out); err != nil {
-				return nil, err
-			}
-		case OutputYaml:
-			y, err := yaml.Marshal(result)
-			if err != nil {
-				return nil, fmt.Errorf("unable to marshal YAML: %v", err)
-			}
-			if _, err := out.Write(y); err != nil {
-				return nil, fmt.Errorf("error writing to output: %v", err)
-			}
-		case OutputJSON:
-			j, err := json.Marshal(result)
-			if err != nil {
-				return nil, fmt.Errorf("unable to marshal JSON: %v", err)
-			}
-			if _, err := out.Write(j); err != nil {
-				return nil, fmt.Errorf("error writing to output: %v", err)
-			}
-		default:
-			return nil, fmt.Errorf("unknown output format: %q", options.output)
+		if err := validateClusterOutput(result, cluster, instanceGroups, out, options.output); err != nil {
+			return nil, err
 		}
 
 		if len(result.Failures) == 0 {
@@ -240,6 +255,96 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 	}
 }
 
+func validateClusterOutput(result *validation.ValidationCluster, cluster *kopsapi.Cluster, instanceGroups []kopsapi.InstanceGroup, out io.Writer, output string) error {
+	switch output {
+	case OutputTable:
+		return validateClusterOutputTable(result, cluster, instanceGroups, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", output)
+	}
+	return nil
+}
+
+// watchValidateCluster validates the cluster every interval until interrupted, writing each result
+// and, if a metrics address is set, exporting the results as Prometheus metrics.
+func watchValidateCluster(ctx context.Context, validator validation.ClusterValidator, cluster *kopsapi.Cluster, instanceGroups []kopsapi.InstanceGroup, out io.Writer, options *ValidateClusterOptions) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var metrics *validation.Metrics
+	if options.metricsAddr != "" {
+		registry := prometheus.NewRegistry()
+		var err error
+		metrics, err = validation.NewMetrics(cluster.Name, registry)
+		if err != nil {
+			return fmt.Errorf("error registering metrics: %v", err)
+		}
+
+		listener, err := net.Listen("tcp", options.metricsAddr)
+		if err != nil {
+			return fmt.Errorf("error listening on %q: %v", options.metricsAddr, err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		server := &http.Server{Handler: mux}
+		go func() {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				klog.Errorf("error serving metrics: %v", err)
+			}
+		}()
+		defer server.Close()
+		klog.Infof("Serving metrics on http://%s/metrics", listener.Addr())
+	}
+
+	for {
+		start := time.Now()
+		result, err := validator.Validate()
+		if metrics != nil {
+			metrics.Observe(result, time.Since(start), err)
+		}
+
+		if err != nil {
+			klog.Warningf("(will retry): unexpected error during validation: %v", err)
+		} else {
+			if options.output == OutputTable {
+				fmt.Fprintf(out, "\n%s\n", start.Format(time.RFC3339))
+			}
+			if err := validateClusterOutput(result, cluster, instanceGroups, out, options.output); err != nil {
+				return err
+			}
+			// Separate the documents of each validation.
+			switch options.output {
+			case OutputJSON:
+				fmt.Fprintln(out)
+			case OutputYaml:
+				fmt.Fprintln(out, "---")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(options.interval):
+		}
+	}
+}
+
 func validateClusterOutputTable(result *validation.ValidationCluster, cluster *kopsapi.Cluster, instanceGroups []kopsapi.InstanceGroup, out io.Writer) error {
 	t := &tables.Table{}
 	t.AddColumn("NAME", func(c kopsapi.InstanceGroup) string {
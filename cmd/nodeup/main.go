@@ -36,9 +36,9 @@ const (
 func main() {
 	klog.InitFlags(nil)
 
-	var flagConf, flagCacheDir, gitVersion string
+	var flagConf, flagCacheDir, flagRenewCertificates, gitVersion string
 	var flagRetries int
-	var dryrun, installSystemdUnit bool
+	var dryrun, installSystemdUnit, forceRenewal bool
 	target := "direct"
 
 	if kops.GitVersion != "" {
@@ -51,6 +51,8 @@ func main() {
 	flag.BoolVar(&dryrun, "dryrun", false, "Don't create cloud resources; just show what would be done")
 	flag.StringVar(&target, "target", target, "Target - direct, cloudinit")
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
+	flag.StringVar(&flagRenewCertificates, "renew-certificates", "", "If set, only renews the certificates issued by kops-controller, as configured in this file")
+	flag.BoolVar(&forceRenewal, "force-renewal", forceRenewal, "If true, renews the certificates issued by kops-controller even if they are not due for renewal")
 
 	if dryrun {
 		target = "dryrun"
@@ -59,6 +61,17 @@ func main() {
 	flag.Set("logtostderr", "true")
 	flag.Parse()
 
+	if flagRenewCertificates != "" {
+		cmd := &nodeup.RenewCertificatesCommand{
+			ConfigLocation: flagRenewCertificates,
+			Force:          forceRenewal,
+		}
+		if err := cmd.Run(os.Stdout); err != nil {
+			klog.Exitf("error renewing certificates: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	if flagConf == "" {
 		klog.Exitf("--conf is required")
 	}
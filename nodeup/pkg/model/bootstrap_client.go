@@ -51,16 +51,10 @@ func (b BootstrapClientBuilder) Build(c *fi.ModelBuilderContext) error {
 		return err
 	}
 
-	baseURL := url.URL{
-		Scheme: "https",
-		Host:   net.JoinHostPort("kops-controller.internal."+b.Cluster.ObjectMeta.Name, strconv.Itoa(wellknownports.KopsControllerPort)),
-		Path:   "/",
-	}
-
 	bootstrapClient := &nodetasks.KopsBootstrapClient{
 		Authenticator: authenticator,
 		CAs:           []byte(b.NodeupConfig.CAs[fi.CertificateIDCA]),
-		BaseURL:       baseURL,
+		BaseURL:       b.kopsControllerURL(),
 	}
 
 	bootstrapClientTask := &nodetasks.BootstrapClientTask{
@@ -78,3 +72,12 @@ func (b BootstrapClientBuilder) Build(c *fi.ModelBuilderContext) error {
 }
 
 var _ fi.ModelBuilder = &BootstrapClientBuilder{}
+
+// kopsControllerURL returns the base URL of kops-controller.
+func (c *NodeupModelContext) kopsControllerURL() url.URL {
+	return url.URL{
+		Scheme: "https",
+		Host:   net.JoinHostPort("kops-controller.internal."+c.Cluster.ObjectMeta.Name, strconv.Itoa(wellknownports.KopsControllerPort)),
+		Path:   "/",
+	}
+}
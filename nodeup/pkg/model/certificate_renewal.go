@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// CertificateRenewalConfigPath is the path of the configuration for renewing the certificates issued by kops-controller.
+	CertificateRenewalConfigPath = "/etc/kubernetes/certificate-renewal.yaml"
+
+	certificateRenewalService = "kops-certificate-renewal.service"
+	certificateRenewalTimer   = "kops-certificate-renewal.timer"
+)
+
+// CertificateRenewalBuilder installs a systemd timer that renews the certificates issued by kops-controller
+// before they expire, by running nodeup in its certificate renewal mode.
+type CertificateRenewalBuilder struct {
+	*NodeupModelContext
+
+	// NodeupPath is the path of the nodeup binary.
+	NodeupPath string
+}
+
+var _ fi.ModelBuilder = &CertificateRenewalBuilder{}
+
+// Build is responsible for configuring the renewal of the certificates issued by kops-controller.
+// It must run after the builders that request certificates from kops-controller.
+func (b *CertificateRenewalBuilder) Build(c *fi.ModelBuilderContext) error {
+	if b.IsMaster || !b.UseKopsControllerForNodeBootstrap() || len(b.bootstrapCerts) == 0 {
+		return nil
+	}
+
+	baseURL := b.kopsControllerURL()
+	config := &nodeup.CertificateRenewalConfig{
+		CloudProvider:    b.Cluster.Spec.CloudProvider,
+		Server:           baseURL.String(),
+		CACertificates:   b.NodeupConfig.CAs[fi.CertificateIDCA],
+		Services:         []string{kubeletService},
+		ContainerRuntime: b.Cluster.Spec.ContainerRuntime,
+	}
+	if b.Cloud != nil {
+		config.Region = b.Cloud.Region()
+	}
+
+	var names []string
+	for name := range b.bootstrapCerts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		renewable, err := b.renewableCertificate(name)
+		if err != nil {
+			return err
+		}
+		config.Certificates = append(config.Certificates, *renewable)
+	}
+
+	configYAML, err := kops.ToRawYaml(config)
+	if err != nil {
+		return fmt.Errorf("error marshaling certificate renewal config: %v", err)
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     CertificateRenewalConfigPath,
+		Contents: fi.NewBytesResource(configYAML),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0600"),
+	})
+
+	c.AddTask(b.buildSystemdService())
+	c.AddTask(b.buildSystemdTimer())
+
+	return nil
+}
+
+// renewableCertificate returns where the certificate named name is stored on the node, and the containers using it.
+// The certificates of kubelet are loaded when the kubelet service is restarted.
+func (b *CertificateRenewalBuilder) renewableCertificate(name string) (*nodeup.RenewableCertificate, error) {
+	switch name {
+	case "kubelet":
+		return &nodeup.RenewableCertificate{Name: name, Kubeconfig: b.KubeletKubeConfig()}, nil
+	case "kube-proxy":
+		return &nodeup.RenewableCertificate{Name: name, Kubeconfig: "/var/lib/kube-proxy/kubeconfig", Containers: []string{"kube-proxy"}}, nil
+	case "kube-router":
+		return &nodeup.RenewableCertificate{Name: name, Kubeconfig: "/var/lib/kube-router/kubeconfig", Containers: []string{"kube-router"}}, nil
+	case "kubelet-server":
+		dir := b.PathSrvKubernetes()
+		return &nodeup.RenewableCertificate{Name: name, CertPath: filepath.Join(dir, name+".crt"), KeyPath: filepath.Join(dir, name+".key")}, nil
+	case "etcd-client-cilium":
+		dir := "/etc/kubernetes/pki/cilium"
+		return &nodeup.RenewableCertificate{Name: name, CertPath: filepath.Join(dir, name+".crt"), KeyPath: filepath.Join(dir, name+".key"), Containers: []string{"cilium-agent", "cilium-operator"}}, nil
+	default:
+		return nil, fmt.Errorf("unable to renew unknown certificate %q", name)
+	}
+}
+
+func (b *CertificateRenewalBuilder) buildSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Renew the certificates issued by kops-controller")
+	manifest.Set("Unit", "Documentation", "https://kops.sigs.k8s.io")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "ExecStart", b.NodeupPath+" --renew-certificates="+CertificateRenewalConfigPath+" --v=2")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", certificateRenewalService, manifestString)
+
+	// The service is started by the timer only
+	service := &nodetasks.Service{
+		Name:        certificateRenewalService,
+		Definition:  s(manifestString),
+		ManageState: fi.Bool(false),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+func (b *CertificateRenewalBuilder) buildSystemdTimer() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Check daily whether the certificates issued by kops-controller need renewing")
+	manifest.Set("Unit", "Documentation", "https://kops.sigs.k8s.io")
+	manifest.Set("Timer", "OnCalendar", "daily")
+	manifest.Set("Timer", "RandomizedDelaySec", "1h")
+	manifest.Set("Timer", "Unit", certificateRenewalService)
+	manifest.Set("Install", "WantedBy", "timers.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built timer manifest %q\n%s", certificateRenewalTimer, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       certificateRenewalTimer,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
@@ -78,6 +78,11 @@ func (b *KopsControllerBuilder) Build(c *fi.ModelBuilderContext) error {
 		Owner:    s(wellknownusers.KopsControllerName),
 	})
 
+	// kops-controller has certificates issued by the CAs held by Vault itself
+	if model.UseVaultPKI(b.Cluster) {
+		return nil
+	}
+
 	caList := []string{fi.CertificateIDCA}
 	if model.UseCiliumEtcd(b.Cluster) {
 		caList = append(caList, "etcd-clients-ca-cilium")
@@ -124,6 +124,17 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 		kubeAPIServer.EtcdKeyFile = filepath.Join(b.PathSrvKubernetes(), "etcd-client-key.pem")
 	}
 
+	if b.NodeupConfig.CAs[fi.CertificateIDUsersCA] != "" && kubeAPIServer.ClientCAFile == "" {
+		// Client certificates are accepted from both the cluster CA and the users CA
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join(pathSrvKAPI, "client-ca.crt"),
+			Contents: fi.NewStringResource(b.NodeupConfig.CAs[fi.CertificateIDCA] + b.NodeupConfig.CAs[fi.CertificateIDUsersCA]),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("0644"),
+		})
+		kubeAPIServer.ClientCAFile = filepath.Join(pathSrvKAPI, "client-ca.crt")
+	}
+
 	{
 		c.AddTask(&nodetasks.File{
 			Path:     filepath.Join(pathSrvKAPI, "apiserver-aggregator-ca.crt"),
@@ -344,6 +355,11 @@ func (b *KubeAPIServerBuilder) writeAuthenticationConfig(c *fi.ModelBuilderConte
 		return nil
 	}
 
+	if b.Cluster.Spec.Authentication.OIDC != nil || b.Cluster.Spec.Authentication.Users != nil {
+		// OpenID Connect and user certificates are configured with kube-apiserver flags only
+		return nil
+	}
+
 	return fmt.Errorf("unrecognized authentication config %v", b.Cluster.Spec.Authentication)
 }
 
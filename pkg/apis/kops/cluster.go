@@ -94,6 +94,9 @@ type ClusterSpec struct {
 	KeyStore string `json:"keyStore,omitempty"`
 	// ConfigStore is the VFS path to where the configuration (Cluster, InstanceGroups etc) is stored
 	ConfigStore string `json:"configStore,omitempty"`
+	// StateEncryptionKey is the key that private keys and secrets in the state store are encrypted with,
+	// e.g. aws-kms://<key ARN>. On AWS, the control plane and nodes are allowed to decrypt with it.
+	StateEncryptionKey string `json:"stateEncryptionKey,omitempty"`
 	// DNSZone is the DNS zone we should use when configuring DNS
 	// This is because some clouds let us define a managed zone foo.bar, and then have
 	// kubernetes.dev.foo.bar, without needing to define dev.foo.bar as a hosted zone.
@@ -214,6 +217,101 @@ type ClusterSpec struct {
 
 	// SnapshotController defines the CSI Snapshot Controller configuration.
 	SnapshotController *SnapshotControllerConfig `json:"snapshotController,omitempty"`
+
+	// Validation configures the checks run when validating the cluster.
+	Validation *ClusterValidationSpec `json:"validation,omitempty"`
+
+	// NodeBootstrapAdmission configures which node bootstrap requests kops-controller issues certificates for.
+	NodeBootstrapAdmission *NodeBootstrapAdmissionSpec `json:"nodeBootstrapAdmission,omitempty"`
+
+	// AutoApply configures kops-controller to apply changes to the cluster, as `kops update cluster --yes` does.
+	AutoApply *AutoApplySpec `json:"autoApply,omitempty"`
+}
+
+// ClusterValidationSpec configures the checks run when validating the cluster.
+type ClusterValidationSpec struct {
+	// Validators lists the names of the built-in validators to run, for example "etcd".
+	// Built-in validators are not run unless listed.
+	Validators []string `json:"validators,omitempty"`
+	// Checks are additional checks that must pass for the cluster to validate.
+	Checks []ClusterValidationCheck `json:"checks,omitempty"`
+}
+
+// ClusterValidationCheck is a user-defined check run when validating the cluster.
+// Exactly one of Workload and HTTPGet must be set.
+type ClusterValidationCheck struct {
+	// Name identifies the check in validation failures.
+	Name string `json:"name"`
+	// Workload requires all the replicas of a Deployment, DaemonSet or StatefulSet to be ready.
+	Workload *ValidationWorkloadCheck `json:"workload,omitempty"`
+	// HTTPGet requires an HTTP GET request to a URL to return a 2xx status code.
+	HTTPGet *ValidationHTTPGetCheck `json:"httpGet,omitempty"`
+}
+
+// ValidationWorkloadCheck identifies a workload whose replicas must be ready.
+type ValidationWorkloadCheck struct {
+	// Kind is the kind of the workload: Deployment, DaemonSet or StatefulSet.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the workload. Defaults to "default".
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the workload.
+	Name string `json:"name"`
+}
+
+// ValidationHTTPGetCheck identifies a URL that must respond successfully.
+type ValidationHTTPGetCheck struct {
+	// URL is the http or https URL to request.
+	URL string `json:"url"`
+}
+
+// NodeBootstrapAdmissionSpec configures the admission of node bootstrap requests by kops-controller.
+// Requests must pass all of the configured checks.
+type NodeBootstrapAdmissionSpec struct {
+	// InstanceGroups lists the names of the instance groups whose nodes may bootstrap.
+	// Defaults to all instance groups.
+	InstanceGroups []string `json:"instanceGroups,omitempty"`
+	// EnforceMaxSize denies requests from new nodes of an instance group that already has maxSize nodes.
+	EnforceMaxSize bool `json:"enforceMaxSize,omitempty"`
+	// MaxSizeSurge is the number of nodes an instance group may have above its maxSize when EnforceMaxSize is set,
+	// for example to allow surging during rolling updates.
+	MaxSizeSurge int32 `json:"maxSizeSurge,omitempty"`
+	// RequestsPerMinute limits the rate of bootstrap requests admitted by each kops-controller.
+	RequestsPerMinute int32 `json:"requestsPerMinute,omitempty"`
+	// Burst is the number of requests admitted at once before RequestsPerMinute applies. Defaults to 1.
+	Burst int32 `json:"burst,omitempty"`
+	// WebhookURL is the https URL of a webhook that is asked to admit each request.
+	WebhookURL string `json:"webhookURL,omitempty"`
+	// WebhookTimeout is how long to wait for the webhook before denying the request. Defaults to 10s.
+	WebhookTimeout *metav1.Duration `json:"webhookTimeout,omitempty"`
+}
+
+// AutoApplySpec configures kops-controller to periodically apply the cluster and instance group specs in the
+// state store to the cloud. Each phase of the update is applied according to its policy.
+type AutoApplySpec struct {
+	// Interval is how often the specs are checked against the cloud. Defaults to 10m.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Network is the policy for the network phase, which manages the VPC, subnets and gateways. Defaults to Never.
+	Network AutoApplyPolicy `json:"network,omitempty"`
+	// Security is the policy for the security phase, which manages IAM roles and security groups. Defaults to Never.
+	Security AutoApplyPolicy `json:"security,omitempty"`
+	// Cluster is the policy for the cluster phase, which manages the instance groups, load balancers
+	// and the other cluster resources. Defaults to Auto.
+	Cluster AutoApplyPolicy `json:"cluster,omitempty"`
+}
+
+// AutoApplyPolicy is whether kops-controller applies the changes of a phase.
+type AutoApplyPolicy string
+
+const (
+	// AutoApplyPolicyAuto applies the changes of the phase.
+	AutoApplyPolicyAuto AutoApplyPolicy = "Auto"
+	// AutoApplyPolicyNever does not apply the changes of the phase; they must be applied with `kops update cluster`.
+	AutoApplyPolicyNever AutoApplyPolicy = "Never"
+)
+
+var SupportedAutoApplyPolicies = []string{
+	string(AutoApplyPolicyAuto),
+	string(AutoApplyPolicyNever),
 }
 
 // ServiceAccountIssuerDiscoveryConfig configures an OIDC Issuer.
@@ -343,10 +441,12 @@ type ExecContainerAction struct {
 type AuthenticationSpec struct {
 	Kopeio *KopeioAuthenticationSpec `json:"kopeio,omitempty"`
 	Aws    *AwsAuthenticationSpec    `json:"aws,omitempty"`
+	OIDC   *OIDCAuthenticationSpec   `json:"oidc,omitempty"`
+	Users  *UserAuthenticationSpec   `json:"users,omitempty"`
 }
 
 func (s *AuthenticationSpec) IsEmpty() bool {
-	return s.Kopeio == nil && s.Aws == nil
+	return s.Kopeio == nil && s.Aws == nil && s.OIDC == nil && s.Users == nil
 }
 
 type KopeioAuthenticationSpec struct {
@@ -369,6 +469,41 @@ type AwsAuthenticationSpec struct {
 	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
 }
 
+// OIDCAuthenticationSpec configures authentication with an OpenID Connect identity provider.
+type OIDCAuthenticationSpec struct {
+	// IssuerURL is the URL of the OpenID issuer. Only the https scheme is accepted.
+	IssuerURL string `json:"issuerURL,omitempty"`
+	// ClientID is the client ID of the OpenID Connect client the tokens must be issued for.
+	ClientID string `json:"clientID,omitempty"`
+	// UsernameClaim is the OpenID claim to use as the user name. Default sub
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// UsernamePrefix is prepended to user names to prevent clashes with existing names.
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	// GroupsClaim is the OpenID claim to use as the groups of the user.
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// GroupsPrefix is prepended to group names to prevent clashes with existing names.
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+	// ExtraScopes are the scopes kubectl requests in addition to openid. Defaults to the groups claim, if set.
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+}
+
+// UserAuthenticationSpec configures the short-lived client certificates issued to users by kops export kubecfg.
+// The certificates are signed by the users-ca keyset, which can be rotated and distrusted independently of the cluster CA.
+type UserAuthenticationSpec struct {
+	// MaxValidity is the maximum validity of the certificates issued to users. Default 24h
+	MaxValidity *metav1.Duration `json:"maxValidity,omitempty"`
+	// GroupRoles binds groups of users to ClusterRoles.
+	GroupRoles []UserGroupRoles `json:"groupRoles,omitempty"`
+}
+
+// UserGroupRoles binds the users issued certificates for a group to ClusterRoles.
+type UserGroupRoles struct {
+	// Group is the name of the group.
+	Group string `json:"group,omitempty"`
+	// ClusterRoles are the names of the ClusterRoles the group is bound to.
+	ClusterRoles []string `json:"clusterRoles,omitempty"`
+}
+
 type AuthorizationSpec struct {
 	AlwaysAllow *AlwaysAllowAuthorizationSpec `json:"alwaysAllow,omitempty"`
 	RBAC        *RBACAuthorizationSpec        `json:"rbac,omitempty"`
@@ -671,10 +806,18 @@ func (t *TargetSpec) IsEmpty() bool {
 type TerraformSpec struct {
 	// ProviderExtraConfig contains key/value pairs to add to the rendered terraform "provider" block
 	ProviderExtraConfig *map[string]string `json:"providerExtraConfig,omitempty"`
+	// Module writes the terraform configuration as a module, to be used from other terraform configurations
+	Module *TerraformModuleSpec `json:"module,omitempty"`
 }
 
 func (t *TerraformSpec) IsEmpty() bool {
-	return t.ProviderExtraConfig == nil
+	return t.ProviderExtraConfig == nil && t.Module == nil
+}
+
+// TerraformModuleSpec configures the terraform module written for the cluster
+type TerraformModuleSpec struct {
+	// ProviderAlias is the alias of the provider configuration the module expects to be passed, if any
+	ProviderAlias string `json:"providerAlias,omitempty"`
 }
 
 // FillDefaults populates default values.
@@ -874,6 +1017,76 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Hooks are user-defined checks run around the replacement of each instance.
+	// A failing hook stops the rolling update unless its failurePolicy is Ignore.
+	// +optional
+	Hooks []RollingUpdateHook `json:"hooks,omitempty"`
+	// Canary, if set, replaces a few instances of the group first and holds them for a soak period
+	// before replacing the remaining instances. If the cluster fails validation during the canary phase,
+	// the group is rolled back to its previous instance specification.
+	// +optional
+	Canary *RollingUpdateCanary `json:"canary,omitempty"`
+}
+
+// RollingUpdateHookPhase is a point of the replacement of an instance at which a RollingUpdateHook is run.
+type RollingUpdateHookPhase string
+
+const (
+	// RollingUpdateHookBeforeDrain runs the hook before the node of an instance is drained.
+	RollingUpdateHookBeforeDrain RollingUpdateHookPhase = "BeforeDrain"
+	// RollingUpdateHookAfterTerminate runs the hook after an instance has been terminated.
+	RollingUpdateHookAfterTerminate RollingUpdateHookPhase = "AfterTerminate"
+	// RollingUpdateHookAfterValidate runs the hook after the cluster has validated following the replacement of instances.
+	RollingUpdateHookAfterValidate RollingUpdateHookPhase = "AfterValidate"
+)
+
+// RollingUpdateHookFailurePolicy determines how a rolling update handles the failure of a RollingUpdateHook.
+type RollingUpdateHookFailurePolicy string
+
+const (
+	// RollingUpdateHookFailurePolicyFail stops the rolling update when the hook fails.
+	RollingUpdateHookFailurePolicyFail RollingUpdateHookFailurePolicy = "Fail"
+	// RollingUpdateHookFailurePolicyIgnore logs the failure of the hook and continues the rolling update.
+	RollingUpdateHookFailurePolicyIgnore RollingUpdateHookFailurePolicy = "Ignore"
+)
+
+// RollingUpdateHook is a user-defined check run during a rolling update.
+// Exactly one of Exec or Webhook must be set.
+type RollingUpdateHook struct {
+	// Name identifies the hook in logs and errors.
+	Name string `json:"name,omitempty"`
+	// Phases are the points at which the hook is run. Defaults to all phases.
+	Phases []RollingUpdateHookPhase `json:"phases,omitempty"`
+	// Exec is a command, run on the machine performing the rolling update.
+	// The phase, cluster, instance group, instance ID and node name are passed in the
+	// KOPS_HOOK_PHASE, KOPS_CLUSTER_NAME, KOPS_INSTANCE_GROUP, KOPS_INSTANCE_ID and KOPS_NODE_NAME environment variables.
+	// The hook fails if the command exits with a non-zero status.
+	Exec []string `json:"exec,omitempty"`
+	// Webhook is a URL to which the phase, cluster, instance group, instance ID and node name are POSTed as JSON.
+	// The hook fails if the response status is not 2xx.
+	Webhook string `json:"webhook,omitempty"`
+	// Timeout is the maximum time the hook may take. Defaults to 5 minutes.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailurePolicy is Fail to stop the rolling update when the hook fails, or Ignore to continue. Defaults to Fail.
+	FailurePolicy RollingUpdateHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// RollingUpdateCanary configures the canary phase of a rolling update.
+type RollingUpdateCanary struct {
+	// Instances is the number of instances replaced during the canary phase.
+	// The value can be an absolute number (for example 1) or a percentage of the
+	// instances needing update (for example 10%), calculated by rounding up.
+	// Defaults to 1.
+	// +optional
+	Instances *intstr.IntOrString `json:"instances,omitempty"`
+	// SoakDuration is how long the cluster must keep passing validation, and the AfterValidate
+	// hooks, after the canaries have been replaced. Defaults to 5 minutes.
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+	// Rollback restores the previous launch template version (AWS) or instance template (GCE)
+	// and replaces the canaries again if the canary phase fails. Defaults to true.
+	// +optional
+	Rollback *bool `json:"rollback,omitempty"`
 }
 
 type PackagesConfig struct {
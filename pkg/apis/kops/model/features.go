@@ -17,6 +17,8 @@ limitations under the License.
 package model
 
 import (
+	"net/url"
+
 	"k8s.io/kops/pkg/apis/kops"
 )
 
@@ -39,3 +41,12 @@ func UseCiliumEtcd(cluster *kops.Cluster) bool {
 
 	return false
 }
+
+// UseVaultPKI is true if the keystore is in Vault and the PKI secrets engine holds CAs.
+func UseVaultPKI(cluster *kops.Cluster) bool {
+	u, err := url.Parse(cluster.Spec.KeyStore)
+	if err != nil || u.Scheme != "vault" {
+		return false
+	}
+	return u.Query().Get("pki") != ""
+}
@@ -16,9 +16,46 @@ limitations under the License.
 
 package kops
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterConditionApplied is true if the cluster spec was fully applied by the last `kops update cluster`.
+	ClusterConditionApplied = "Applied"
+	// ClusterConditionRollingUpdateSucceeded is true if the last rolling update completed.
+	ClusterConditionRollingUpdateSucceeded = "RollingUpdateSucceeded"
+	// InstanceGroupConditionInstancesUpToDate is true if no instance of the group needed updating after the last rolling update.
+	InstanceGroupConditionInstancesUpToDate = "InstancesUpToDate"
+
+	// RollingUpdateReasonSucceeded is the reason of the rolling update conditions when the rolling update completed
+	RollingUpdateReasonSucceeded = "RollingUpdateSucceeded"
+	// RollingUpdateReasonFailed is the reason of the rolling update conditions when the rolling update failed
+	RollingUpdateReasonFailed = "RollingUpdateFailed"
+	// RollingUpdateReasonStopped is the reason of the instance group condition when the rolling update stopped before the group
+	RollingUpdateReasonStopped = "RollingUpdateStopped"
+)
+
 type ClusterStatus struct {
 	// EtcdClusters stores the status for each cluster
 	EtcdClusters []EtcdClusterStatus `json:"etcdClusters,omitempty"`
+
+	// AppliedSpecHash is the hash of the cluster and instance group specs last applied in full
+	AppliedSpecHash string `json:"appliedSpecHash,omitempty"`
+	// AppliedKopsVersion is the version of kops that last applied the cluster
+	AppliedKopsVersion string `json:"appliedKopsVersion,omitempty"`
+	// Conditions are the outcomes of the last apply and the last rolling update
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// InstanceGroupStatus is the status of an instance group, as recorded by the last rolling update.
+type InstanceGroupStatus struct {
+	// Instances is the number of instances in the group
+	Instances int32 `json:"instances"`
+	// NeedUpdate is the number of instances that still needed updating
+	NeedUpdate int32 `json:"needUpdate"`
+	// Conditions are the outcomes of the last rolling update of the group
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // EtcdClusterStatus represents the status of etcd: because etcd only allows limited reconfiguration, we have to block changes once etcd has been initialized.
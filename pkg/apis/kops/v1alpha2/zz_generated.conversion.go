@@ -133,6 +133,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AutoApplySpec)(nil), (*kops.AutoApplySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AutoApplySpec_To_kops_AutoApplySpec(a.(*AutoApplySpec), b.(*kops.AutoApplySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AutoApplySpec)(nil), (*AutoApplySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AutoApplySpec_To_v1alpha2_AutoApplySpec(a.(*kops.AutoApplySpec), b.(*AutoApplySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AwsAuthenticationSpec)(nil), (*kops.AwsAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AwsAuthenticationSpec_To_kops_AwsAuthenticationSpec(a.(*AwsAuthenticationSpec), b.(*kops.AwsAuthenticationSpec), scope)
 	}); err != nil {
@@ -303,6 +313,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterValidationCheck)(nil), (*kops.ClusterValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ClusterValidationCheck_To_kops_ClusterValidationCheck(a.(*ClusterValidationCheck), b.(*kops.ClusterValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ClusterValidationCheck)(nil), (*ClusterValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ClusterValidationCheck_To_v1alpha2_ClusterValidationCheck(a.(*kops.ClusterValidationCheck), b.(*ClusterValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ClusterValidationSpec)(nil), (*kops.ClusterValidationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(a.(*ClusterValidationSpec), b.(*kops.ClusterValidationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ClusterValidationSpec)(nil), (*ClusterValidationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(a.(*kops.ClusterValidationSpec), b.(*ClusterValidationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ContainerdConfig)(nil), (*kops.ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(a.(*ContainerdConfig), b.(*kops.ContainerdConfig), scope)
 	}); err != nil {
@@ -843,6 +873,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeBootstrapAdmissionSpec)(nil), (*kops.NodeBootstrapAdmissionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeBootstrapAdmissionSpec_To_kops_NodeBootstrapAdmissionSpec(a.(*NodeBootstrapAdmissionSpec), b.(*kops.NodeBootstrapAdmissionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeBootstrapAdmissionSpec)(nil), (*NodeBootstrapAdmissionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeBootstrapAdmissionSpec_To_v1alpha2_NodeBootstrapAdmissionSpec(a.(*kops.NodeBootstrapAdmissionSpec), b.(*NodeBootstrapAdmissionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -873,6 +913,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OIDCAuthenticationSpec)(nil), (*kops.OIDCAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(a.(*OIDCAuthenticationSpec), b.(*kops.OIDCAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.OIDCAuthenticationSpec)(nil), (*OIDCAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(a.(*kops.OIDCAuthenticationSpec), b.(*OIDCAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackBlockStorageConfig)(nil), (*kops.OpenstackBlockStorageConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(a.(*OpenstackBlockStorageConfig), b.(*kops.OpenstackBlockStorageConfig), scope)
 	}); err != nil {
@@ -963,6 +1013,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateCanary)(nil), (*kops.RollingUpdateCanary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(a.(*RollingUpdateCanary), b.(*kops.RollingUpdateCanary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateCanary)(nil), (*RollingUpdateCanary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(a.(*kops.RollingUpdateCanary), b.(*RollingUpdateCanary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateHook)(nil), (*kops.RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(a.(*RollingUpdateHook), b.(*kops.RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateHook)(nil), (*RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(a.(*kops.RollingUpdateHook), b.(*RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
 	}); err != nil {
@@ -1043,6 +1113,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TerraformModuleSpec)(nil), (*kops.TerraformModuleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TerraformModuleSpec_To_kops_TerraformModuleSpec(a.(*TerraformModuleSpec), b.(*kops.TerraformModuleSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TerraformModuleSpec)(nil), (*TerraformModuleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TerraformModuleSpec_To_v1alpha2_TerraformModuleSpec(a.(*kops.TerraformModuleSpec), b.(*TerraformModuleSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TerraformSpec)(nil), (*kops.TerraformSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(a.(*TerraformSpec), b.(*kops.TerraformSpec), scope)
 	}); err != nil {
@@ -1063,6 +1143,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*UserAuthenticationSpec)(nil), (*kops.UserAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_UserAuthenticationSpec_To_kops_UserAuthenticationSpec(a.(*UserAuthenticationSpec), b.(*kops.UserAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.UserAuthenticationSpec)(nil), (*UserAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_UserAuthenticationSpec_To_v1alpha2_UserAuthenticationSpec(a.(*kops.UserAuthenticationSpec), b.(*UserAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*UserData)(nil), (*kops.UserData)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_UserData_To_kops_UserData(a.(*UserData), b.(*kops.UserData), scope)
 	}); err != nil {
@@ -1073,6 +1163,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*UserGroupRoles)(nil), (*kops.UserGroupRoles)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_UserGroupRoles_To_kops_UserGroupRoles(a.(*UserGroupRoles), b.(*kops.UserGroupRoles), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.UserGroupRoles)(nil), (*UserGroupRoles)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_UserGroupRoles_To_v1alpha2_UserGroupRoles(a.(*kops.UserGroupRoles), b.(*UserGroupRoles), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ValidationHTTPGetCheck)(nil), (*kops.ValidationHTTPGetCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ValidationHTTPGetCheck_To_kops_ValidationHTTPGetCheck(a.(*ValidationHTTPGetCheck), b.(*kops.ValidationHTTPGetCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ValidationHTTPGetCheck)(nil), (*ValidationHTTPGetCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ValidationHTTPGetCheck_To_v1alpha2_ValidationHTTPGetCheck(a.(*kops.ValidationHTTPGetCheck), b.(*ValidationHTTPGetCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ValidationWorkloadCheck)(nil), (*kops.ValidationWorkloadCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ValidationWorkloadCheck_To_kops_ValidationWorkloadCheck(a.(*ValidationWorkloadCheck), b.(*kops.ValidationWorkloadCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ValidationWorkloadCheck)(nil), (*ValidationWorkloadCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ValidationWorkloadCheck_To_v1alpha2_ValidationWorkloadCheck(a.(*kops.ValidationWorkloadCheck), b.(*ValidationWorkloadCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -1363,6 +1483,24 @@ func autoConvert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(in *Auth
 	} else {
 		out.Aws = nil
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(kops.OIDCAuthenticationSpec)
+		if err := Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OIDC = nil
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(kops.UserAuthenticationSpec)
+		if err := Convert_v1alpha2_UserAuthenticationSpec_To_kops_UserAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Users = nil
+	}
 	return nil
 }
 
@@ -1390,6 +1528,24 @@ func autoConvert_kops_AuthenticationSpec_To_v1alpha2_AuthenticationSpec(in *kops
 	} else {
 		out.Aws = nil
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCAuthenticationSpec)
+		if err := Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OIDC = nil
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(UserAuthenticationSpec)
+		if err := Convert_kops_UserAuthenticationSpec_To_v1alpha2_UserAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Users = nil
+	}
 	return nil
 }
 
@@ -1452,6 +1608,32 @@ func Convert_kops_AuthorizationSpec_To_v1alpha2_AuthorizationSpec(in *kops.Autho
 	return autoConvert_kops_AuthorizationSpec_To_v1alpha2_AuthorizationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_AutoApplySpec_To_kops_AutoApplySpec(in *AutoApplySpec, out *kops.AutoApplySpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Network = kops.AutoApplyPolicy(in.Network)
+	out.Security = kops.AutoApplyPolicy(in.Security)
+	out.Cluster = kops.AutoApplyPolicy(in.Cluster)
+	return nil
+}
+
+// Convert_v1alpha2_AutoApplySpec_To_kops_AutoApplySpec is an autogenerated conversion function.
+func Convert_v1alpha2_AutoApplySpec_To_kops_AutoApplySpec(in *AutoApplySpec, out *kops.AutoApplySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AutoApplySpec_To_kops_AutoApplySpec(in, out, s)
+}
+
+func autoConvert_kops_AutoApplySpec_To_v1alpha2_AutoApplySpec(in *kops.AutoApplySpec, out *AutoApplySpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Network = AutoApplyPolicy(in.Network)
+	out.Security = AutoApplyPolicy(in.Security)
+	out.Cluster = AutoApplyPolicy(in.Cluster)
+	return nil
+}
+
+// Convert_kops_AutoApplySpec_To_v1alpha2_AutoApplySpec is an autogenerated conversion function.
+func Convert_kops_AutoApplySpec_To_v1alpha2_AutoApplySpec(in *kops.AutoApplySpec, out *AutoApplySpec, s conversion.Scope) error {
+	return autoConvert_kops_AutoApplySpec_To_v1alpha2_AutoApplySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AwsAuthenticationSpec_To_kops_AwsAuthenticationSpec(in *AwsAuthenticationSpec, out *kops.AwsAuthenticationSpec, s conversion.Scope) error {
 	out.Image = in.Image
 	out.BackendMode = in.BackendMode
@@ -2293,6 +2475,7 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
 	out.ConfigStore = in.ConfigStore
+	out.StateEncryptionKey = in.StateEncryptionKey
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
 		in, out := &in.DNSControllerGossipConfig, &out.DNSControllerGossipConfig
@@ -2642,6 +2825,33 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.SnapshotController = nil
 	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(kops.ClusterValidationSpec)
+		if err := Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Validation = nil
+	}
+	if in.NodeBootstrapAdmission != nil {
+		in, out := &in.NodeBootstrapAdmission, &out.NodeBootstrapAdmission
+		*out = new(kops.NodeBootstrapAdmissionSpec)
+		if err := Convert_v1alpha2_NodeBootstrapAdmissionSpec_To_kops_NodeBootstrapAdmissionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeBootstrapAdmission = nil
+	}
+	if in.AutoApply != nil {
+		in, out := &in.AutoApply, &out.AutoApply
+		*out = new(kops.AutoApplySpec)
+		if err := Convert_v1alpha2_AutoApplySpec_To_kops_AutoApplySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AutoApply = nil
+	}
 	return nil
 }
 
@@ -2705,6 +2915,7 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
 	out.ConfigStore = in.ConfigStore
+	out.StateEncryptionKey = in.StateEncryptionKey
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
 		in, out := &in.DNSControllerGossipConfig, &out.DNSControllerGossipConfig
@@ -3054,6 +3265,33 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.SnapshotController = nil
 	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ClusterValidationSpec)
+		if err := Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Validation = nil
+	}
+	if in.NodeBootstrapAdmission != nil {
+		in, out := &in.NodeBootstrapAdmission, &out.NodeBootstrapAdmission
+		*out = new(NodeBootstrapAdmissionSpec)
+		if err := Convert_kops_NodeBootstrapAdmissionSpec_To_v1alpha2_NodeBootstrapAdmissionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeBootstrapAdmission = nil
+	}
+	if in.AutoApply != nil {
+		in, out := &in.AutoApply, &out.AutoApply
+		*out = new(AutoApplySpec)
+		if err := Convert_kops_AutoApplySpec_To_v1alpha2_AutoApplySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AutoApply = nil
+	}
 	return nil
 }
 
@@ -3098,6 +3336,104 @@ func Convert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in *kops.Clust
 	return autoConvert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ClusterValidationCheck_To_kops_ClusterValidationCheck(in *ClusterValidationCheck, out *kops.ClusterValidationCheck, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(kops.ValidationWorkloadCheck)
+		if err := Convert_v1alpha2_ValidationWorkloadCheck_To_kops_ValidationWorkloadCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Workload = nil
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(kops.ValidationHTTPGetCheck)
+		if err := Convert_v1alpha2_ValidationHTTPGetCheck_To_kops_ValidationHTTPGetCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTPGet = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ClusterValidationCheck_To_kops_ClusterValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_ClusterValidationCheck_To_kops_ClusterValidationCheck(in *ClusterValidationCheck, out *kops.ClusterValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ClusterValidationCheck_To_kops_ClusterValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_ClusterValidationCheck_To_v1alpha2_ClusterValidationCheck(in *kops.ClusterValidationCheck, out *ClusterValidationCheck, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(ValidationWorkloadCheck)
+		if err := Convert_kops_ValidationWorkloadCheck_To_v1alpha2_ValidationWorkloadCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Workload = nil
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(ValidationHTTPGetCheck)
+		if err := Convert_kops_ValidationHTTPGetCheck_To_v1alpha2_ValidationHTTPGetCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTPGet = nil
+	}
+	return nil
+}
+
+// Convert_kops_ClusterValidationCheck_To_v1alpha2_ClusterValidationCheck is an autogenerated conversion function.
+func Convert_kops_ClusterValidationCheck_To_v1alpha2_ClusterValidationCheck(in *kops.ClusterValidationCheck, out *ClusterValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_ClusterValidationCheck_To_v1alpha2_ClusterValidationCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(in *ClusterValidationSpec, out *kops.ClusterValidationSpec, s conversion.Scope) error {
+	out.Validators = in.Validators
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]kops.ClusterValidationCheck, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ClusterValidationCheck_To_kops_ClusterValidationCheck(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Checks = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(in *ClusterValidationSpec, out *kops.ClusterValidationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(in, out, s)
+}
+
+func autoConvert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(in *kops.ClusterValidationSpec, out *ClusterValidationSpec, s conversion.Scope) error {
+	out.Validators = in.Validators
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ClusterValidationCheck, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ClusterValidationCheck_To_v1alpha2_ClusterValidationCheck(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Checks = nil
+	}
+	return nil
+}
+
+// Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec is an autogenerated conversion function.
+func Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(in *kops.ClusterValidationSpec, out *ClusterValidationSpec, s conversion.Scope) error {
+	return autoConvert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
 	out.Address = in.Address
 	out.ConfigOverride = in.ConfigOverride
@@ -5946,6 +6282,38 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeBootstrapAdmissionSpec_To_kops_NodeBootstrapAdmissionSpec(in *NodeBootstrapAdmissionSpec, out *kops.NodeBootstrapAdmissionSpec, s conversion.Scope) error {
+	out.InstanceGroups = in.InstanceGroups
+	out.EnforceMaxSize = in.EnforceMaxSize
+	out.MaxSizeSurge = in.MaxSizeSurge
+	out.RequestsPerMinute = in.RequestsPerMinute
+	out.Burst = in.Burst
+	out.WebhookURL = in.WebhookURL
+	out.WebhookTimeout = in.WebhookTimeout
+	return nil
+}
+
+// Convert_v1alpha2_NodeBootstrapAdmissionSpec_To_kops_NodeBootstrapAdmissionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeBootstrapAdmissionSpec_To_kops_NodeBootstrapAdmissionSpec(in *NodeBootstrapAdmissionSpec, out *kops.NodeBootstrapAdmissionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeBootstrapAdmissionSpec_To_kops_NodeBootstrapAdmissionSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeBootstrapAdmissionSpec_To_v1alpha2_NodeBootstrapAdmissionSpec(in *kops.NodeBootstrapAdmissionSpec, out *NodeBootstrapAdmissionSpec, s conversion.Scope) error {
+	out.InstanceGroups = in.InstanceGroups
+	out.EnforceMaxSize = in.EnforceMaxSize
+	out.MaxSizeSurge = in.MaxSizeSurge
+	out.RequestsPerMinute = in.RequestsPerMinute
+	out.Burst = in.Burst
+	out.WebhookURL = in.WebhookURL
+	out.WebhookTimeout = in.WebhookTimeout
+	return nil
+}
+
+// Convert_kops_NodeBootstrapAdmissionSpec_To_v1alpha2_NodeBootstrapAdmissionSpec is an autogenerated conversion function.
+func Convert_kops_NodeBootstrapAdmissionSpec_To_v1alpha2_NodeBootstrapAdmissionSpec(in *kops.NodeBootstrapAdmissionSpec, out *NodeBootstrapAdmissionSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeBootstrapAdmissionSpec_To_v1alpha2_NodeBootstrapAdmissionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.LocalIP = in.LocalIP
@@ -6038,6 +6406,38 @@ func Convert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandle
 	return autoConvert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in *OIDCAuthenticationSpec, out *kops.OIDCAuthenticationSpec, s conversion.Scope) error {
+	out.IssuerURL = in.IssuerURL
+	out.ClientID = in.ClientID
+	out.UsernameClaim = in.UsernameClaim
+	out.UsernamePrefix = in.UsernamePrefix
+	out.GroupsClaim = in.GroupsClaim
+	out.GroupsPrefix = in.GroupsPrefix
+	out.ExtraScopes = in.ExtraScopes
+	return nil
+}
+
+// Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in *OIDCAuthenticationSpec, out *kops.OIDCAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in, out, s)
+}
+
+func autoConvert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in *kops.OIDCAuthenticationSpec, out *OIDCAuthenticationSpec, s conversion.Scope) error {
+	out.IssuerURL = in.IssuerURL
+	out.ClientID = in.ClientID
+	out.UsernameClaim = in.UsernameClaim
+	out.UsernamePrefix = in.UsernamePrefix
+	out.GroupsClaim = in.GroupsClaim
+	out.GroupsPrefix = in.GroupsPrefix
+	out.ExtraScopes = in.ExtraScopes
+	return nil
+}
+
+// Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec is an autogenerated conversion function.
+func Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in *kops.OIDCAuthenticationSpec, out *OIDCAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(in *OpenstackBlockStorageConfig, out *kops.OpenstackBlockStorageConfig, s conversion.Scope) error {
 	out.Version = in.Version
 	out.IgnoreAZ = in.IgnoreAZ
@@ -6330,6 +6730,26 @@ func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]kops.RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(kops.RollingUpdateCanary)
+		if err := Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Canary = nil
+	}
 	return nil
 }
 
@@ -6342,6 +6762,26 @@ func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(RollingUpdateCanary)
+		if err := Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Canary = nil
+	}
 	return nil
 }
 
@@ -6350,6 +6790,76 @@ func Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUpdate
 	return autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in, out, s)
 }
 
+func autoConvert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(in *RollingUpdateCanary, out *kops.RollingUpdateCanary, s conversion.Scope) error {
+	out.Instances = in.Instances
+	out.SoakDuration = in.SoakDuration
+	out.Rollback = in.Rollback
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(in *RollingUpdateCanary, out *kops.RollingUpdateCanary, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(in *kops.RollingUpdateCanary, out *RollingUpdateCanary, s conversion.Scope) error {
+	out.Instances = in.Instances
+	out.SoakDuration = in.SoakDuration
+	out.Rollback = in.Rollback
+	return nil
+}
+
+// Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary is an autogenerated conversion function.
+func Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(in *kops.RollingUpdateCanary, out *RollingUpdateCanary, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(in, out, s)
+}
+
+func autoConvert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]kops.RollingUpdateHookPhase, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.RollingUpdateHookPhase((*in)[i])
+		}
+	} else {
+		out.Phases = nil
+	}
+	out.Exec = in.Exec
+	out.Webhook = in.Webhook
+	out.Timeout = in.Timeout
+	out.FailurePolicy = kops.RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]RollingUpdateHookPhase, len(*in))
+		for i := range *in {
+			(*out)[i] = RollingUpdateHookPhase((*in)[i])
+		}
+	} else {
+		out.Phases = nil
+	}
+	out.Exec = in.Exec
+	out.Webhook = in.Webhook
+	out.Timeout = in.Timeout
+	out.FailurePolicy = RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(in *RomanaNetworkingSpec, out *kops.RomanaNetworkingSpec, s conversion.Scope) error {
 	out.DaemonServiceIP = in.DaemonServiceIP
 	out.EtcdServiceIP = in.EtcdServiceIP
@@ -6580,8 +7090,37 @@ func Convert_kops_TargetSpec_To_v1alpha2_TargetSpec(in *kops.TargetSpec, out *Ta
 	return autoConvert_kops_TargetSpec_To_v1alpha2_TargetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_TerraformModuleSpec_To_kops_TerraformModuleSpec(in *TerraformModuleSpec, out *kops.TerraformModuleSpec, s conversion.Scope) error {
+	out.ProviderAlias = in.ProviderAlias
+	return nil
+}
+
+// Convert_v1alpha2_TerraformModuleSpec_To_kops_TerraformModuleSpec is an autogenerated conversion function.
+func Convert_v1alpha2_TerraformModuleSpec_To_kops_TerraformModuleSpec(in *TerraformModuleSpec, out *kops.TerraformModuleSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_TerraformModuleSpec_To_kops_TerraformModuleSpec(in, out, s)
+}
+
+func autoConvert_kops_TerraformModuleSpec_To_v1alpha2_TerraformModuleSpec(in *kops.TerraformModuleSpec, out *TerraformModuleSpec, s conversion.Scope) error {
+	out.ProviderAlias = in.ProviderAlias
+	return nil
+}
+
+// Convert_kops_TerraformModuleSpec_To_v1alpha2_TerraformModuleSpec is an autogenerated conversion function.
+func Convert_kops_TerraformModuleSpec_To_v1alpha2_TerraformModuleSpec(in *kops.TerraformModuleSpec, out *TerraformModuleSpec, s conversion.Scope) error {
+	return autoConvert_kops_TerraformModuleSpec_To_v1alpha2_TerraformModuleSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out *kops.TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	if in.Module != nil {
+		in, out := &in.Module, &out.Module
+		*out = new(kops.TerraformModuleSpec)
+		if err := Convert_v1alpha2_TerraformModuleSpec_To_kops_TerraformModuleSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Module = nil
+	}
 	return nil
 }
 
@@ -6592,6 +7131,15 @@ func Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out
 
 func autoConvert_kops_TerraformSpec_To_v1alpha2_TerraformSpec(in *kops.TerraformSpec, out *TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	if in.Module != nil {
+		in, out := &in.Module, &out.Module
+		*out = new(TerraformModuleSpec)
+		if err := Convert_kops_TerraformModuleSpec_To_v1alpha2_TerraformModuleSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Module = nil
+	}
 	return nil
 }
 
@@ -6658,6 +7206,48 @@ func Convert_kops_TopologySpec_To_v1alpha2_TopologySpec(in *kops.TopologySpec, o
 	return autoConvert_kops_TopologySpec_To_v1alpha2_TopologySpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_UserAuthenticationSpec_To_kops_UserAuthenticationSpec(in *UserAuthenticationSpec, out *kops.UserAuthenticationSpec, s conversion.Scope) error {
+	out.MaxValidity = in.MaxValidity
+	if in.GroupRoles != nil {
+		in, out := &in.GroupRoles, &out.GroupRoles
+		*out = make([]kops.UserGroupRoles, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_UserGroupRoles_To_kops_UserGroupRoles(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.GroupRoles = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_UserAuthenticationSpec_To_kops_UserAuthenticationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_UserAuthenticationSpec_To_kops_UserAuthenticationSpec(in *UserAuthenticationSpec, out *kops.UserAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_UserAuthenticationSpec_To_kops_UserAuthenticationSpec(in, out, s)
+}
+
+func autoConvert_kops_UserAuthenticationSpec_To_v1alpha2_UserAuthenticationSpec(in *kops.UserAuthenticationSpec, out *UserAuthenticationSpec, s conversion.Scope) error {
+	out.MaxValidity = in.MaxValidity
+	if in.GroupRoles != nil {
+		in, out := &in.GroupRoles, &out.GroupRoles
+		*out = make([]UserGroupRoles, len(*in))
+		for i := range *in {
+			if err := Convert_kops_UserGroupRoles_To_v1alpha2_UserGroupRoles(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.GroupRoles = nil
+	}
+	return nil
+}
+
+// Convert_kops_UserAuthenticationSpec_To_v1alpha2_UserAuthenticationSpec is an autogenerated conversion function.
+func Convert_kops_UserAuthenticationSpec_To_v1alpha2_UserAuthenticationSpec(in *kops.UserAuthenticationSpec, out *UserAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_kops_UserAuthenticationSpec_To_v1alpha2_UserAuthenticationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_UserData_To_kops_UserData(in *UserData, out *kops.UserData, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Type = in.Type
@@ -6682,6 +7272,72 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha2_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha2_UserGroupRoles_To_kops_UserGroupRoles(in *UserGroupRoles, out *kops.UserGroupRoles, s conversion.Scope) error {
+	out.Group = in.Group
+	out.ClusterRoles = in.ClusterRoles
+	return nil
+}
+
+// Convert_v1alpha2_UserGroupRoles_To_kops_UserGroupRoles is an autogenerated conversion function.
+func Convert_v1alpha2_UserGroupRoles_To_kops_UserGroupRoles(in *UserGroupRoles, out *kops.UserGroupRoles, s conversion.Scope) error {
+	return autoConvert_v1alpha2_UserGroupRoles_To_kops_UserGroupRoles(in, out, s)
+}
+
+func autoConvert_kops_UserGroupRoles_To_v1alpha2_UserGroupRoles(in *kops.UserGroupRoles, out *UserGroupRoles, s conversion.Scope) error {
+	out.Group = in.Group
+	out.ClusterRoles = in.ClusterRoles
+	return nil
+}
+
+// Convert_kops_UserGroupRoles_To_v1alpha2_UserGroupRoles is an autogenerated conversion function.
+func Convert_kops_UserGroupRoles_To_v1alpha2_UserGroupRoles(in *kops.UserGroupRoles, out *UserGroupRoles, s conversion.Scope) error {
+	return autoConvert_kops_UserGroupRoles_To_v1alpha2_UserGroupRoles(in, out, s)
+}
+
+func autoConvert_v1alpha2_ValidationHTTPGetCheck_To_kops_ValidationHTTPGetCheck(in *ValidationHTTPGetCheck, out *kops.ValidationHTTPGetCheck, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_v1alpha2_ValidationHTTPGetCheck_To_kops_ValidationHTTPGetCheck is an autogenerated conversion function.
+func Convert_v1alpha2_ValidationHTTPGetCheck_To_kops_ValidationHTTPGetCheck(in *ValidationHTTPGetCheck, out *kops.ValidationHTTPGetCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ValidationHTTPGetCheck_To_kops_ValidationHTTPGetCheck(in, out, s)
+}
+
+func autoConvert_kops_ValidationHTTPGetCheck_To_v1alpha2_ValidationHTTPGetCheck(in *kops.ValidationHTTPGetCheck, out *ValidationHTTPGetCheck, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_kops_ValidationHTTPGetCheck_To_v1alpha2_ValidationHTTPGetCheck is an autogenerated conversion function.
+func Convert_kops_ValidationHTTPGetCheck_To_v1alpha2_ValidationHTTPGetCheck(in *kops.ValidationHTTPGetCheck, out *ValidationHTTPGetCheck, s conversion.Scope) error {
+	return autoConvert_kops_ValidationHTTPGetCheck_To_v1alpha2_ValidationHTTPGetCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_ValidationWorkloadCheck_To_kops_ValidationWorkloadCheck(in *ValidationWorkloadCheck, out *kops.ValidationWorkloadCheck, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1alpha2_ValidationWorkloadCheck_To_kops_ValidationWorkloadCheck is an autogenerated conversion function.
+func Convert_v1alpha2_ValidationWorkloadCheck_To_kops_ValidationWorkloadCheck(in *ValidationWorkloadCheck, out *kops.ValidationWorkloadCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ValidationWorkloadCheck_To_kops_ValidationWorkloadCheck(in, out, s)
+}
+
+func autoConvert_kops_ValidationWorkloadCheck_To_v1alpha2_ValidationWorkloadCheck(in *kops.ValidationWorkloadCheck, out *ValidationWorkloadCheck, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_kops_ValidationWorkloadCheck_To_v1alpha2_ValidationWorkloadCheck is an autogenerated conversion function.
+func Convert_kops_ValidationWorkloadCheck_To_v1alpha2_ValidationWorkloadCheck(in *kops.ValidationWorkloadCheck, out *ValidationWorkloadCheck, s conversion.Scope) error {
+	return autoConvert_kops_ValidationWorkloadCheck_To_v1alpha2_ValidationWorkloadCheck(in, out, s)
+}
+
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -357,17 +357,11 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 	allErrs = append(allErrs, newValidateCluster(c)...)
 
 	if strings.HasPrefix(c.Spec.SecretStore, "vault://") {
-		if !featureflag.VFSVaultSupport.Enabled() {
-			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("secretStore"), "vault VFS is an experimental feature; set `export KOPS_FEATURE_FLAGS=VFSVaultSupport`"))
-		}
 		if kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderAWS {
 			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("secretStore"), "Vault secret store is only available on AWS"))
 		}
 	}
 	if strings.HasPrefix(c.Spec.KeyStore, "vault://") {
-		if !featureflag.VFSVaultSupport.Enabled() {
-			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("keyStore"), "vault VFS is an experimental feature; set `export KOPS_FEATURE_FLAGS=VFSVaultSupport`"))
-		}
 		if kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderAWS {
 			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("keyStore"), "Vault keystore is only available on AWS"))
 		}
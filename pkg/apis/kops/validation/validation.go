@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/blang/semver/v4"
@@ -35,6 +36,7 @@ import (
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/upup/pkg/fi"
@@ -218,10 +220,47 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("iam", "legacy"), "legacy IAM permissions are no longer supported"))
 	}
 
+	if spec.Authentication != nil && spec.Authentication.OIDC != nil {
+		allErrs = append(allErrs, validateOIDCAuthentication(spec.Authentication.OIDC, fieldPath.Child("authentication", "oidc"))...)
+	}
+
+	if spec.Authentication != nil && spec.Authentication.Users != nil {
+		usersPath := fieldPath.Child("authentication", "users")
+		allErrs = append(allErrs, validateUserAuthentication(spec.Authentication.Users, usersPath)...)
+		if len(spec.Authentication.Users.GroupRoles) != 0 && (spec.Authorization == nil || spec.Authorization.RBAC == nil) {
+			allErrs = append(allErrs, field.Forbidden(usersPath.Child("groupRoles"), "groupRoles requires RBAC authorization"))
+		}
+	}
+
 	if spec.RollingUpdate != nil {
 		allErrs = append(allErrs, validateRollingUpdate(spec.RollingUpdate, fieldPath.Child("rollingUpdate"), false)...)
 	}
 
+	if spec.Validation != nil {
+		allErrs = append(allErrs, validateClusterValidation(spec.Validation, fieldPath.Child("validation"))...)
+	}
+
+	if spec.NodeBootstrapAdmission != nil {
+		admissionPath := fieldPath.Child("nodeBootstrapAdmission")
+		allErrs = append(allErrs, validateNodeBootstrapAdmission(spec.NodeBootstrapAdmission, admissionPath)...)
+		if !model.UseKopsControllerForNodeBootstrap(c) {
+			allErrs = append(allErrs, field.Forbidden(admissionPath, "nodeBootstrapAdmission requires nodes to bootstrap through kops-controller"))
+		}
+	}
+
+	if spec.StateEncryptionKey != "" {
+		allErrs = append(allErrs, validateStateEncryptionKey(spec.StateEncryptionKey, kops.CloudProviderID(spec.CloudProvider), fieldPath.Child("stateEncryptionKey"))...)
+	}
+
+	if spec.AutoApply != nil {
+		allErrs = append(allErrs, validateAutoApply(spec.AutoApply, fieldPath.Child("autoApply"))...)
+		allErrs = append(allErrs, validateAutoApplyIAM(spec, fieldPath)...)
+	}
+
+	if spec.Target != nil && spec.Target.Terraform != nil && spec.Target.Terraform.Module != nil {
+		allErrs = append(allErrs, validateTerraformModule(spec.Target.Terraform.Module, fieldPath.Child("target", "terraform", "module"))...)
+	}
+
 	if spec.API != nil && spec.API.LoadBalancer != nil && spec.CloudProvider == "aws" {
 		value := string(spec.API.LoadBalancer.Class)
 		allErrs = append(allErrs, IsValidValue(fieldPath.Child("class"), &value, kops.SupportedLoadBalancerClasses)...)
@@ -1395,9 +1434,301 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+	for i, hook := range rollingUpdate.Hooks {
+		allErrs = append(allErrs, validateRollingUpdateHook(&hook, fldpath.Child("hooks").Index(i))...)
+	}
+	if rollingUpdate.Canary != nil {
+		allErrs = append(allErrs, validateRollingUpdateCanary(rollingUpdate.Canary, fldpath.Child("canary"))...)
+	}
+	return allErrs
+}
+
+func validateRollingUpdateCanary(canary *kops.RollingUpdateCanary, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if canary.Instances != nil {
+		instances, err := intstr.GetValueFromIntOrPercent(canary.Instances, 1000, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("instances"), canary.Instances,
+				fmt.Sprintf("Unable to parse: %v", err)))
+		} else if instances <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("instances"), canary.Instances, "Must be positive"))
+		}
+	}
+
+	if canary.SoakDuration != nil && canary.SoakDuration.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("soakDuration"), canary.SoakDuration.Duration.String(), "Cannot be negative"))
+	}
+
+	return allErrs
+}
+
+func validateRollingUpdateHook(hook *kops.RollingUpdateHook, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(hook.Exec) == 0 && hook.Webhook == "" {
+		allErrs = append(allErrs, field.Required(fldpath, "One of exec or webhook must be specified"))
+	} else if len(hook.Exec) != 0 && hook.Webhook != "" {
+		allErrs = append(allErrs, field.Forbidden(fldpath.Child("webhook"), "Cannot be specified together with exec"))
+	}
+
+	if hook.Webhook != "" {
+		u, err := url.Parse(hook.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("webhook"), hook.Webhook, "Must be an http or https URL"))
+		}
+	}
+
+	for i, phase := range hook.Phases {
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("phases").Index(i), fi.String(string(phase)), []string{
+			string(kops.RollingUpdateHookBeforeDrain),
+			string(kops.RollingUpdateHookAfterTerminate),
+			string(kops.RollingUpdateHookAfterValidate),
+		})...)
+	}
+
+	if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("timeout"), hook.Timeout.Duration.String(), "Must be positive"))
+	}
+
+	if hook.FailurePolicy != "" {
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("failurePolicy"), fi.String(string(hook.FailurePolicy)), []string{
+			string(kops.RollingUpdateHookFailurePolicyFail),
+			string(kops.RollingUpdateHookFailurePolicyIgnore),
+		})...)
+	}
+
+	return allErrs
+}
+
+func validateClusterValidation(spec *kops.ClusterValidationSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i := range spec.Checks {
+		check := &spec.Checks[i]
+		checkPath := fldpath.Child("checks").Index(i)
+
+		if check.Name == "" {
+			allErrs = append(allErrs, field.Required(checkPath.Child("name"), ""))
+		} else if names.Has(check.Name) {
+			allErrs = append(allErrs, field.Duplicate(checkPath.Child("name"), check.Name))
+		} else {
+			names.Insert(check.Name)
+		}
+
+		if check.Workload == nil && check.HTTPGet == nil {
+			allErrs = append(allErrs, field.Required(checkPath, "One of workload or httpGet must be specified"))
+		} else if check.Workload != nil && check.HTTPGet != nil {
+			allErrs = append(allErrs, field.Forbidden(checkPath.Child("httpGet"), "Cannot be specified together with workload"))
+		}
+
+		if check.Workload != nil {
+			workloadPath := checkPath.Child("workload")
+			allErrs = append(allErrs, IsValidValue(workloadPath.Child("kind"), &check.Workload.Kind, []string{"Deployment", "DaemonSet", "StatefulSet"})...)
+			if check.Workload.Name == "" {
+				allErrs = append(allErrs, field.Required(workloadPath.Child("name"), ""))
+			}
+		}
+
+		if check.HTTPGet != nil {
+			u, err := url.Parse(check.HTTPGet.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(checkPath.Child("httpGet", "url"), check.HTTPGet.URL, "Must be an http or https URL"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateNodeBootstrapAdmission(spec *kops.NodeBootstrapAdmissionSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	instanceGroups := sets.NewString()
+	for i, name := range spec.InstanceGroups {
+		if name == "" {
+			allErrs = append(allErrs, field.Required(fldpath.Child("instanceGroups").Index(i), ""))
+		} else if instanceGroups.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(fldpath.Child("instanceGroups").Index(i), name))
+		}
+		instanceGroups.Insert(name)
+	}
+
+	if spec.MaxSizeSurge < 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("maxSizeSurge"), spec.MaxSizeSurge, "Cannot be negative"))
+	} else if spec.MaxSizeSurge > 0 && !spec.EnforceMaxSize {
+		allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSizeSurge"), "maxSizeSurge requires enforceMaxSize"))
+	}
+
+	if spec.RequestsPerMinute < 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("requestsPerMinute"), spec.RequestsPerMinute, "Cannot be negative"))
+	}
+	if spec.Burst < 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("burst"), spec.Burst, "Cannot be negative"))
+	} else if spec.Burst > 0 && spec.RequestsPerMinute == 0 {
+		allErrs = append(allErrs, field.Forbidden(fldpath.Child("burst"), "burst requires requestsPerMinute"))
+	}
+
+	if spec.WebhookURL != "" {
+		u, err := url.Parse(spec.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("webhookURL"), spec.WebhookURL, "Must be an https URL"))
+		}
+	}
+	if spec.WebhookTimeout != nil {
+		if spec.WebhookTimeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("webhookTimeout"), spec.WebhookTimeout.Duration.String(), "Must be positive"))
+		} else if spec.WebhookURL == "" {
+			allErrs = append(allErrs, field.Forbidden(fldpath.Child("webhookTimeout"), "webhookTimeout requires webhookURL"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateAutoApply(spec *kops.AutoApplySpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Interval != nil && spec.Interval.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("interval"), spec.Interval.Duration.String(), "Must be at least 1m"))
+	}
+
+	allErrs = append(allErrs, validateAutoApplyPolicy(spec.Network, fldpath.Child("network"))...)
+	allErrs = append(allErrs, validateAutoApplyPolicy(spec.Security, fldpath.Child("security"))...)
+	allErrs = append(allErrs, validateAutoApplyPolicy(spec.Cluster, fldpath.Child("cluster"))...)
+
+	return allErrs
+}
+
+// validateAutoApplyIAM checks that the control plane cannot use the security phase to grant itself any permission.
+func validateAutoApplyIAM(spec *kops.ClusterSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS || spec.AutoApply.Security != kops.AutoApplyPolicyAuto {
+		return allErrs
+	}
+	if spec.IAM == nil || spec.IAM.PermissionsBoundary == nil {
+		allErrs = append(allErrs, field.Required(fldpath.Child("iam", "permissionsBoundary"), "a permissions boundary is required when autoApply.security is Auto"))
+	}
+
+	return allErrs
+}
+
+func validateAutoApplyPolicy(policy kops.AutoApplyPolicy, fldpath *field.Path) field.ErrorList {
+	if policy == "" {
+		return nil
+	}
+	value := string(policy)
+	return IsValidValue(fldpath, &value, kops.SupportedAutoApplyPolicies)
+}
+
+// gcpKMSKeyName matches the resource names of GCP Cloud KMS keys
+var gcpKMSKeyName = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+func validateStateEncryptionKey(keyURL string, cloudProvider kops.CloudProviderID, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch {
+	case strings.HasPrefix(keyURL, "aws-kms://"):
+		parsedARN, err := arn.Parse(strings.TrimPrefix(keyURL, "aws-kms://"))
+		if err != nil || parsedARN.Service != "kms" || !strings.HasPrefix(parsedARN.Resource, "key/") {
+			allErrs = append(allErrs, field.Invalid(fldpath, keyURL, "Must be the ARN of an AWS KMS key, such as aws-kms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"))
+		}
+		if cloudProvider != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fldpath, "AWS KMS keys can only be used on AWS"))
+		}
+	case strings.HasPrefix(keyURL, "gcp-kms://"):
+		if !gcpKMSKeyName.MatchString(strings.TrimPrefix(keyURL, "gcp-kms://")) {
+			allErrs = append(allErrs, field.Invalid(fldpath, keyURL, "Must be the resource name of a GCP Cloud KMS key, such as gcp-kms://projects/my-project/locations/global/keyRings/my-keyring/cryptoKeys/my-key"))
+		}
+		if cloudProvider != kops.CloudProviderGCE {
+			allErrs = append(allErrs, field.Forbidden(fldpath, "GCP Cloud KMS keys can only be used on GCE"))
+		}
+	default:
+		allErrs = append(allErrs, field.Invalid(fldpath, keyURL, "Must be an AWS KMS key (aws-kms://ARN) or a GCP Cloud KMS key (gcp-kms://RESOURCE_NAME)"))
+	}
+
 	return allErrs
 }
 
+// terraformIdentifier matches the names terraform accepts for identifiers, such as provider aliases
+var terraformIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+func validateTerraformModule(spec *kops.TerraformModuleSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.ProviderAlias != "" && !terraformIdentifier.MatchString(spec.ProviderAlias) {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("providerAlias"), spec.ProviderAlias, "Must be a valid terraform identifier"))
+	}
+
+	return allErrs
+}
+
+func validateOIDCAuthentication(spec *kops.OIDCAuthenticationSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.IssuerURL == "" {
+		allErrs = append(allErrs, field.Required(fldpath.Child("issuerURL"), ""))
+	} else {
+		u, err := url.Parse(spec.IssuerURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("issuerURL"), spec.IssuerURL, "Must be an https URL"))
+		}
+	}
+
+	if spec.ClientID == "" {
+		allErrs = append(allErrs, field.Required(fldpath.Child("clientID"), ""))
+	}
+
+	for i, scope := range spec.ExtraScopes {
+		if scope == "" || scope == "openid" {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("extraScopes").Index(i), scope, "Must be a scope other than openid"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateUserAuthentication(spec *kops.UserAuthenticationSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.MaxValidity != nil && spec.MaxValidity.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("maxValidity"), spec.MaxValidity.Duration.String(), "Must be positive"))
+	}
+
+	groups := sets.NewString()
+	for i, groupRoles := range spec.GroupRoles {
+		groupPath := fldpath.Child("groupRoles").Index(i)
+		if groupRoles.Group == "" {
+			allErrs = append(allErrs, field.Required(groupPath.Child("group"), ""))
+		} else if strings.HasPrefix(groupRoles.Group, "system:") {
+			allErrs = append(allErrs, field.Forbidden(groupPath.Child("group"), "groups with the system: prefix are reserved"))
+		} else if !isValidRBACName(groupRoles.Group) {
+			allErrs = append(allErrs, field.Invalid(groupPath.Child("group"), groupRoles.Group, "Must not be \".\" or \"..\" nor contain \"/\" or \"%\""))
+		} else if groups.Has(groupRoles.Group) {
+			allErrs = append(allErrs, field.Duplicate(groupPath.Child("group"), groupRoles.Group))
+		}
+		groups.Insert(groupRoles.Group)
+
+		if len(groupRoles.ClusterRoles) == 0 {
+			allErrs = append(allErrs, field.Required(groupPath.Child("clusterRoles"), ""))
+		}
+		for j, clusterRole := range groupRoles.ClusterRoles {
+			if clusterRole == "" || !isValidRBACName(clusterRole) {
+				allErrs = append(allErrs, field.Invalid(groupPath.Child("clusterRoles").Index(j), clusterRole, "Must be the name of a ClusterRole"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// isValidRBACName returns true if name can be used in the name of an RBAC object.
+func isValidRBACName(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, "/%")
+}
+
 func validateNodeLocalDNS(spec *kops.ClusterSpec, fldpath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
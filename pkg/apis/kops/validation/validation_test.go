@@ -18,7 +18,9 @@ package validation
 
 import (
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -1034,6 +1036,84 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Exec:          []string{"/usr/local/bin/check-brokers"},
+						Phases:        []kops.RollingUpdateHookPhase{kops.RollingUpdateHookBeforeDrain},
+						FailurePolicy: kops.RollingUpdateHookFailurePolicyIgnore,
+					},
+					{
+						Webhook: "https://example.com/ready",
+						Timeout: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{{}},
+			},
+			ExpectedErrors: []string{"Required value::testField.hooks[0]"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Exec:    []string{"true"},
+						Webhook: "https://example.com/ready",
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.hooks[0].webhook"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Webhook:       "ftp://example.com",
+						Phases:        []kops.RollingUpdateHookPhase{"BeforeTerminate"},
+						Timeout:       &metav1.Duration{},
+						FailurePolicy: "Retry",
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.hooks[0].webhook",
+				"Unsupported value::testField.hooks[0].phases[0]",
+				"Invalid value::testField.hooks[0].timeout",
+				"Unsupported value::testField.hooks[0].failurePolicy",
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Canary: &kops.RollingUpdateCanary{
+					Instances:    intStr(intstr.FromString("10%")),
+					SoakDuration: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Canary: &kops.RollingUpdateCanary{
+					Instances: intStr(intstr.FromInt(0)),
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.canary.instances"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Canary: &kops.RollingUpdateCanary{
+					Instances:    intStr(intstr.FromString("nope")),
+					SoakDuration: &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.canary.instances",
+				"Invalid value::testField.canary.soakDuration",
+			},
+		},
 	}
 	for _, g := range grid {
 		errs := validateRollingUpdate(&g.Input, field.NewPath("testField"), g.OnMasterIG)
@@ -1045,6 +1125,366 @@ func intStr(i intstr.IntOrString) *intstr.IntOrString {
 	return &i
 }
 
+func Test_Validate_ClusterValidation(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterValidationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterValidationSpec{
+				Validators: []string{"etcd", "dns"},
+				Checks: []kops.ClusterValidationCheck{
+					{
+						Name:     "ingress",
+						Workload: &kops.ValidationWorkloadCheck{Kind: "Deployment", Namespace: "ingress", Name: "ingress-nginx"},
+					},
+					{
+						Name:    "app",
+						HTTPGet: &kops.ValidationHTTPGetCheck{URL: "https://app.example.com/healthz"},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterValidationSpec{
+				Checks: []kops.ClusterValidationCheck{
+					{Name: "a", HTTPGet: &kops.ValidationHTTPGetCheck{URL: "http://example.com"}},
+					{Name: "a", HTTPGet: &kops.ValidationHTTPGetCheck{URL: "ftp://example.com"}},
+					{},
+				},
+			},
+			ExpectedErrors: []string{
+				"Duplicate value::testField.checks[1].name",
+				"Invalid value::testField.checks[1].httpGet.url",
+				"Required value::testField.checks[2].name",
+				"Required value::testField.checks[2]",
+			},
+		},
+		{
+			Input: kops.ClusterValidationSpec{
+				Checks: []kops.ClusterValidationCheck{
+					{
+						Name:     "both",
+						Workload: &kops.ValidationWorkloadCheck{Kind: "Pod"},
+						HTTPGet:  &kops.ValidationHTTPGetCheck{URL: "http://example.com"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.checks[0].httpGet",
+				"Unsupported value::testField.checks[0].workload.kind",
+				"Required value::testField.checks[0].workload.name",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateClusterValidation(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_OIDCAuthentication(t *testing.T) {
+	grid := []struct {
+		Input          kops.OIDCAuthenticationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:   "https://accounts.example.com",
+				ClientID:    "kubernetes",
+				GroupsClaim: "groups",
+				ExtraScopes: []string{"groups", "email"},
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{},
+			ExpectedErrors: []string{
+				"Required value::testField.issuerURL",
+				"Required value::testField.clientID",
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:   "http://accounts.example.com",
+				ClientID:    "kubernetes",
+				ExtraScopes: []string{"openid"},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.issuerURL",
+				"Invalid value::testField.extraScopes[0]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateOIDCAuthentication(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_UserAuthentication(t *testing.T) {
+	grid := []struct {
+		Input          kops.UserAuthenticationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.UserAuthenticationSpec{
+				MaxValidity: &metav1.Duration{Duration: 8 * time.Hour},
+				GroupRoles: []kops.UserGroupRoles{
+					{Group: "developers", ClusterRoles: []string{"view", "edit"}},
+					{Group: "operators", ClusterRoles: []string{"admin"}},
+				},
+			},
+		},
+		{
+			Input: kops.UserAuthenticationSpec{
+				MaxValidity: &metav1.Duration{},
+				GroupRoles: []kops.UserGroupRoles{
+					{},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.maxValidity",
+				"Required value::testField.groupRoles[0].group",
+				"Required value::testField.groupRoles[0].clusterRoles",
+			},
+		},
+		{
+			Input: kops.UserAuthenticationSpec{
+				GroupRoles: []kops.UserGroupRoles{
+					{Group: "system:masters", ClusterRoles: []string{"view"}},
+					{Group: "developers", ClusterRoles: []string{"view"}},
+					{Group: "developers", ClusterRoles: []string{"edit"}},
+					{Group: "a/b", ClusterRoles: []string{"", ".."}},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.groupRoles[0].group",
+				"Duplicate value::testField.groupRoles[2].group",
+				"Invalid value::testField.groupRoles[3].group",
+				"Invalid value::testField.groupRoles[3].clusterRoles[0]",
+				"Invalid value::testField.groupRoles[3].clusterRoles[1]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateUserAuthentication(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeBootstrapAdmission(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeBootstrapAdmissionSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeBootstrapAdmissionSpec{
+				InstanceGroups:    []string{"nodes", "master-us-east-1a"},
+				EnforceMaxSize:    true,
+				MaxSizeSurge:      2,
+				RequestsPerMinute: 60,
+				Burst:             10,
+				WebhookURL:        "https://admission.example.com/bootstrap",
+				WebhookTimeout:    &metav1.Duration{Duration: 5 * time.Second},
+			},
+		},
+		{
+			Input: kops.NodeBootstrapAdmissionSpec{
+				InstanceGroups:    []string{"nodes", "", "nodes"},
+				MaxSizeSurge:      -1,
+				RequestsPerMinute: -1,
+				Burst:             -1,
+				WebhookURL:        "http://admission.example.com/bootstrap",
+				WebhookTimeout:    &metav1.Duration{},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField.instanceGroups[1]",
+				"Duplicate value::testField.instanceGroups[2]",
+				"Invalid value::testField.maxSizeSurge",
+				"Invalid value::testField.requestsPerMinute",
+				"Invalid value::testField.burst",
+				"Invalid value::testField.webhookURL",
+				"Invalid value::testField.webhookTimeout",
+			},
+		},
+		{
+			Input: kops.NodeBootstrapAdmissionSpec{
+				MaxSizeSurge:   1,
+				Burst:          5,
+				WebhookTimeout: &metav1.Duration{Duration: 5 * time.Second},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.maxSizeSurge",
+				"Forbidden::testField.burst",
+				"Forbidden::testField.webhookTimeout",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeBootstrapAdmission(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AutoApply(t *testing.T) {
+	grid := []struct {
+		Input          kops.AutoApplySpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AutoApplySpec{},
+		},
+		{
+			Input: kops.AutoApplySpec{
+				Interval: &metav1.Duration{Duration: 5 * time.Minute},
+				Network:  kops.AutoApplyPolicyNever,
+				Security: kops.AutoApplyPolicyNever,
+				Cluster:  kops.AutoApplyPolicyAuto,
+			},
+		},
+		{
+			Input: kops.AutoApplySpec{
+				Interval: &metav1.Duration{Duration: 30 * time.Second},
+				Network:  "Always",
+				Security: "auto",
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.interval",
+				"Unsupported value::testField.network",
+				"Unsupported value::testField.security",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAutoApply(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AutoApplyIAM(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				AutoApply:     &kops.AutoApplySpec{},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				AutoApply: &kops.AutoApplySpec{
+					Security: kops.AutoApplyPolicyAuto,
+				},
+			},
+			ExpectedErrors: []string{"Required value::testField.iam.permissionsBoundary"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				AutoApply: &kops.AutoApplySpec{
+					Security: kops.AutoApplyPolicyAuto,
+				},
+				IAM: &kops.IAMSpec{
+					PermissionsBoundary: fi.String("arn:aws:iam::123456789012:policy/kops-boundary"),
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				AutoApply: &kops.AutoApplySpec{
+					Security: kops.AutoApplyPolicyAuto,
+				},
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAutoApplyIAM(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_StateEncryptionKey(t *testing.T) {
+	grid := []struct {
+		Input          string
+		CloudProvider  kops.CloudProviderID
+		ExpectedErrors []string
+	}{
+		{
+			Input:         "aws-kms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			CloudProvider: kops.CloudProviderAWS,
+		},
+		{
+			Input:          "aws-kms://1234abcd-12ab-34cd-56ef-1234567890ab",
+			CloudProvider:  kops.CloudProviderAWS,
+			ExpectedErrors: []string{"Invalid value::testField"},
+		},
+		{
+			Input:          "aws-kms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			CloudProvider:  kops.CloudProviderGCE,
+			ExpectedErrors: []string{"Forbidden::testField"},
+		},
+		{
+			Input:         "gcp-kms://projects/my-project/locations/global/keyRings/my-keyring/cryptoKeys/my-key",
+			CloudProvider: kops.CloudProviderGCE,
+		},
+		{
+			Input:          "gcp-kms://my-key",
+			CloudProvider:  kops.CloudProviderGCE,
+			ExpectedErrors: []string{"Invalid value::testField"},
+		},
+		{
+			Input:          "age:///home/user/key.txt",
+			CloudProvider:  kops.CloudProviderAWS,
+			ExpectedErrors: []string{"Invalid value::testField"},
+		},
+		{
+			Input:          "pgp:///home/user/keyring.asc",
+			CloudProvider:  kops.CloudProviderAWS,
+			ExpectedErrors: []string{"Invalid value::testField"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateStateEncryptionKey(g.Input, g.CloudProvider, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_TerraformModule(t *testing.T) {
+	grid := []struct {
+		Input          kops.TerraformModuleSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.TerraformModuleSpec{},
+		},
+		{
+			Input: kops.TerraformModuleSpec{
+				ProviderAlias: "kops_cluster-1",
+			},
+		},
+		{
+			Input: kops.TerraformModuleSpec{
+				ProviderAlias: "1cluster",
+			},
+			ExpectedErrors: []string{"Invalid value::testField.providerAlias"},
+		},
+		{
+			Input: kops.TerraformModuleSpec{
+				ProviderAlias: "aws.cluster",
+			},
+			ExpectedErrors: []string{"Invalid value::testField.providerAlias"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateTerraformModule(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func Test_Validate_NodeLocalDNS(t *testing.T) {
 	grid := []struct {
 		Input          kops.ClusterSpec
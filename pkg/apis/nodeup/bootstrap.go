@@ -18,7 +18,8 @@ package nodeup
 
 const BootstrapAPIVersion = "bootstrap.kops.k8s.io/v1alpha1"
 
-// BootstrapRequest is a request from nodeup to kops-controller for bootstrapping a node.
+// BootstrapRequest is a request from nodeup to kops-controller for bootstrapping a node,
+// or for renewing the certificates of a node that has registered.
 type BootstrapRequest struct {
 	// APIVersion defines the versioned schema of this representation of a request.
 	APIVersion string `json:"apiVersion"`
@@ -27,6 +28,7 @@ type BootstrapRequest struct {
 
 	// IncludeNodeConfig controls whether the cluster & instance group configuration should be returned.
 	// This allows for nodes without access to the kops state store.
+	// It is ignored when renewing certificates.
 	IncludeNodeConfig bool `json:"includeNodeConfig"`
 }
 
@@ -34,6 +36,10 @@ type BootstrapRequest struct {
 type BootstrapResponse struct {
 	// Certs are the issued certificates.
 	Certs map[string]string
+	// Keys are the private keys kops-controller generated for issued certificates, because their signer
+	// is held remotely and can only sign certificate requests kops-controller makes itself.
+	// The public keys in the request are not used for these certificates.
+	Keys map[string]string `json:"keys,omitempty"`
 
 	// NodeConfig contains the node configuration, if IncludeNodeConfig is set.
 	NodeConfig *NodeConfig `json:"nodeConfig,omitempty"`
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+// CertificateRenewalConfig is written by nodeup on nodes that bootstrap through kops-controller.
+// It configures the renewal of the certificates kops-controller issued to the node.
+type CertificateRenewalConfig struct {
+	// CloudProvider is the cloud provider the node authenticates to kops-controller with.
+	CloudProvider string `json:"cloudProvider"`
+	// Region is the cloud region of the node.
+	Region string `json:"region,omitempty"`
+	// Server is the base URL of kops-controller.
+	Server string `json:"server"`
+	// CACertificates are the PEM-encoded CA certificates trusted to sign the certificates of kops-controller
+	// and of the node.
+	CACertificates string `json:"caCertificates"`
+	// RenewBefore is how long before they expire certificates are renewed, as a duration string.
+	RenewBefore string `json:"renewBefore,omitempty"`
+	// Certificates are the certificates to renew.
+	Certificates []RenewableCertificate `json:"certificates"`
+	// Services are the systemd services to restart after certificates are renewed.
+	Services []string `json:"services,omitempty"`
+	// ContainerRuntime is the container runtime of the node, used to restart the containers of the certificates.
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+}
+
+// RenewableCertificate is a certificate issued by kops-controller, and where it is stored on the node.
+// Either Kubeconfig, or CertPath and KeyPath, is set.
+type RenewableCertificate struct {
+	// Name is the name of the certificate in the BootstrapRequest.
+	Name string `json:"name"`
+	// Kubeconfig is the path of a kubeconfig file embedding the certificate and its key.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// CertPath is the path of the PEM-encoded certificate.
+	CertPath string `json:"certPath,omitempty"`
+	// KeyPath is the path of the PEM-encoded private key.
+	KeyPath string `json:"keyPath,omitempty"`
+	// Containers are the names of the containers using the certificate.
+	// They are stopped after the certificate is renewed, so that kubelet restarts them with the new certificate.
+	Containers []string `json:"containers,omitempty"`
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinventory
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// Source is where a certificate is kept.
+type Source string
+
+const (
+	// SourceKeystore is a certificate of a keyset in the keystore.
+	SourceKeystore Source = "Keystore"
+	// SourceNode is a certificate issued by nodeup on a node.
+	SourceNode Source = "Node"
+)
+
+// Certificate is an entry in the certificate inventory.
+type Certificate struct {
+	Source Source `json:"source"`
+	// Name is the name of the keyset, or of the node
+	Name           string    `json:"name"`
+	Subject        string    `json:"subject,omitempty"`
+	AlternateNames []string  `json:"alternateNames,omitempty"`
+	Issuer         string    `json:"issuer,omitempty"`
+	Serial         string    `json:"serial,omitempty"`
+	NotBefore      time.Time `json:"notBefore"`
+	NotAfter       time.Time `json:"notAfter"`
+	Primary        bool      `json:"primary,omitempty"`
+	Distrusted     bool      `json:"distrusted,omitempty"`
+	// Estimated is true if the certificate could not be read, and NotBefore and NotAfter are estimates
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// FromKeysets returns the certificates of the keysets.
+func FromKeysets(keysets map[string]*fi.Keyset) []*Certificate {
+	var certificates []*Certificate
+	for name, keyset := range keysets {
+		for _, item := range keyset.Items {
+			if item.Certificate == nil || item.Certificate.Certificate == nil {
+				continue
+			}
+			cert := item.Certificate.Certificate
+
+			var alternateNames []string
+			alternateNames = append(alternateNames, cert.DNSNames...)
+			for _, ip := range cert.IPAddresses {
+				alternateNames = append(alternateNames, ip.String())
+			}
+			alternateNames = append(alternateNames, cert.EmailAddresses...)
+			for _, uri := range cert.URIs {
+				alternateNames = append(alternateNames, uri.String())
+			}
+
+			certificates = append(certificates, &Certificate{
+				Source:         SourceKeystore,
+				Name:           name,
+				Subject:        cert.Subject.String(),
+				AlternateNames: alternateNames,
+				Issuer:         cert.Issuer.String(),
+				Serial:         cert.SerialNumber.String(),
+				NotBefore:      cert.NotBefore,
+				NotAfter:       cert.NotAfter,
+				Primary:        keyset.Primary != nil && keyset.Primary.Id == item.Id,
+				Distrusted:     item.DistrustTimestamp != nil,
+			})
+		}
+	}
+	Sort(certificates)
+	return certificates
+}
+
+// FromNodes returns an estimate of the certificates nodeup issued on the nodes.
+// The certificates are kept on the nodes only, so NotAfter is estimated as the time
+// the node registered plus nodetasks.IssuedCertMinimumValidity. The certificates may
+// expire a little earlier, as nodeup issues them before the node registers, or much
+// later, if they have been renewed since.
+func FromNodes(nodes []v1.Node) []*Certificate {
+	var certificates []*Certificate
+	for i := range nodes {
+		node := &nodes[i]
+		created := node.CreationTimestamp.Time
+		certificates = append(certificates, &Certificate{
+			Source:    SourceNode,
+			Name:      node.Name,
+			NotBefore: created,
+			NotAfter:  created.Add(nodetasks.IssuedCertMinimumValidity),
+			Estimated: true,
+		})
+	}
+	Sort(certificates)
+	return certificates
+}
+
+// Expiring returns the trusted certificates that expire before deadline.
+func Expiring(certificates []*Certificate, deadline time.Time) []*Certificate {
+	var expiring []*Certificate
+	for _, c := range certificates {
+		if c.Distrusted {
+			continue
+		}
+		if c.NotAfter.Before(deadline) {
+			expiring = append(expiring, c)
+		}
+	}
+	return expiring
+}
+
+// Sort sorts certificates by source, name and expiry.
+func Sort(certificates []*Certificate) {
+	sort.SliceStable(certificates, func(i, j int) bool {
+		a, b := certificates[i], certificates[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.NotAfter.Before(b.NotAfter)
+	})
+}
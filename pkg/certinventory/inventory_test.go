@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinventory
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+var now = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func keysetItem(id string, commonName string, notAfter time.Time) *fi.KeysetItem {
+	serial, _ := big.NewInt(0).SetString(id, 10)
+	return &fi.KeysetItem{
+		Id: id,
+		Certificate: &pki.Certificate{
+			Certificate: &x509.Certificate{
+				Subject:      pkix.Name{CommonName: commonName},
+				Issuer:       pkix.Name{CommonName: "kubernetes-ca"},
+				SerialNumber: serial,
+				NotBefore:    now.Add(-24 * time.Hour),
+				NotAfter:     notAfter,
+				DNSNames:     []string{"kubernetes.default"},
+				IPAddresses:  []net.IP{net.ParseIP("100.64.0.1")},
+			},
+		},
+	}
+}
+
+func TestFromKeysets(t *testing.T) {
+	primary := keysetItem("2", "kubernetes-ca", now.Add(365*24*time.Hour))
+	distrusted := keysetItem("1", "kubernetes-ca", now.Add(24*time.Hour))
+	distrusted.DistrustTimestamp = &now
+	keysets := map[string]*fi.Keyset{
+		"kubernetes-ca": {
+			Items: map[string]*fi.KeysetItem{
+				"1": distrusted,
+				"2": primary,
+			},
+			Primary: primary,
+		},
+	}
+
+	certificates := FromKeysets(keysets)
+	if assert.Len(t, certificates, 2) {
+		assert.Equal(t, "1", certificates[0].Serial)
+		assert.True(t, certificates[0].Distrusted)
+		assert.False(t, certificates[0].Primary)
+		assert.Equal(t, "2", certificates[1].Serial)
+		assert.True(t, certificates[1].Primary)
+		assert.Equal(t, "CN=kubernetes-ca", certificates[1].Subject)
+		assert.Equal(t, "CN=kubernetes-ca", certificates[1].Issuer)
+		assert.Equal(t, []string{"kubernetes.default", "100.64.0.1"}, certificates[1].AlternateNames)
+	}
+
+	// The distrusted keypair expires first, but no longer matters
+	assert.Empty(t, Expiring(certificates, now.Add(30*24*time.Hour)))
+	assert.Len(t, Expiring(certificates, now.Add(400*24*time.Hour)), 1)
+}
+
+func TestFromNodes(t *testing.T) {
+	created := now.Add(-450 * 24 * time.Hour)
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b", CreationTimestamp: metav1.NewTime(now)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", CreationTimestamp: metav1.NewTime(created)}},
+	}
+
+	certificates := FromNodes(nodes)
+	if assert.Len(t, certificates, 2) {
+		assert.Equal(t, "node-a", certificates[0].Name)
+		assert.True(t, certificates[0].Estimated)
+		assert.Equal(t, created.Add(nodetasks.IssuedCertMinimumValidity), certificates[0].NotAfter)
+	}
+
+	expiring := Expiring(certificates, now.Add(30*24*time.Hour))
+	if assert.Len(t, expiring, 1) {
+		assert.Equal(t, "node-a", expiring[0].Name)
+	}
+}
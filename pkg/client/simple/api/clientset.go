@@ -97,6 +97,21 @@ func (c *RESTClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalver
 	return c.KopsClient.InstanceGroups(namespace)
 }
 
+// ListClusterRevisions implements the ListClusterRevisions method of Clientset for a kubernetes-API state store
+func (c *RESTClientset) ListClusterRevisions(ctx context.Context, cluster *kops.Cluster) ([]*simple.ClusterRevision, error) {
+	return nil, fmt.Errorf("cluster history is not supported for a kubernetes-API state store")
+}
+
+// GetRecordedStatus implements the GetRecordedStatus method of Clientset for a kubernetes-API state store
+func (c *RESTClientset) GetRecordedStatus(ctx context.Context, cluster *kops.Cluster) (*simple.RecordedStatus, error) {
+	return nil, fmt.Errorf("recorded status is not supported for a kubernetes-API state store")
+}
+
+// UpdateRecordedStatus implements the UpdateRecordedStatus method of Clientset for a kubernetes-API state store
+func (c *RESTClientset) UpdateRecordedStatus(ctx context.Context, cluster *kops.Cluster, status *simple.RecordedStatus) error {
+	return fmt.Errorf("recorded status is not supported for a kubernetes-API state store")
+}
+
 func (c *RESTClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	namespace := restNamespaceForClusterName(cluster.Name)
 	return secrets.NewClientsetSecretStore(cluster, c.KopsClient, namespace), nil
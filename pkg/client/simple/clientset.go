@@ -18,6 +18,7 @@ package simple
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
@@ -60,6 +61,41 @@ type Clientset interface {
 
 	// DeleteCluster deletes all the state for the specified cluster
 	DeleteCluster(ctx context.Context, cluster *kops.Cluster) error
+
+	// ListClusterRevisions returns the recorded revisions of the cluster and its instance groups, oldest first
+	ListClusterRevisions(ctx context.Context, cluster *kops.Cluster) ([]*ClusterRevision, error)
+
+	// GetRecordedStatus returns the status recorded for the cluster and its instance groups, empty if none was recorded
+	GetRecordedStatus(ctx context.Context, cluster *kops.Cluster) (*RecordedStatus, error)
+
+	// UpdateRecordedStatus records the status of the cluster and its instance groups
+	UpdateRecordedStatus(ctx context.Context, cluster *kops.Cluster, status *RecordedStatus) error
+}
+
+// RecordedStatus is the status of a cluster and its instance groups, recorded when they are applied or rolling-updated
+type RecordedStatus struct {
+	// Cluster is the status of the cluster
+	Cluster kops.ClusterStatus `json:"cluster"`
+	// InstanceGroups is the status of each instance group, by name
+	InstanceGroups map[string]*kops.InstanceGroupStatus `json:"instanceGroups,omitempty"`
+}
+
+// ClusterRevision is a snapshot of the cluster or one of its instance groups, recorded when it was written
+type ClusterRevision struct {
+	// Revision is the number of the revision; revisions of a cluster and its instance groups share a sequence
+	Revision int `json:"revision"`
+	// Kind is the kind of the object, Cluster or InstanceGroup
+	Kind string `json:"kind"`
+	// Name is the name of the object
+	Name string `json:"name"`
+	// Author is who wrote the object
+	Author string `json:"author"`
+	// Timestamp is when the object was written
+	Timestamp time.Time `json:"timestamp"`
+	// Diff is the difference from the previous version of the object
+	Diff string `json:"diff,omitempty"`
+	// Object is the object as it was written to the state store
+	Object string `json:"object"`
 }
 
 // AddonsClient is a client for manipulating cluster addons
@@ -28,8 +28,10 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/lease"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
+	"k8s.io/kops/upup/pkg/fi/vaultstore"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -63,6 +65,21 @@ func (c *VFSClientset) ListClusters(ctx context.Context, options metav1.ListOpti
 	return c.clusters().List(options)
 }
 
+// ListClusterRevisions implements the ListClusterRevisions method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) ListClusterRevisions(ctx context.Context, cluster *kops.Cluster) ([]*simple.ClusterRevision, error) {
+	return newHistoryVFS(c.basePath.Join(cluster.Name)).list(ctx)
+}
+
+// GetRecordedStatus implements the GetRecordedStatus method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) GetRecordedStatus(ctx context.Context, cluster *kops.Cluster) (*simple.RecordedStatus, error) {
+	return readRecordedStatus(c.basePath.Join(cluster.Name, PathStatus))
+}
+
+// UpdateRecordedStatus implements the UpdateRecordedStatus method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) UpdateRecordedStatus(ctx context.Context, cluster *kops.Cluster, status *simple.RecordedStatus) error {
+	return writeRecordedStatus(c.basePath.Join(cluster.Name, PathStatus), cluster, status)
+}
+
 // ConfigBaseFor implements the ConfigBaseFor method of simple.Clientset for a VFS-backed state store
 func (c *VFSClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
 	if cluster.Spec.ConfigBase != "" {
@@ -95,6 +112,15 @@ func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error
 }
 
 func (c *VFSClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
+	if vaultstore.IsVaultLocation(cluster.Spec.KeyStore) {
+		klog.V(8).Infof("Using vault keystore: %q", cluster.Spec.KeyStore)
+		keyStore, err := vaultstore.NewKeyStore(cluster, cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, err
+		}
+		return keyStore, nil
+	}
+
 	basedir, err := pkiPath(cluster)
 	if err != nil {
 		return nil, err
@@ -159,6 +185,10 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "secrets/") {
 			continue
 		}
+		// The progress of an interrupted rolling update
+		if relativePath == "rolling-update-checkpoint" {
+			continue
+		}
 		if strings.HasPrefix(relativePath, "instancegroup/") {
 			continue
 		}
@@ -168,10 +198,23 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "manifests/") {
 			continue
 		}
+		// The progress of keypair rotations
+		if strings.HasPrefix(relativePath, "rotations/") {
+			continue
+		}
 		// TODO: offer an option _not_ to delete backups?
 		if strings.HasPrefix(relativePath, "backups/") {
 			continue
 		}
+		if strings.HasPrefix(relativePath, PathHistory+"/") {
+			continue
+		}
+		if relativePath == lease.PathLock {
+			continue
+		}
+		if relativePath == PathStatus {
+			continue
+		}
 
 		return fmt.Errorf("refusing to delete: unknown file found: %s", path)
 	}
@@ -116,6 +116,10 @@ func (r *ClusterVFS) Create(c *api.Cluster) (*api.Cluster, error) {
 		return nil, fmt.Errorf("error writing Cluster %q: %v", c.ObjectMeta.Name, err)
 	}
 
+	if err := r.withHistory(clusterName).recordRevision(c, clusterName, nil, c); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -142,16 +146,33 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		c.SetGeneration(old.GetGeneration() + 1)
 	}
 
-	if err := r.writeConfig(c, r.basePath.Join(clusterName, registry.PathCluster), c, vfs.WriteOptionOnlyIfExists); err != nil {
-		if os.IsNotExist(err) {
+	configPath := r.basePath.Join(clusterName, registry.PathCluster)
+	oldData, err := configPath.ReadFile()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading %s: %v", configPath, err)
+	}
+
+	if err := r.writeConfig(c, configPath, c, vfs.WriteOptionOnlyIfExists); err != nil {
+		if os.IsNotExist(err) || errors.IsConflict(err) {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
 	}
 
+	if err := r.withHistory(clusterName).recordRevision(c, clusterName, oldData, c); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
+// withHistory returns the commonVFS of the cluster with history enabled
+func (r *ClusterVFS) withHistory(clusterName string) *commonVFS {
+	c := r.commonVFS
+	c.history = newHistoryVFS(r.basePath.Join(clusterName))
+	return &c
+}
+
 // List returns a slice containing all the cluster names
 // It skips directories that don't look like clusters
 func (r *ClusterVFS) listNames() ([]string, error) {
@@ -19,15 +19,19 @@ package vfsclientset
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"reflect"
 	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
@@ -45,6 +49,9 @@ type commonVFS struct {
 	basePath vfs.Path
 	encoder  runtime.Encoder
 	validate ValidationFunction
+
+	// history, if set, records a revision of every object that is written
+	history *historyVFS
 }
 
 func (c *commonVFS) init(kind string, basePath vfs.Path, storeVersion runtime.GroupVersioner) {
@@ -100,10 +107,36 @@ func (c *commonVFS) create(ctx context.Context, cluster *kops.Cluster, i runtime
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
 
+	return c.recordRevision(cluster, objectMeta.GetName(), nil, i)
+}
+
+// recordRevision records a revision of an object that replaced oldData, if history is enabled.
+func (c *commonVFS) recordRevision(cluster *kops.Cluster, name string, oldData []byte, o runtime.Object) error {
+	if c.history == nil {
+		return nil
+	}
+
+	data, err := c.serialize(o)
+	if err != nil {
+		return fmt.Errorf("error marshaling object: %v", err)
+	}
+	if err := c.history.record(cluster, c.kind, name, oldData, data); err != nil {
+		return fmt.Errorf("%s %q was written, but its revision could not be recorded: %v", c.kind, name, err)
+	}
 	return nil
 }
 
+// serialize encodes the object for storage.
+// The resourceVersion is derived from the stored data, so it is not stored itself.
 func (c *commonVFS) serialize(o runtime.Object) ([]byte, error) {
+	if objectMeta, err := meta.Accessor(o); err == nil && objectMeta.GetResourceVersion() != "" {
+		o = o.DeepCopyObject()
+		if objectMeta, err = meta.Accessor(o); err != nil {
+			return nil, err
+		}
+		objectMeta.SetResourceVersion("")
+	}
+
 	var b bytes.Buffer
 	err := c.encoder.Encode(o, &b)
 	if err != nil {
@@ -126,9 +159,28 @@ func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing %s: %v", configPath, err)
 	}
+	if err := setResourceVersion(object, data); err != nil {
+		return nil, err
+	}
 	return object, nil
 }
 
+// resourceVersion returns the resourceVersion of an object stored as data.
+// We hash the data rather than relying on object versioning, which not every vfs backend supports.
+func resourceVersion(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+func setResourceVersion(o runtime.Object, data []byte) error {
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return err
+	}
+	objectMeta.SetResourceVersion(resourceVersion(data))
+	return nil
+}
+
 func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o runtime.Object, writeOptions ...vfs.WriteOption) error {
 	data, err := c.serialize(o)
 	if err != nil {
@@ -141,13 +193,16 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 		case vfs.WriteOptionCreate:
 			create = true
 		case vfs.WriteOptionOnlyIfExists:
-			_, err = configPath.ReadFile()
+			existing, err := configPath.ReadFile()
 			if err != nil {
 				if os.IsNotExist(err) {
 					return fmt.Errorf("cannot update configuration file %s: does not exist", configPath)
 				}
 				return fmt.Errorf("error checking if configuration file %s exists already: %v", configPath, err)
 			}
+			if err := c.checkResourceVersion(o, existing); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unknown write option: %q", writeOption)
 		}
@@ -171,7 +226,22 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 		}
 		return fmt.Errorf("error writing configuration file %s: %v", configPath, err)
 	}
-	return nil
+	return setResourceVersion(o, data)
+}
+
+// checkResourceVersion returns a conflict error if the object was read at a different version than the existing data.
+// Objects without a resourceVersion, e.g. read from a file, overwrite the existing data unconditionally.
+// The check and the write are not atomic, but this catches all but the closest of concurrent writers.
+func (c *commonVFS) checkResourceVersion(o runtime.Object, existing []byte) error {
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return err
+	}
+	if objectMeta.GetResourceVersion() == "" || objectMeta.GetResourceVersion() == resourceVersion(existing) {
+		return nil
+	}
+	return errors.NewConflict(schema.GroupResource{Group: kops.GroupName, Resource: c.kind}, objectMeta.GetName(),
+		fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
 }
 
 func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime.Object) error {
@@ -192,12 +262,25 @@ func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime
 		objectMeta.SetCreationTimestamp(metav1.NewTime(time.Now().UTC()))
 	}
 
-	err = c.writeConfig(cluster, c.basePath.Join(objectMeta.GetName()), i, vfs.WriteOptionOnlyIfExists)
+	configPath := c.basePath.Join(objectMeta.GetName())
+
+	var oldData []byte
+	if c.history != nil {
+		oldData, err = configPath.ReadFile()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error reading %s: %v", configPath, err)
+		}
+	}
+
+	err = c.writeConfig(cluster, configPath, i, vfs.WriteOptionOnlyIfExists)
 	if err != nil {
+		if errors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
 
-	return nil
+	return c.recordRevision(cluster, objectMeta.GetName(), oldData, i)
 }
 
 func (c *commonVFS) delete(ctx context.Context, name string, options metav1.DeleteOptions) error {
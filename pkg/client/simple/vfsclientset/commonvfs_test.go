@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestUpdateConflict(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://state/cluster.example.com/instancegroup")
+	require.NoError(t, err)
+
+	c := &commonVFS{}
+	c.init("InstanceGroup", basePath, StoreVersion)
+
+	ctx := context.TODO()
+	cluster := &kops.Cluster{}
+	cluster.Name = "cluster.example.com"
+
+	ig := &kops.InstanceGroup{}
+	ig.Name = "nodes"
+	ig.Spec.Role = kops.InstanceGroupRoleNode
+	require.NoError(t, c.create(ctx, cluster, ig))
+	assert.NotEmpty(t, ig.ResourceVersion, "create should set the resourceVersion")
+
+	read := func() *kops.InstanceGroup {
+		o, err := c.find(ctx, "nodes")
+		require.NoError(t, err)
+		return o.(*kops.InstanceGroup)
+	}
+
+	first := read()
+	second := read()
+	assert.Equal(t, ig.ResourceVersion, first.ResourceVersion)
+
+	first.Spec.MinSize = fi32(2)
+	require.NoError(t, c.update(ctx, cluster, first))
+	assert.NotEqual(t, second.ResourceVersion, first.ResourceVersion)
+
+	second.Spec.MinSize = fi32(3)
+	err = c.update(ctx, cluster, second)
+	require.Error(t, err)
+	assert.True(t, errors.IsConflict(err), "expected a conflict, got %v", err)
+	assert.Equal(t, int32(2), *read().Spec.MinSize)
+
+	// The updated object can be updated again, as it carries the new resourceVersion
+	first.Spec.MinSize = fi32(4)
+	require.NoError(t, c.update(ctx, cluster, first))
+
+	// Objects without a resourceVersion are written unconditionally
+	second.ResourceVersion = ""
+	require.NoError(t, c.update(ctx, cluster, second))
+	assert.Equal(t, int32(3), *read().Spec.MinSize)
+
+	data, err := basePath.Join("nodes").ReadFile()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "resourceVersion")
+}
+
+func fi32(v int32) *int32 {
+	return &v
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/lease"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// PathHistory is the path, relative to the cluster, under which revisions are recorded
+const PathHistory = "history"
+
+// maxRevisionAttempts bounds how often we retry when another writer takes the revision number we chose
+const maxRevisionAttempts = 5
+
+// historyAuthor returns who is writing to the state store
+var historyAuthor = lease.CurrentUser
+
+// historyVFS records a revision for every write of a cluster or instance group.
+// Revisions are plain files, so history works on every vfs backend, whether or not it supports object versioning.
+type historyVFS struct {
+	basePath vfs.Path
+}
+
+func newHistoryVFS(clusterBasePath vfs.Path) *historyVFS {
+	return &historyVFS{basePath: clusterBasePath.Join(PathHistory)}
+}
+
+// record stores a revision of an object that was written with newData, replacing oldData (empty for a new object).
+func (h *historyVFS) record(cluster *kops.Cluster, kind string, name string, oldData []byte, newData []byte) error {
+	revision := &simple.ClusterRevision{
+		Kind:      kind,
+		Name:      name,
+		Author:    historyAuthor(),
+		Timestamp: time.Now().UTC(),
+		Object:    string(newData),
+	}
+	if len(oldData) != 0 {
+		revision.Diff = diff.FormatDiff(string(oldData), string(newData))
+	}
+
+	for attempt := 0; ; attempt++ {
+		latest, err := h.latestRevision()
+		if err != nil {
+			return err
+		}
+		revision.Revision = latest + 1
+
+		data, err := yaml.Marshal(revision)
+		if err != nil {
+			return fmt.Errorf("error serializing revision: %v", err)
+		}
+
+		p := h.basePath.Join(revisionFileName(revision.Revision))
+		acl, err := acls.GetACL(p, cluster)
+		if err != nil {
+			return err
+		}
+		err = p.CreateFile(bytes.NewReader(data), acl)
+		if err == nil {
+			return nil
+		}
+		if !os.IsExist(err) || attempt+1 >= maxRevisionAttempts {
+			return fmt.Errorf("error writing revision %d of %s %q: %v", revision.Revision, kind, name, err)
+		}
+		// Another writer recorded the same revision number; try the next one
+	}
+}
+
+// list returns all revisions, oldest first.
+func (h *historyVFS) list(ctx context.Context) ([]*simple.ClusterRevision, error) {
+	numbers, err := h.revisionNumbers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*simple.ClusterRevision
+	for _, n := range numbers {
+		p := h.basePath.Join(revisionFileName(n))
+		data, err := p.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("error reading revision %s: %v", p, err)
+		}
+		revision := &simple.ClusterRevision{}
+		if err := yaml.Unmarshal(data, revision); err != nil {
+			return nil, fmt.Errorf("error parsing revision %s: %v", p, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+func (h *historyVFS) latestRevision() (int, error) {
+	numbers, err := h.revisionNumbers(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	if len(numbers) == 0 {
+		return 0, nil
+	}
+	return numbers[len(numbers)-1], nil
+}
+
+// revisionNumbers returns the numbers of the recorded revisions, in ascending order
+func (h *historyVFS) revisionNumbers(ctx context.Context) ([]int, error) {
+	names, err := listChildNames(ctx, h.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []int
+	for _, name := range names {
+		n, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+func revisionFileName(revision int) string {
+	return fmt.Sprintf("%08d", revision)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestHistoryRecordAndList(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+	clusterBase, err := vfs.Context.BuildVfsPath("memfs://state/cluster.example.com")
+	require.NoError(t, err)
+	cluster := &kops.Cluster{}
+	cluster.Name = "cluster.example.com"
+
+	defer func(f func() string) { historyAuthor = f }(historyAuthor)
+	historyAuthor = func() string { return "alice@workstation" }
+
+	h := newHistoryVFS(clusterBase)
+	ctx := context.TODO()
+
+	revisions, err := h.list(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	require.NoError(t, h.record(cluster, "Cluster", "cluster.example.com", nil, []byte("spec:\n  a: 1\n")))
+	require.NoError(t, h.record(cluster, "InstanceGroup", "nodes", nil, []byte("spec:\n  minSize: 1\n")))
+	require.NoError(t, h.record(cluster, "Cluster", "cluster.example.com", []byte("spec:\n  a: 1\n"), []byte("spec:\n  a: 2\n")))
+
+	// Files that are not revisions are ignored
+	require.NoError(t, clusterBase.Join(PathHistory, "README").WriteFile(strings.NewReader("notes"), nil))
+
+	revisions, err = h.list(ctx)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+
+	for i, r := range revisions {
+		assert.Equal(t, i+1, r.Revision)
+		assert.Equal(t, "alice@workstation", r.Author)
+		assert.False(t, r.Timestamp.IsZero())
+	}
+
+	assert.Equal(t, "Cluster", revisions[0].Kind)
+	assert.Equal(t, "spec:\n  a: 1\n", revisions[0].Object)
+	assert.Empty(t, revisions[0].Diff)
+
+	assert.Equal(t, "InstanceGroup", revisions[1].Kind)
+	assert.Equal(t, "nodes", revisions[1].Name)
+
+	assert.Equal(t, "spec:\n  a: 2\n", revisions[2].Object)
+	assert.Contains(t, revisions[2].Diff, "-   a: 1")
+	assert.Contains(t, revisions[2].Diff, "+   a: 2")
+
+	_, err = clusterBase.Join(PathHistory, "00000003").ReadFile()
+	assert.NoError(t, err, "revisions should be stored as numbered files")
+}
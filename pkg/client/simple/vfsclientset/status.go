@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// PathStatus is the path, relative to the cluster, of the recorded status
+const PathStatus = "status.yaml"
+
+// readRecordedStatus reads the status recorded at p, returning an empty status if none was recorded.
+func readRecordedStatus(p vfs.Path) (*simple.RecordedStatus, error) {
+	status := &simple.RecordedStatus{}
+
+	data, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return status, nil
+		}
+		return nil, fmt.Errorf("error reading status %s: %v", p, err)
+	}
+	if err := yaml.Unmarshal(data, status); err != nil {
+		return nil, fmt.Errorf("error parsing status %s: %v", p, err)
+	}
+	return status, nil
+}
+
+// writeRecordedStatus replaces the status recorded at p.
+func writeRecordedStatus(p vfs.Path, cluster *kops.Cluster, status *simple.RecordedStatus) error {
+	data, err := yaml.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("error serializing status: %v", err)
+	}
+
+	acl, err := acls.GetACL(p, cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteFile(bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("error writing status %s: %v", p, err)
+	}
+	return nil
+}
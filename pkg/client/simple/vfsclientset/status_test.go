@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestRecordedStatus(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://state")
+	require.NoError(t, err)
+	clientset := NewVFSClientset(basePath)
+	cluster := &kops.Cluster{}
+	cluster.Name = "cluster.example.com"
+	ctx := context.TODO()
+
+	status, err := clientset.GetRecordedStatus(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, &simple.RecordedStatus{}, status)
+
+	status.Cluster.AppliedSpecHash = "abc123"
+	status.Cluster.AppliedKopsVersion = "1.22.0"
+	status.Cluster.Conditions = []metav1.Condition{
+		{
+			Type:   kops.ClusterConditionApplied,
+			Status: metav1.ConditionTrue,
+			Reason: "Applied",
+		},
+	}
+	status.InstanceGroups = map[string]*kops.InstanceGroupStatus{
+		"nodes": {Instances: 3, NeedUpdate: 1},
+	}
+	require.NoError(t, clientset.UpdateRecordedStatus(ctx, cluster, status))
+
+	_, err = basePath.Join("cluster.example.com", PathStatus).ReadFile()
+	require.NoError(t, err, "status should be stored alongside the cluster spec")
+
+	recorded, err := clientset.GetRecordedStatus(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", recorded.Cluster.AppliedSpecHash)
+	assert.Equal(t, "1.22.0", recorded.Cluster.AppliedKopsVersion)
+	require.Len(t, recorded.Cluster.Conditions, 1)
+	assert.Equal(t, metav1.ConditionTrue, recorded.Cluster.Conditions[0].Status)
+	require.Contains(t, recorded.InstanceGroups, "nodes")
+	assert.Equal(t, int32(3), recorded.InstanceGroups["nodes"].Instances)
+	assert.Equal(t, int32(1), recorded.InstanceGroups["nodes"].NeedUpdate)
+
+	// The status must not stop the cluster from being deleted
+	require.NoError(t, DeleteAllClusterState(basePath.Join("cluster.example.com")))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// Kind is the way in which a cloud resource has drifted from the model.
+type Kind string
+
+const (
+	// KindChanged is a resource whose fields differ from the model.
+	KindChanged Kind = "Changed"
+	// KindMissing is a resource of the model that does not exist.
+	KindMissing Kind = "Missing"
+	// KindExtra is a resource that the model would delete.
+	KindExtra Kind = "Extra"
+	// KindUnowned is a resource tagged for the cluster that is not part of the model.
+	KindUnowned Kind = "Unowned"
+)
+
+// Drift is a cloud resource that has drifted from the model.
+type Drift struct {
+	Kind Kind   `json:"kind"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// ID is the cloud ID of an unowned resource
+	ID string `json:"id,omitempty"`
+	// Fields are the fields that differ from the model
+	Fields []*fi.DryRunReportField `json:"fields,omitempty"`
+}
+
+// ignoredResourceTypes are types of cluster resources that are not modelled as tasks,
+// because they are created by other resources (e.g. instances by autoscaling groups)
+// or by components running in the cluster (e.g. DNS records by dns-controller).
+// Types are compared case-insensitively, as the cloud providers use different conventions.
+var ignoredResourceTypes = map[string]bool{
+	"instance":       true,
+	"volume":         true,
+	"disk":           true,
+	"route53-record": true,
+	"dnsrecord":      true,
+}
+
+// FromDryRunReport returns the drift found by a dry run of the model.
+func FromDryRunReport(report *fi.DryRunReport) []*Drift {
+	var drift []*Drift
+	for _, change := range report.Changes {
+		d := &Drift{
+			Type:   change.Type,
+			Name:   change.Name,
+			Fields: change.Fields,
+		}
+		switch change.Action {
+		case fi.DryRunActionCreate:
+			d.Kind = KindMissing
+			// The fields of a missing resource are the whole model, not a difference
+			d.Fields = nil
+		case fi.DryRunActionModify:
+			d.Kind = KindChanged
+		case fi.DryRunActionDelete:
+			d.Kind = KindExtra
+		}
+		drift = append(drift, d)
+	}
+	return drift
+}
+
+// FindUnowned returns the cluster resources that do not belong to any task of the model.
+// A resource belongs to a task if its ID or name matches the ID or the name of the task.
+// Resources whose cluster ownership tags (kubernetes.io/cluster/<name> or KubernetesCluster) do not mark them
+// as owned by the cluster, such as subnets shared with it, are never reported.
+func FindUnowned(clusterName string, taskMap map[string]fi.Task, clusterResources map[string]*resources.Resource) []*Drift {
+	owned := make(map[string]bool)
+	for _, task := range taskMap {
+		if compareWithID, ok := task.(fi.CompareWithID); ok {
+			if id := fi.StringValue(compareWithID.CompareWithID()); id != "" {
+				owned[id] = true
+			}
+		}
+		if hasName, ok := task.(fi.HasName); ok {
+			if name := fi.StringValue(hasName.GetName()); name != "" {
+				owned[name] = true
+			}
+		}
+	}
+
+	var drift []*Drift
+	for _, r := range clusterResources {
+		if r.Shared || ignoredResourceTypes[strings.ToLower(r.Type)] {
+			continue
+		}
+		if tags := resourceTags(r); len(tags) != 0 && !ownedByCluster(clusterName, tags) {
+			continue
+		}
+		if owned[r.ID] || (r.Name != "" && owned[r.Name]) {
+			continue
+		}
+		drift = append(drift, &Drift{
+			Kind: KindUnowned,
+			Type: r.Type,
+			Name: r.Name,
+			ID:   r.ID,
+		})
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Type != drift[j].Type {
+			return drift[i].Type < drift[j].Type
+		}
+		return drift[i].ID < drift[j].ID
+	})
+	return drift
+}
+
+// ownedByCluster returns true if the cluster ownership tags mark a resource as owned by the cluster.
+// The kubernetes.io/cluster/<name> tag takes precedence over the legacy KubernetesCluster tag.
+func ownedByCluster(clusterName string, tags map[string]string) bool {
+	if lifecycle, found := tags["kubernetes.io/cluster/"+clusterName]; found {
+		return lifecycle == "owned"
+	}
+	return tags["KubernetesCluster"] == clusterName
+}
+
+// resourceTags returns the tags of the cloud object of a resource, read from a Tags field of Key and Value pairs
+// as used by the AWS API (e.g. *ec2.Subnet). It returns nil if the object has no such tags.
+func resourceTags(r *resources.Resource) map[string]string {
+	v := reflect.Indirect(reflect.ValueOf(r.Obj))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	field := v.FieldByName("Tags")
+	if field.Kind() != reflect.Slice {
+		return nil
+	}
+
+	tags := make(map[string]string)
+	for i := 0; i < field.Len(); i++ {
+		tag := reflect.Indirect(field.Index(i))
+		if tag.Kind() != reflect.Struct {
+			continue
+		}
+		key, value := stringValue(tag.FieldByName("Key")), stringValue(tag.FieldByName("Value"))
+		if key != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// stringValue returns the value of a string or *string field, or "" if it is not set
+func stringValue(v reflect.Value) string {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.String {
+		return ""
+	}
+	return v.String()
+}
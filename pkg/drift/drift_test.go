@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type testTask struct {
+	Name *string
+	ID   *string
+}
+
+var _ fi.CompareWithID = &testTask{}
+var _ fi.HasName = &testTask{}
+
+func (t *testTask) Run(_ *fi.Context) error {
+	panic("not implemented")
+}
+
+func (t *testTask) CompareWithID() *string {
+	return t.ID
+}
+
+func (t *testTask) GetName() *string {
+	return t.Name
+}
+
+func TestFromDryRunReport(t *testing.T) {
+	fields := []*fi.DryRunReportField{{Name: "Tags", Old: "{a: 1}", New: "{a: 2}"}}
+	report := &fi.DryRunReport{
+		Changes: []*fi.DryRunReportChange{
+			{Key: "SecurityGroup/nodes", Type: "SecurityGroup", Name: "nodes", Action: fi.DryRunActionCreate, Fields: fields},
+			{Key: "SecurityGroup/masters", Type: "SecurityGroup", Name: "masters", Action: fi.DryRunActionModify, Fields: fields},
+			{Key: "LaunchTemplate/nodes-1", Type: "LaunchTemplate", Name: "nodes-1", Action: fi.DryRunActionDelete},
+		},
+	}
+
+	expected := []*Drift{
+		{Kind: KindMissing, Type: "SecurityGroup", Name: "nodes"},
+		{Kind: KindChanged, Type: "SecurityGroup", Name: "masters", Fields: fields},
+		{Kind: KindExtra, Type: "LaunchTemplate", Name: "nodes-1"},
+	}
+	assert.Equal(t, expected, FromDryRunReport(report))
+}
+
+func TestFindUnowned(t *testing.T) {
+	taskMap := map[string]fi.Task{
+		"SecurityGroup/nodes":           &testTask{Name: fi.String("nodes.example.com"), ID: fi.String("sg-1")},
+		"AutoscalingGroup/nodes":        &testTask{Name: fi.String("nodes.example.com")},
+		"SecurityGroup/masters-unfound": &testTask{Name: fi.String("masters.example.com")},
+	}
+	clusterResources := map[string]*resources.Resource{
+		"security-group:sg-1":                  {Type: "security-group", ID: "sg-1", Name: "nodes.example.com"},
+		"security-group:sg-2":                  {Type: "security-group", ID: "sg-2", Name: "console.example.com"},
+		"autoscaling-group:nodes.example.com":  {Type: "autoscaling-group", ID: "nodes.example.com", Name: "nodes.example.com"},
+		"autoscaling-group:extra.example.com":  {Type: "autoscaling-group", ID: "extra.example.com", Name: "extra.example.com"},
+		"instance:i-1":                         {Type: "instance", ID: "i-1", Name: "nodes.example.com"},
+		"Instance:gce-1":                       {Type: "Instance", ID: "gce-1"},
+		"route53-record:api.example.com":       {Type: "route53-record", ID: "api.example.com"},
+		"vpc:vpc-1":                            {Type: "vpc", ID: "vpc-1", Shared: true},
+		"security-group:sg-3":                  {Type: "security-group", ID: "sg-3"},
+		"iam-role:masters.example.com-renamed": {Type: "iam-role", ID: "masters.example.com-renamed", Name: "masters.example.com-renamed"},
+		"subnet:subnet-shared": {
+			Type: "subnet",
+			ID:   "subnet-shared",
+			Name: "shared",
+			Obj:  &ec2.Subnet{Tags: []*ec2.Tag{{Key: aws.String("kubernetes.io/cluster/example.com"), Value: aws.String("shared")}}},
+		},
+		"subnet:subnet-other": {
+			Type: "subnet",
+			ID:   "subnet-other",
+			Obj:  &ec2.Subnet{Tags: []*ec2.Tag{{Key: aws.String("KubernetesCluster"), Value: aws.String("other.example.com")}}},
+		},
+		"subnet:subnet-owned": {
+			Type: "subnet",
+			ID:   "subnet-owned",
+			Obj:  &ec2.Subnet{Tags: []*ec2.Tag{{Key: aws.String("kubernetes.io/cluster/example.com"), Value: aws.String("owned")}}},
+		},
+		"route-table:rtb-legacy": {
+			Type: "route-table",
+			ID:   "rtb-legacy",
+			Obj:  &ec2.RouteTable{Tags: []*ec2.Tag{{Key: aws.String("KubernetesCluster"), Value: aws.String("example.com")}}},
+		},
+	}
+
+	expected := []*Drift{
+		{Kind: KindUnowned, Type: "autoscaling-group", ID: "extra.example.com", Name: "extra.example.com"},
+		{Kind: KindUnowned, Type: "iam-role", ID: "masters.example.com-renamed", Name: "masters.example.com-renamed"},
+		{Kind: KindUnowned, Type: "route-table", ID: "rtb-legacy"},
+		{Kind: KindUnowned, Type: "security-group", ID: "sg-2", Name: "console.example.com"},
+		{Kind: KindUnowned, Type: "security-group", ID: "sg-3"},
+		{Kind: KindUnowned, Type: "subnet", ID: "subnet-owned"},
+	}
+	assert.Equal(t, expected, FindUnowned("example.com", taskMap, clusterResources))
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// defaultCanarySoakDuration is how long canaries are held if the RollingUpdateCanary does not specify a soak duration.
+const defaultCanarySoakDuration = 5 * time.Minute
+
+// canaryCount returns the number of instances to replace in the canary phase, out of numUpdate instances needing update.
+func canaryCount(canary *api.RollingUpdateCanary, numUpdate int) int {
+	count := 1
+	if canary.Instances != nil {
+		count, _ = intstr.GetValueFromIntOrPercent(canary.Instances, numUpdate, true)
+	}
+	if count < 1 {
+		count = 1
+	}
+	if count > numUpdate {
+		count = numUpdate
+	}
+	return count
+}
+
+// rollingUpdateCanaries replaces the canary instances of a group and holds them for the soak period.
+// If the canary phase fails, the group is rolled back to the specification of its outdated instances,
+// unless rollback is disabled, and an error is returned so the rolling update stops.
+func (c *RollingUpdateCluster) rollingUpdateCanaries(group *cloudinstances.CloudInstanceGroup, canaries []*cloudinstances.CloudInstance, canary *api.RollingUpdateCanary, sleepAfterTerminate time.Duration) error {
+	name := group.InstanceGroup.Name
+	startTime := time.Now()
+
+	klog.Infof("Replacing %d canary instance(s) in instance group %q.", len(canaries), name)
+	err := c.replaceCanaries(group, canaries, canary, sleepAfterTerminate)
+
+	event := &Event{
+		Type:          EventCanaryFinished,
+		InstanceGroup: name,
+		Duration:      &metav1.Duration{Duration: time.Since(startTime)},
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	c.emit(event)
+
+	if err == nil {
+		klog.Infof("Canary instances in instance group %q passed.", name)
+		return nil
+	}
+
+	if canary.Rollback != nil && !*canary.Rollback {
+		return fmt.Errorf("canary phase of instance group %q failed: %v", name, err)
+	}
+
+	klog.Warningf("Canary phase of instance group %q failed, rolling back: %v", name, err)
+	if rollbackErr := c.rollbackGroup(group, sleepAfterTerminate); rollbackErr != nil {
+		return fmt.Errorf("canary phase of instance group %q failed: %v; error rolling back: %v", name, err, rollbackErr)
+	}
+	return fmt.Errorf("canary phase of instance group %q failed and was rolled back: %v", name, err)
+}
+
+// replaceCanaries replaces the canary instances one at a time, then soaks them.
+func (c *RollingUpdateCluster) replaceCanaries(group *cloudinstances.CloudInstanceGroup, canaries []*cloudinstances.CloudInstance, canary *api.RollingUpdateCanary, sleepAfterTerminate time.Duration) error {
+	for _, u := range canaries {
+		if err := c.drainTerminateAndWait(u, sleepAfterTerminate); err != nil {
+			return err
+		}
+		if err := c.maybeValidate(" after terminating canary instance", c.ValidateCount, group); err != nil {
+			return err
+		}
+	}
+
+	soakDuration := defaultCanarySoakDuration
+	if canary.SoakDuration != nil {
+		soakDuration = canary.SoakDuration.Duration
+	}
+	return c.soakCanaries(group, soakDuration)
+}
+
+// soakCanaries requires the cluster to keep validating, and the AfterValidate hooks to keep passing,
+// for the soak duration. Unlike validateClusterWithTimeout, any failure fails the canary phase.
+func (c *RollingUpdateCluster) soakCanaries(group *cloudinstances.CloudInstanceGroup, soakDuration time.Duration) error {
+	klog.Infof("Soaking canary instances in instance group %q for %s.", group.InstanceGroup.Name, soakDuration)
+	deadline := time.Now().Add(soakDuration)
+
+	for {
+		if !c.CloudOnly {
+			validateStart := time.Now()
+			result, err := c.ClusterValidator.Validate()
+			c.emitValidation(group, result, time.Since(validateStart), err)
+			if err != nil {
+				return fmt.Errorf("error validating cluster during soak: %v", err)
+			}
+			if hasFailureRelevantToGroup(result.Failures, group) {
+				messages := []string{}
+				for _, failure := range result.Failures {
+					messages = append(messages, failure.Message)
+				}
+				return fmt.Errorf("cluster did not pass validation during soak: %s", strings.Join(messages, ", "))
+			}
+		}
+
+		if err := c.runAfterValidateHooks(group); err != nil {
+			return err
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil
+		}
+		time.Sleep(c.ValidateTickDuration)
+	}
+}
+
+// rollbackGroup restores the previous instance specification of a group and replaces the instances
+// that were created from the new specification.
+func (c *RollingUpdateCluster) rollbackGroup(group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration) error {
+	rollbacker, ok := c.Cloud.(fi.InstanceGroupRollbacker)
+	if !ok {
+		return fmt.Errorf("rollback is not supported by cloud provider %q", c.Cloud.ProviderID())
+	}
+
+	if err := rollbacker.RollbackGroup(group); err != nil {
+		return err
+	}
+	c.emit(&Event{Type: EventRolledBack, InstanceGroup: group.InstanceGroup.Name})
+
+	var nodes []corev1.Node
+	if c.K8sClient != nil {
+		nodeList, err := c.K8sClient.CoreV1().Nodes().List(c.Ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing nodes: %v", err)
+		}
+		nodes = nodeList.Items
+	}
+
+	groups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nodes)
+	if err != nil {
+		return err
+	}
+
+	for _, rolledBack := range groups {
+		if rolledBack.InstanceGroup != group.InstanceGroup {
+			continue
+		}
+		for _, u := range rolledBack.NeedUpdate {
+			klog.Infof("Replacing canary instance %q in instance group %q.", u.ID, group.InstanceGroup.Name)
+			if err := c.drainTerminateAndWait(u, sleepAfterTerminate); err != nil {
+				return err
+			}
+		}
+		if len(rolledBack.NeedUpdate) > 0 {
+			if err := c.maybeValidate(" after rolling back", c.ValidateCount, rolledBack); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// rollbackCloud records rollbacks and, after one, reports the canary replacement as needing update.
+type rollbackCloud struct {
+	*awsup.MockAWSCloud
+	rolledBack []string
+	replaced   string
+}
+
+func (c *rollbackCloud) RollbackGroup(g *cloudinstances.CloudInstanceGroup) error {
+	c.rolledBack = append(c.rolledBack, g.InstanceGroup.Name)
+	return nil
+}
+
+func (c *rollbackCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		group := &cloudinstances.CloudInstanceGroup{
+			HumanName:     ig.Name,
+			InstanceGroup: ig,
+			Raw:           &autoscaling.Group{AutoScalingGroupName: aws.String("asg-" + ig.Name)},
+		}
+		if _, err := group.NewCloudInstance(c.replaced, cloudinstances.CloudInstanceStatusNeedsUpdate, nil); err != nil {
+			return nil, err
+		}
+		groups[ig.Name] = group
+	}
+	return groups, nil
+}
+
+// failOnceClusterValidator fails only the given call, counting from 1.
+type failOnceClusterValidator struct {
+	calls  int
+	failOn int
+}
+
+func (v *failOnceClusterValidator) Validate() (*validation.ValidationCluster, error) {
+	v.calls++
+	if v.calls == v.failOn {
+		return &validation.ValidationCluster{
+			Failures: []*validation.ValidationError{
+				{
+					Kind:    "testing",
+					Name:    "testing failure",
+					Message: "testing failure",
+				},
+			},
+		}, nil
+	}
+	return &validation.ValidationCluster{}, nil
+}
+
+func getCanaryTestSetup(t *testing.T, count int, canary *kopsapi.RollingUpdateCanary) (*RollingUpdateCluster, *rollbackCloud, map[string]*cloudinstances.CloudInstanceGroup) {
+	c, mockcloud := getTestSetup()
+	cloud := &rollbackCloud{MockAWSCloud: mockcloud, replaced: "node-1z"}
+	c.Cloud = cloud
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, mockcloud, "node-1", kopsapi.InstanceGroupRoleNode, count, count)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Canary: canary,
+	}
+
+	// The replacement launched for the canary, which is replaced again after a rollback.
+	_, err := mockcloud.Autoscaling().AttachInstances(&autoscaling.AttachInstancesInput{
+		AutoScalingGroupName: aws.String("node-1"),
+		InstanceIds:          []*string{aws.String(cloud.replaced)},
+	})
+	if err != nil {
+		t.Fatalf("error attaching instance: %v", err)
+	}
+
+	return c, cloud, groups
+}
+
+func TestCanaryCount(t *testing.T) {
+	grid := []struct {
+		instances *intstr.IntOrString
+		numUpdate int
+		expected  int
+	}{
+		{nil, 10, 1},
+		{intStr(intstr.FromInt(3)), 10, 3},
+		{intStr(intstr.FromInt(3)), 2, 2},
+		{intStr(intstr.FromString("10%")), 15, 2},
+		{intStr(intstr.FromString("1%")), 15, 1},
+	}
+	for _, g := range grid {
+		actual := canaryCount(&kopsapi.RollingUpdateCanary{Instances: g.instances}, g.numUpdate)
+		assert.Equal(t, g.expected, actual, "canaryCount(%v, %d)", g.instances, g.numUpdate)
+	}
+}
+
+func intStr(i intstr.IntOrString) *intstr.IntOrString {
+	return &i
+}
+
+func TestRollingUpdateCanaryPasses(t *testing.T) {
+	c, cloud, groups := getCanaryTestSetup(t, 4, &kopsapi.RollingUpdateCanary{
+		SoakDuration: &metav1.Duration{Duration: 0},
+	})
+	var events bytes.Buffer
+	c.Events = &events
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+	assert.Empty(t, cloud.rolledBack, "rolled back groups")
+
+	var canaryFinished []Event
+	decoder := json.NewDecoder(&events)
+	for decoder.More() {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("error decoding event: %v", err)
+		}
+		if event.Type == EventCanaryFinished {
+			canaryFinished = append(canaryFinished, event)
+		}
+	}
+	if assert.Len(t, canaryFinished, 1, "CanaryFinished events") {
+		assert.Empty(t, canaryFinished[0].Error, "CanaryFinished error")
+	}
+}
+
+func TestRollingUpdateCanaryRollsBack(t *testing.T) {
+	c, cloud, groups := getCanaryTestSetup(t, 4, &kopsapi.RollingUpdateCanary{
+		Instances:    intStr(intstr.FromInt(2)),
+		SoakDuration: &metav1.Duration{Duration: 0},
+	})
+	// Validation passes before the update and after each canary, then fails during the soak.
+	c.ClusterValidator = &failOnceClusterValidator{failOn: 1 + 2*c.ValidateCount + 1}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "was rolled back")
+	}
+
+	assert.Equal(t, []string{"node-1"}, cloud.rolledBack, "rolled back groups")
+	// Two canaries and their replacement were terminated; the others were left alone.
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateCanaryHookFailureWithoutRollback(t *testing.T) {
+	c, cloud, groups := getCanaryTestSetup(t, 3, &kopsapi.RollingUpdateCanary{
+		SoakDuration: &metav1.Duration{Duration: 0},
+		Rollback:     fi.Bool(false),
+	})
+	c.Hooks = []InstanceHook{&failingAfterValidateHook{}}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "after-validate hook failed")
+	}
+
+	assert.Empty(t, cloud.rolledBack, "rolled back groups")
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+type failingAfterValidateHook struct {
+	recordingHook
+}
+
+func (h *failingAfterValidateHook) AfterValidate(ctx context.Context, group *cloudinstances.CloudInstanceGroup) error {
+	return errors.New("hook failure")
+}
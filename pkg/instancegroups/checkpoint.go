@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// RollingUpdateCheckpoint records the progress of a rolling update, so that an interrupted
+// rolling update can be resumed where it stopped.
+type RollingUpdateCheckpoint struct {
+	// Selection is the selection of instance groups the rolling update was started with.
+	Selection RollingUpdateSelection `json:"selection,omitempty"`
+	// CompletedInstanceGroups are the names of the instance groups that have been fully updated.
+	CompletedInstanceGroups []string `json:"completedInstanceGroups,omitempty"`
+	// DetachedInstances are the IDs of the instances that have been detached.
+	DetachedInstances []string `json:"detachedInstances,omitempty"`
+	// TerminatedInstances are the IDs of the instances that have been terminated.
+	TerminatedInstances []string `json:"terminatedInstances,omitempty"`
+	// LastValidation is the result of the most recent cluster validation.
+	LastValidation *CheckpointValidation `json:"lastValidation,omitempty"`
+}
+
+// RollingUpdateSelection is the selection of instance groups to update.
+// A rolling update can only be resumed with the selection it was started with.
+type RollingUpdateSelection struct {
+	// InstanceGroups are the names of the selected instance groups; all instance groups if empty.
+	InstanceGroups []string `json:"instanceGroups,omitempty"`
+	// InstanceGroupRoles are the selected instance group roles; all roles if empty.
+	InstanceGroupRoles []string `json:"instanceGroupRoles,omitempty"`
+}
+
+// normalize returns a copy of the selection, with sorted values so it can be compared.
+func (s RollingUpdateSelection) normalize() RollingUpdateSelection {
+	normalized := RollingUpdateSelection{
+		InstanceGroups:     append([]string(nil), s.InstanceGroups...),
+		InstanceGroupRoles: append([]string(nil), s.InstanceGroupRoles...),
+	}
+	sort.Strings(normalized.InstanceGroups)
+	sort.Strings(normalized.InstanceGroupRoles)
+	return normalized
+}
+
+func (s RollingUpdateSelection) String() string {
+	groups := "all"
+	if len(s.InstanceGroups) != 0 {
+		groups = strings.Join(s.InstanceGroups, ",")
+	}
+	roles := "all"
+	if len(s.InstanceGroupRoles) != 0 {
+		roles = strings.Join(s.InstanceGroupRoles, ",")
+	}
+	return fmt.Sprintf("instance groups %s, roles %s", groups, roles)
+}
+
+// CheckpointValidation is the result of a cluster validation attempt.
+type CheckpointValidation struct {
+	// Time is when the validation was attempted.
+	Time time.Time `json:"time"`
+	// InstanceGroup is the instance group being updated when the validation was attempted.
+	InstanceGroup string `json:"instanceGroup,omitempty"`
+	// Succeeded is true if the cluster passed validation.
+	Succeeded bool `json:"succeeded"`
+	// Failures are the messages of the validation failures, if any.
+	Failures []string `json:"failures,omitempty"`
+	// Error is the error returned by the validator, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ReadCheckpoint reads the rolling update checkpoint at the specified path.
+// It returns nil if there is no checkpoint.
+func ReadCheckpoint(p vfs.Path) (*RollingUpdateCheckpoint, error) {
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rolling update checkpoint %s: %v", p, err)
+	}
+
+	checkpoint := &RollingUpdateCheckpoint{}
+	if err := json.Unmarshal(b, checkpoint); err != nil {
+		return nil, fmt.Errorf("error parsing rolling update checkpoint %s: %v", p, err)
+	}
+	return checkpoint, nil
+}
+
+// checkpointTracker keeps the in-memory checkpoint and persists it on every change.
+// A nil tracker, or one without a path, records nothing.
+type checkpointTracker struct {
+	mutex sync.Mutex
+	path  vfs.Path
+	state RollingUpdateCheckpoint
+}
+
+// loadCheckpoint initializes the checkpoint tracker, restoring the previous progress when resuming.
+func (c *RollingUpdateCluster) loadCheckpoint() error {
+	selection := c.Selection.normalize()
+	c.checkpoint = &checkpointTracker{
+		path:  c.CheckpointPath,
+		state: RollingUpdateCheckpoint{Selection: selection},
+	}
+	if c.CheckpointPath == nil {
+		if c.Resume {
+			return fmt.Errorf("cannot resume rolling update without a checkpoint location")
+		}
+		return nil
+	}
+
+	existing, err := ReadCheckpoint(c.CheckpointPath)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if c.Resume {
+			klog.Infof("No rolling update checkpoint found at %s; starting from the beginning.", c.CheckpointPath)
+		}
+		return nil
+	}
+
+	if !c.Resume {
+		klog.Warningf("Discarding progress of a previous rolling update recorded at %s; use --resume to continue it instead.", c.CheckpointPath)
+		return nil
+	}
+
+	if recorded := existing.Selection.normalize(); !reflect.DeepEqual(recorded, selection) {
+		return fmt.Errorf("cannot resume the rolling update recorded at %s, which was started for %s, with %s; use the same --instance-group and --instance-group-roles, or omit --resume to start over",
+			c.CheckpointPath, recorded, selection)
+	}
+
+	klog.Infof("Resuming rolling update: %d instance groups completed, %d instances detached, %d instances terminated.",
+		len(existing.CompletedInstanceGroups), len(existing.DetachedInstances), len(existing.TerminatedInstances))
+	c.checkpoint.state = *existing
+	return nil
+}
+
+func (t *checkpointTracker) isGroupCompleted(name string) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return contains(t.state.CompletedInstanceGroups, name)
+}
+
+func (t *checkpointTracker) isDetached(id string) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return contains(t.state.DetachedInstances, id)
+}
+
+func (t *checkpointTracker) isTerminated(id string) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return contains(t.state.TerminatedInstances, id)
+}
+
+func (t *checkpointTracker) groupCompleted(name string) error {
+	return t.update(func(state *RollingUpdateCheckpoint) {
+		if !contains(state.CompletedInstanceGroups, name) {
+			state.CompletedInstanceGroups = append(state.CompletedInstanceGroups, name)
+		}
+	})
+}
+
+func (t *checkpointTracker) instanceDetached(id string) error {
+	return t.update(func(state *RollingUpdateCheckpoint) {
+		if !contains(state.DetachedInstances, id) {
+			state.DetachedInstances = append(state.DetachedInstances, id)
+		}
+	})
+}
+
+func (t *checkpointTracker) instanceTerminated(id string) error {
+	return t.update(func(state *RollingUpdateCheckpoint) {
+		if !contains(state.TerminatedInstances, id) {
+			state.TerminatedInstances = append(state.TerminatedInstances, id)
+		}
+	})
+}
+
+// validated records the outcome of a validation attempt. Failing to persist it is not fatal.
+func (t *checkpointTracker) validated(groupName string, result *validation.ValidationCluster, validationErr error) {
+	err := t.update(func(state *RollingUpdateCheckpoint) {
+		v := &CheckpointValidation{
+			Time:          time.Now().UTC(),
+			InstanceGroup: groupName,
+		}
+		if validationErr != nil {
+			v.Error = validationErr.Error()
+		} else {
+			for _, failure := range result.Failures {
+				v.Failures = append(v.Failures, failure.Message)
+			}
+			v.Succeeded = len(v.Failures) == 0
+		}
+		state.LastValidation = v
+	})
+	if err != nil {
+		klog.Warningf("unable to record validation result: %v", err)
+	}
+}
+
+// remove deletes the persisted checkpoint, once the rolling update has completed.
+func (t *checkpointTracker) remove() error {
+	if t == nil || t.path == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err := t.path.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing rolling update checkpoint %s: %v", t.path, err)
+	}
+	return nil
+}
+
+func (t *checkpointTracker) update(fn func(state *RollingUpdateCheckpoint)) error {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	fn(&t.state)
+
+	if t.path == nil {
+		return nil
+	}
+	b, err := json.Marshal(&t.state)
+	if err != nil {
+		return fmt.Errorf("error serializing rolling update checkpoint: %v", err)
+	}
+	if err := t.path.WriteFile(bytes.NewReader(b), nil); err != nil {
+		return fmt.Errorf("error writing rolling update checkpoint %s: %v", t.path, err)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// drainBatch is a set of instances whose nodes can be drained concurrently
+// without exceeding the allowed disruptions of any PodDisruptionBudget.
+type drainBatch struct {
+	instances []*cloudinstances.CloudInstance
+	// used is the number of pods covered by each PodDisruptionBudget on the nodes of the batch.
+	used map[string]int
+	// overBudget is set when the pods on the node of the single instance in the batch exceed a budget on their own.
+	overBudget bool
+}
+
+// drainPlan is the order in which the instances of a group are drained, split into batches.
+// Instances of the same batch may be drained concurrently; the running drains are finished
+// before an instance of another batch is drained.
+type drainPlan struct {
+	batches []*drainBatch
+	// budgets is the number of allowed disruptions of each PodDisruptionBudget, keyed by namespace/name.
+	budgets map[string]int
+}
+
+// instances returns the instances of the plan, in the order they are to be drained:
+// by batch and, within each batch, attached instances before detached ones.
+func (p *drainPlan) instances() []*cloudinstances.CloudInstance {
+	var result []*cloudinstances.CloudInstance
+	for _, b := range p.batches {
+		var detached []*cloudinstances.CloudInstance
+		for _, u := range b.instances {
+			if u.Status == cloudinstances.CloudInstanceStatusDetached {
+				detached = append(detached, u)
+			} else {
+				result = append(result, u)
+			}
+		}
+		result = append(result, detached...)
+	}
+	return result
+}
+
+// batchOf returns the index of the batch of each instance of the plan, keyed by instance ID.
+func (p *drainPlan) batchOf() map[string]int {
+	result := make(map[string]int)
+	for i, b := range p.batches {
+		for _, u := range b.instances {
+			result[u.ID] = i
+		}
+	}
+	return result
+}
+
+// reason describes why the instances of a batch were drained together.
+func (p *drainPlan) reason(b *drainBatch) string {
+	if len(b.used) == 0 {
+		return "no pods covered by a PodDisruptionBudget"
+	}
+
+	var keys []string
+	for key := range b.used {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var budgets []string
+	for _, key := range keys {
+		budgets = append(budgets, fmt.Sprintf("%s (%d of %d allowed disruptions)", key, b.used[key], p.budgets[key]))
+	}
+	if b.overBudget {
+		return "drained alone, as its pods exceed the allowed disruptions of " + strings.Join(budgets, ", ")
+	}
+	return "within the allowed disruptions of " + strings.Join(budgets, ", ")
+}
+
+// add appends instances to the plan, placing each in the first batch that has room
+// left in the budgets covering the pods on its node.
+func (p *drainPlan) add(instances []*cloudinstances.CloudInstance, usage map[string]map[string]int) {
+	for _, u := range instances {
+		pods := usage[u.ID]
+
+		var batch *drainBatch
+		for _, b := range p.batches {
+			if p.fits(b, pods) {
+				batch = b
+				break
+			}
+		}
+		if batch == nil {
+			batch = &drainBatch{used: make(map[string]int)}
+			batch.overBudget = !p.fits(batch, pods)
+			p.batches = append(p.batches, batch)
+		}
+
+		batch.instances = append(batch.instances, u)
+		for key, n := range pods {
+			batch.used[key] += n
+		}
+	}
+}
+
+// fits returns whether the pods of a node can be drained together with the batch.
+func (p *drainPlan) fits(b *drainBatch, pods map[string]int) bool {
+	if b.overBudget {
+		return len(pods) == 0
+	}
+	for key, n := range pods {
+		if b.used[key]+n > p.budgets[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// planDrains orders and batches the instances to update so that draining a batch does not
+// exceed the allowed disruptions of any PodDisruptionBudget. If the PodDisruptionBudgets or
+// pods cannot be read, the instances are drained in a single batch.
+func (c *RollingUpdateCluster) planDrains(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance) *drainPlan {
+	plan := &drainPlan{}
+	var usage map[string]map[string]int
+	if c.K8sClient != nil && !c.CloudOnly && !group.InstanceGroup.IsBastion() {
+		var err error
+		plan.budgets, usage, err = c.disruptionUsage(update)
+		if err != nil {
+			klog.Warningf("Unable to plan drains around PodDisruptionBudgets, draining instances in order: %v", err)
+			usage = nil
+		}
+	}
+
+	plan.add(update, usage)
+
+	if len(plan.batches) > 1 {
+		for i, b := range plan.batches {
+			var ids []string
+			for _, u := range b.instances {
+				ids = append(ids, u.ID)
+			}
+			reason := plan.reason(b)
+			klog.Infof("Drain batch %d of %d in instance group %q: %s; %s.", i+1, len(plan.batches), group.InstanceGroup.Name, strings.Join(ids, ", "), reason)
+			c.emit(&Event{
+				Type:          EventDrainBatchPlanned,
+				InstanceGroup: group.InstanceGroup.Name,
+				Instances:     ids,
+				Reason:        reason,
+			})
+		}
+	}
+
+	return plan
+}
+
+// disruptionUsage returns the allowed disruptions of each PodDisruptionBudget and, for each
+// instance, the number of pods on its node that would be evicted and are covered by each budget.
+func (c *RollingUpdateCluster) disruptionUsage(update []*cloudinstances.CloudInstance) (map[string]int, map[string]map[string]int, error) {
+	pdbs, err := c.K8sClient.PolicyV1beta1().PodDisruptionBudgets("").List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing PodDisruptionBudgets: %v", err)
+	}
+	if len(pdbs.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	pods, err := c.K8sClient.CoreV1().Pods("").List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing pods: %v", err)
+	}
+
+	instanceByNode := make(map[string]string)
+	for _, u := range update {
+		if u.Node != nil {
+			instanceByNode[u.Node.Name] = u.ID
+		}
+	}
+
+	budgets := make(map[string]int)
+	usage := make(map[string]map[string]int)
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		key := pdb.Namespace + "/" + pdb.Name
+		budgets[key] = int(pdb.Status.DisruptionsAllowed)
+
+		// An empty selector matches no pods in policy/v1beta1
+		if pdb.Spec.Selector == nil || (len(pdb.Spec.Selector.MatchLabels) == 0 && len(pdb.Spec.Selector.MatchExpressions) == 0) {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			klog.Warningf("Ignoring PodDisruptionBudget %q with invalid selector: %v", key, err)
+			continue
+		}
+
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+			id, found := instanceByNode[pod.Spec.NodeName]
+			if !found || pod.Namespace != pdb.Namespace || !isEvicted(pod) || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if usage[id] == nil {
+				usage[id] = make(map[string]int)
+			}
+			usage[id][key]++
+		}
+	}
+
+	return budgets, usage, nil
+}
+
+// isEvicted returns whether draining a node evicts the pod: it is running, and not a mirror or DaemonSet pod.
+func isEvicted(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, found := pod.Annotations[corev1.MirrorPodAnnotationKey]; found {
+		return false
+	}
+	if controller := metav1.GetControllerOf(pod); controller != nil && controller.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
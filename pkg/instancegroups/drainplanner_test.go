@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func addPodDisruptionBudget(t *testing.T, c *RollingUpdateCluster, name string, app string, disruptionsAllowed int32) {
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		},
+		Status: policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+	if err := c.K8sClient.(*fake.Clientset).Tracker().Add(pdb); err != nil {
+		t.Fatalf("error adding PodDisruptionBudget: %v", err)
+	}
+}
+
+func addPod(t *testing.T, c *RollingUpdateCluster, name string, app string, nodeName string) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	if err := c.K8sClient.(*fake.Clientset).Tracker().Add(pod); err != nil {
+		t.Fatalf("error adding pod: %v", err)
+	}
+}
+
+func instanceIDs(instances []*cloudinstances.CloudInstance) []string {
+	var ids []string
+	for _, u := range instances {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestPlanDrainsWithoutPodDisruptionBudgets(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	group := groups["node-1"]
+	group.NeedUpdate[0].Status = cloudinstances.CloudInstanceStatusDetached
+	addPod(t, c, "web-1", "web", "node-1b.local")
+
+	plan := c.planDrains(group, group.NeedUpdate)
+	assert.Len(t, plan.batches, 1, "batches")
+	assert.Equal(t, []string{"node-1b", "node-1c", "node-1a"}, instanceIDs(plan.instances()), "drain order")
+}
+
+func TestPlanDrainsAroundPodDisruptionBudgets(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 5, 5)
+	group := groups["node-1"]
+
+	addPodDisruptionBudget(t, c, "web", "web", 1)
+	addPodDisruptionBudget(t, c, "db", "db", 0)
+	addPod(t, c, "web-1", "web", "node-1a.local")
+	addPod(t, c, "web-2", "web", "node-1b.local")
+	addPod(t, c, "db-1", "db", "node-1c.local")
+	addPod(t, c, "other-1", "other", "node-1d.local")
+	// Completed pods are not evicted, so do not count against the budget.
+	err := c.K8sClient.(*fake.Clientset).Tracker().Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-done", Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Spec:       v1.PodSpec{NodeName: "node-1e.local"},
+		Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+	})
+	if err != nil {
+		t.Fatalf("error adding pod: %v", err)
+	}
+
+	plan := c.planDrains(group, group.NeedUpdate)
+	if assert.Len(t, plan.batches, 3, "batches") {
+		assert.Equal(t, []string{"node-1a", "node-1d", "node-1e"}, instanceIDs(plan.batches[0].instances), "first batch")
+		assert.Equal(t, "within the allowed disruptions of default/web (1 of 1 allowed disruptions)", plan.reason(plan.batches[0]))
+		assert.Equal(t, []string{"node-1b"}, instanceIDs(plan.batches[1].instances), "second batch")
+		assert.Equal(t, []string{"node-1c"}, instanceIDs(plan.batches[2].instances), "third batch")
+		assert.Equal(t, "drained alone, as its pods exceed the allowed disruptions of default/db (1 of 0 allowed disruptions)", plan.reason(plan.batches[2]))
+	}
+	assert.Equal(t, []string{"node-1a", "node-1d", "node-1e", "node-1b", "node-1c"}, instanceIDs(plan.instances()), "drain order")
+}
+
+func TestPlanDrainsOrdersDetachedInstancesWithinBatch(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	group := groups["node-1"]
+	group.NeedUpdate[0].Status = cloudinstances.CloudInstanceStatusDetached
+
+	addPodDisruptionBudget(t, c, "web", "web", 1)
+	addPod(t, c, "web-1", "web", "node-1a.local")
+	addPod(t, c, "web-2", "web", "node-1b.local")
+
+	plan := c.planDrains(group, group.NeedUpdate)
+	if assert.Len(t, plan.batches, 2, "batches") {
+		assert.Equal(t, []string{"node-1a", "node-1c"}, instanceIDs(plan.batches[0].instances), "first batch")
+		assert.Equal(t, []string{"node-1b"}, instanceIDs(plan.batches[1].instances), "second batch")
+	}
+	assert.Equal(t, []string{"node-1c", "node-1a", "node-1b"}, instanceIDs(plan.instances()), "drain order")
+}
+
+func TestRollingUpdateDrainsBatchesInTurn(t *testing.T) {
+	c, cloud := getTestSetup()
+	var events bytes.Buffer
+	c.Events = &events
+
+	// Evicting a pod deletes it, so that draining completes.
+	fakeClient := c.K8sClient.(*fake.Clientset)
+	fakeClient.PrependReactor("create", "pods", func(action testingclient.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(testingclient.CreateAction).GetObject().(*policyv1beta1.Eviction)
+		return true, nil, fakeClient.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+	})
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	unavailable := intstr.FromInt(3)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxUnavailable: &unavailable,
+	}
+	addPodDisruptionBudget(t, c, "web", "web", 1)
+	addPod(t, c, "web-1", "web", "node-1a.local")
+	addPod(t, c, "web-2", "web", "node-1b.local")
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+
+	var planned [][]string
+	var order []string
+	decoder := json.NewDecoder(&events)
+	for decoder.More() {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("error decoding event: %v", err)
+		}
+		switch event.Type {
+		case EventDrainBatchPlanned:
+			planned = append(planned, event.Instances)
+		case EventDrainStarted, EventInstanceTerminated:
+			order = append(order, string(event.Type)+" "+event.InstanceID)
+		}
+	}
+
+	assert.Equal(t, [][]string{{"node-1a", "node-1c"}, {"node-1b"}}, planned, "planned batches")
+	// node-1b is only drained once the instances of the first batch have been terminated.
+	indexOf := func(s string) int {
+		for i, o := range order {
+			if o == s {
+				return i
+			}
+		}
+		t.Fatalf("event %q not found in %v", s, order)
+		return -1
+	}
+	secondBatch := indexOf("DrainStarted node-1b")
+	assert.Greater(t, secondBatch, indexOf("InstanceTerminated node-1a"), "events: %v", order)
+	assert.Greater(t, secondBatch, indexOf("InstanceTerminated node-1c"), "events: %v", order)
+}
+
+func TestRollingUpdateMaxSurgeCountsDetachedInstancesOfEveryBatch(t *testing.T) {
+	grid := []struct {
+		maxSurge int
+		detached int
+	}{
+		{maxSurge: 1, detached: 0},
+		{maxSurge: 2, detached: 1},
+		{maxSurge: 3, detached: 2},
+	}
+	for _, g := range grid {
+		c, cloud := getTestSetup()
+
+		// Evicting a pod deletes it, so that draining completes.
+		fakeClient := c.K8sClient.(*fake.Clientset)
+		fakeClient.PrependReactor("create", "pods", func(action testingclient.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			eviction := action.(testingclient.CreateAction).GetObject().(*policyv1beta1.Eviction)
+			return true, nil, fakeClient.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+		})
+
+		countDetach := &countDetach{AutoScalingAPI: cloud.MockAutoscaling}
+		cloud.MockAutoscaling = countDetach
+		cloud.MockEC2 = &ec2IgnoreTags{EC2API: cloud.MockEC2}
+
+		surge := intstr.FromInt(g.maxSurge)
+		c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+			MaxSurge: &surge,
+		}
+
+		groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+		makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+		// node-1a is already detached, and is drained in the first batch, before node-1b.
+		groups["node-1"].NeedUpdate[0].Status = cloudinstances.CloudInstanceStatusDetached
+		addPodDisruptionBudget(t, c, "web", "web", 1)
+		addPod(t, c, "web-1", "web", "node-1a.local")
+		addPod(t, c, "web-2", "web", "node-1b.local")
+
+		err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+		assert.NoError(t, err, "rolling update with maxSurge %d", g.maxSurge)
+		assertGroupInstanceCount(t, cloud, "node-1", 0)
+		assert.Equal(t, g.detached, countDetach.Count, "instances detached with maxSurge %d", g.maxSurge)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/validation"
+)
+
+// EventType identifies the kind of a rolling update Event.
+type EventType string
+
+const (
+	// EventGroupStarted is emitted when the rolling update of an instance group starts.
+	EventGroupStarted EventType = "GroupStarted"
+	// EventInstanceTainted is emitted when the node of an instance is tainted as scheduled for update.
+	EventInstanceTainted EventType = "InstanceTainted"
+	// EventDrainBatchPlanned is emitted for each batch of instances that are drained together, with the reason for the batch.
+	EventDrainBatchPlanned EventType = "DrainBatchPlanned"
+	// EventDrainStarted is emitted when draining the node of an instance starts.
+	EventDrainStarted EventType = "DrainStarted"
+	// EventDrainFinished is emitted when draining the node of an instance finishes.
+	EventDrainFinished EventType = "DrainFinished"
+	// EventInstanceDetached is emitted when an instance has been detached from its instance group.
+	EventInstanceDetached EventType = "InstanceDetached"
+	// EventInstanceTerminated is emitted when an instance has been terminated.
+	EventInstanceTerminated EventType = "InstanceTerminated"
+	// EventValidationAttempt is emitted after each attempt to validate the cluster.
+	EventValidationAttempt EventType = "ValidationAttempt"
+	// EventCanaryFinished is emitted when the canary phase of an instance group finishes.
+	EventCanaryFinished EventType = "CanaryFinished"
+	// EventRolledBack is emitted when an instance group has been rolled back after its canary phase failed.
+	EventRolledBack EventType = "RolledBack"
+	// EventGroupFinished is emitted when the rolling update of an instance group finishes.
+	EventGroupFinished EventType = "GroupFinished"
+)
+
+// Event is a structured progress event of a rolling update.
+type Event struct {
+	// Type is the kind of the event.
+	Type EventType `json:"type"`
+	// Time is when the event occurred.
+	Time metav1.Time `json:"time"`
+	// InstanceGroup is the name of the instance group the event relates to.
+	InstanceGroup string `json:"instanceGroup,omitempty"`
+	// InstanceID is the ID of the cloud instance the event relates to.
+	InstanceID string `json:"instanceID,omitempty"`
+	// NodeName is the name of the kubernetes node the event relates to.
+	NodeName string `json:"nodeName,omitempty"`
+	// Instances are the IDs of the cloud instances of a DrainBatchPlanned event.
+	Instances []string `json:"instances,omitempty"`
+	// Reason explains the decision reported by the event.
+	Reason string `json:"reason,omitempty"`
+	// Duration is how long the step reported by the event took.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Failures are the messages of the validation failures of a ValidationAttempt.
+	Failures []string `json:"failures,omitempty"`
+	// Error is set when the step reported by the event failed.
+	Error string `json:"error,omitempty"`
+}
+
+// emit writes an event to the event stream, if one is configured.
+func (c *RollingUpdateCluster) emit(event *Event) {
+	if c.Events == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = metav1.Now()
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		klog.Warningf("unable to serialize rolling update event: %v", err)
+		return
+	}
+	b = append(b, '\n')
+
+	c.eventsMutex.Lock()
+	defer c.eventsMutex.Unlock()
+	if _, err := c.Events.Write(b); err != nil {
+		klog.Warningf("unable to write rolling update event: %v", err)
+	}
+}
+
+// emitInstance writes an event relating to a cloud instance.
+func (c *RollingUpdateCluster) emitInstance(eventType EventType, u *cloudinstances.CloudInstance, duration time.Duration, err error) {
+	event := &Event{
+		Type:       eventType,
+		InstanceID: u.ID,
+	}
+	if u.CloudInstanceGroup != nil && u.CloudInstanceGroup.InstanceGroup != nil {
+		event.InstanceGroup = u.CloudInstanceGroup.InstanceGroup.Name
+	}
+	if u.Node != nil {
+		event.NodeName = u.Node.Name
+	}
+	if duration != 0 {
+		event.Duration = &metav1.Duration{Duration: duration}
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	c.emit(event)
+}
+
+// emitValidation writes a ValidationAttempt event.
+func (c *RollingUpdateCluster) emitValidation(group *cloudinstances.CloudInstanceGroup, result *validation.ValidationCluster, duration time.Duration, err error) {
+	event := &Event{
+		Type:          EventValidationAttempt,
+		InstanceGroup: group.InstanceGroup.Name,
+		Duration:      &metav1.Duration{Duration: duration},
+	}
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		for _, failure := range result.Failures {
+			event.Failures = append(event.Failures, failure.Message)
+		}
+	}
+	c.emit(event)
+}
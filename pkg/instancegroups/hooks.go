@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// defaultHookTimeout is the maximum time a hook from the RollingUpdate spec may take, if it does not specify one.
+const defaultHookTimeout = 5 * time.Minute
+
+// InstanceHook is called around the replacement of each instance during a rolling update.
+// An error returned from any call stops the rolling update.
+type InstanceHook interface {
+	// BeforeDrain is called before the node of an instance is drained.
+	BeforeDrain(ctx context.Context, instance *cloudinstances.CloudInstance) error
+	// AfterTerminate is called after an instance has been terminated.
+	AfterTerminate(ctx context.Context, instance *cloudinstances.CloudInstance) error
+	// AfterValidate is called after the cluster has validated following the replacement of instances of the group.
+	AfterValidate(ctx context.Context, group *cloudinstances.CloudInstanceGroup) error
+}
+
+// hooksFor returns the hooks to call for an instance group: those set on the RollingUpdateCluster
+// followed by those declared in the group's (or the cluster's default) RollingUpdate spec.
+func (c *RollingUpdateCluster) hooksFor(ig *api.InstanceGroup) []InstanceHook {
+	hooks := append([]InstanceHook{}, c.Hooks...)
+	for _, spec := range resolveSettings(c.Cluster, ig, 0).Hooks {
+		hooks = append(hooks, &specHook{
+			clusterName: c.Cluster.Name,
+			spec:        spec,
+		})
+	}
+	return hooks
+}
+
+func (c *RollingUpdateCluster) runBeforeDrainHooks(u *cloudinstances.CloudInstance) error {
+	for _, hook := range c.hooksFor(u.CloudInstanceGroup.InstanceGroup) {
+		if err := hook.BeforeDrain(c.Ctx, u); err != nil {
+			return fmt.Errorf("before-drain hook failed for instance %q: %v", u.ID, err)
+		}
+	}
+	return nil
+}
+
+func (c *RollingUpdateCluster) runAfterTerminateHooks(u *cloudinstances.CloudInstance) error {
+	for _, hook := range c.hooksFor(u.CloudInstanceGroup.InstanceGroup) {
+		if err := hook.AfterTerminate(c.Ctx, u); err != nil {
+			return fmt.Errorf("after-terminate hook failed for instance %q: %v", u.ID, err)
+		}
+	}
+	return nil
+}
+
+func (c *RollingUpdateCluster) runAfterValidateHooks(group *cloudinstances.CloudInstanceGroup) error {
+	for _, hook := range c.hooksFor(group.InstanceGroup) {
+		if err := hook.AfterValidate(c.Ctx, group); err != nil {
+			return fmt.Errorf("after-validate hook failed for instance group %q: %v", group.InstanceGroup.Name, err)
+		}
+	}
+	return nil
+}
+
+// hookRequest is the payload describing a hook invocation, POSTed to webhooks.
+type hookRequest struct {
+	Phase         api.RollingUpdateHookPhase `json:"phase"`
+	ClusterName   string                     `json:"clusterName"`
+	InstanceGroup string                     `json:"instanceGroup"`
+	InstanceID    string                     `json:"instanceID,omitempty"`
+	NodeName      string                     `json:"nodeName,omitempty"`
+}
+
+// specHook is an InstanceHook declared in the RollingUpdate spec.
+type specHook struct {
+	clusterName string
+	spec        api.RollingUpdateHook
+}
+
+var _ InstanceHook = &specHook{}
+
+func (h *specHook) BeforeDrain(ctx context.Context, instance *cloudinstances.CloudInstance) error {
+	return h.run(ctx, h.instanceRequest(api.RollingUpdateHookBeforeDrain, instance))
+}
+
+func (h *specHook) AfterTerminate(ctx context.Context, instance *cloudinstances.CloudInstance) error {
+	return h.run(ctx, h.instanceRequest(api.RollingUpdateHookAfterTerminate, instance))
+}
+
+func (h *specHook) AfterValidate(ctx context.Context, group *cloudinstances.CloudInstanceGroup) error {
+	return h.run(ctx, &hookRequest{
+		Phase:         api.RollingUpdateHookAfterValidate,
+		ClusterName:   h.clusterName,
+		InstanceGroup: group.InstanceGroup.Name,
+	})
+}
+
+func (h *specHook) instanceRequest(phase api.RollingUpdateHookPhase, instance *cloudinstances.CloudInstance) *hookRequest {
+	request := &hookRequest{
+		Phase:         phase,
+		ClusterName:   h.clusterName,
+		InstanceGroup: instance.CloudInstanceGroup.InstanceGroup.Name,
+		InstanceID:    instance.ID,
+	}
+	if instance.Node != nil {
+		request.NodeName = instance.Node.Name
+	}
+	return request
+}
+
+func (h *specHook) name() string {
+	if h.spec.Name != "" {
+		return h.spec.Name
+	}
+	if h.spec.Webhook != "" {
+		return h.spec.Webhook
+	}
+	if len(h.spec.Exec) != 0 {
+		return h.spec.Exec[0]
+	}
+	return "unnamed"
+}
+
+func (h *specHook) runsIn(phase api.RollingUpdateHookPhase) bool {
+	if len(h.spec.Phases) == 0 {
+		return true
+	}
+	for _, p := range h.spec.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *specHook) run(ctx context.Context, request *hookRequest) error {
+	if !h.runsIn(request.Phase) {
+		return nil
+	}
+
+	timeout := defaultHookTimeout
+	if h.spec.Timeout != nil {
+		timeout = h.spec.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	klog.Infof("Running %s hook %q for instance group %q.", request.Phase, h.name(), request.InstanceGroup)
+
+	var err error
+	switch {
+	case len(h.spec.Exec) != 0:
+		err = h.runExec(ctx, request)
+	case h.spec.Webhook != "":
+		err = h.runWebhook(ctx, request)
+	default:
+		err = fmt.Errorf("neither exec nor webhook is specified")
+	}
+	if err == nil {
+		return nil
+	}
+
+	if h.spec.FailurePolicy == api.RollingUpdateHookFailurePolicyIgnore {
+		klog.Warningf("Ignoring failure of %s hook %q: %v", request.Phase, h.name(), err)
+		return nil
+	}
+	return fmt.Errorf("hook %q: %v", h.name(), err)
+}
+
+func (h *specHook) runExec(ctx context.Context, request *hookRequest) error {
+	cmd := exec.CommandContext(ctx, h.spec.Exec[0], h.spec.Exec[1:]...)
+	cmd.Env = append(os.Environ(),
+		"KOPS_HOOK_PHASE="+string(request.Phase),
+		"KOPS_CLUSTER_NAME="+request.ClusterName,
+		"KOPS_INSTANCE_GROUP="+request.InstanceGroup,
+		"KOPS_INSTANCE_ID="+request.InstanceID,
+		"KOPS_NODE_NAME="+request.NodeName,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out: %s", output)
+		}
+		return fmt.Errorf("%v: %s", err, output)
+	}
+	klog.V(2).Infof("hook output: %s", output)
+	return nil
+}
+
+func (h *specHook) runWebhook(ctx context.Context, request *hookRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error serializing hook request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.spec.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("unexpected response status %q: %s", resp.Status, message)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func TestRollingUpdateWebhookHook(t *testing.T) {
+	var mutex sync.Mutex
+	var requests []hookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request hookRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding hook request: %v", err)
+		}
+		mutex.Lock()
+		requests = append(requests, request)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Webhook: server.URL,
+				Phases:  []kopsapi.RollingUpdateHookPhase{kopsapi.RollingUpdateHookBeforeDrain},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 2, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, []hookRequest{
+		{
+			Phase:         kopsapi.RollingUpdateHookBeforeDrain,
+			ClusterName:   "test.k8s.local",
+			InstanceGroup: "node-1",
+			InstanceID:    "node-1a",
+			NodeName:      "node-1a.local",
+		},
+		{
+			Phase:         kopsapi.RollingUpdateHookBeforeDrain,
+			ClusterName:   "test.k8s.local",
+			InstanceGroup: "node-1",
+			InstanceID:    "node-1b",
+			NodeName:      "node-1b.local",
+		},
+	}, requests)
+}
+
+func TestRollingUpdateWebhookHookFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broker not ready", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	for _, policy := range []kopsapi.RollingUpdateHookFailurePolicy{"", kopsapi.RollingUpdateHookFailurePolicyIgnore} {
+		t.Run(string(policy), func(t *testing.T) {
+			c, cloud := getTestSetup()
+
+			groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+			makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 2, 2)
+			groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+				Hooks: []kopsapi.RollingUpdateHook{
+					{
+						Name:          "broker",
+						Webhook:       server.URL,
+						FailurePolicy: policy,
+					},
+				},
+			}
+
+			err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+			if policy == kopsapi.RollingUpdateHookFailurePolicyIgnore {
+				assert.NoError(t, err, "rolling update")
+				assertGroupInstanceCount(t, cloud, "node-1", 0)
+			} else {
+				assert.Error(t, err, "rolling update")
+				assertGroupInstanceCount(t, cloud, "node-1", 2)
+			}
+		})
+	}
+}
+
+func TestRollingUpdateExecHook(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Exec:   []string{"sh", "-c", `test "$KOPS_INSTANCE_ID" != node-1b`},
+				Phases: []kopsapi.RollingUpdateHookPhase{kopsapi.RollingUpdateHookAfterTerminate},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "after-terminate hook failed for instance \"node-1b\"")
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+}
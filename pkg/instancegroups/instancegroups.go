@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -80,12 +81,34 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		return fmt.Errorf("rollingUpdate is missing a k8s client")
 	}
 
+	if c.checkpoint.isGroupCompleted(group.InstanceGroup.Name) {
+		klog.Infof("Skipping instance group %q, which was updated before the rolling update was interrupted.", group.InstanceGroup.Name)
+		return nil
+	}
+	c.emit(&Event{Type: EventGroupStarted, InstanceGroup: group.InstanceGroup.Name})
+	startTime := time.Now()
+	defer func() {
+		if err == nil {
+			err = c.checkpoint.groupCompleted(group.InstanceGroup.Name)
+		}
+		event := &Event{
+			Type:          EventGroupFinished,
+			InstanceGroup: group.InstanceGroup.Name,
+			Duration:      &metav1.Duration{Duration: time.Since(startTime)},
+		}
+		if err != nil {
+			event.Error = err.Error()
+		}
+		c.emit(event)
+	}()
+
 	noneReady := len(group.Ready) == 0
 	numInstances := len(group.Ready) + len(group.NeedUpdate)
 	update := group.NeedUpdate
 	if c.Force {
 		update = append(update, group.Ready...)
 	}
+	update = c.skipCheckpointedInstances(update)
 
 	if len(update) == 0 {
 		return nil
@@ -97,15 +120,18 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		return err
 	}
 
-	if !c.CloudOnly {
+	settings := resolveSettings(c.Cluster, group.InstanceGroup, numInstances)
+
+	// With a canary phase, only the canaries are tainted until they have passed.
+	canaryPhase := settings.Canary != nil && *settings.DrainAndTerminate && !isBastion
+
+	if !c.CloudOnly && !canaryPhase {
 		err = c.taintAllNeedUpdate(group, update)
 		if err != nil {
 			return err
 		}
 	}
 
-	settings := resolveSettings(c.Cluster, group.InstanceGroup, numInstances)
-
 	runningDrains := 0
 	maxSurge := settings.MaxSurge.IntValue()
 	if maxSurge > len(update) {
@@ -133,6 +159,10 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 			if err != nil {
 				return fmt.Errorf("failed to delete warm pool instance %q: %w", instance.ID, err)
 			}
+			if err := c.checkpoint.instanceTerminated(instance.ID); err != nil {
+				return err
+			}
+			c.emitInstance(EventInstanceTerminated, instance, 0, nil)
 		} else {
 			nonWarmPool = append(nonWarmPool, instance)
 		}
@@ -146,32 +176,70 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		maxConcurrency = 1
 	}
 
-	update = prioritizeUpdate(update)
+	plan := c.planDrains(group, update)
+	update = plan.instances()
+
+	if canaryPhase && len(update) > 0 {
+		numCanaries := canaryCount(settings.Canary, len(update))
+		canaries := update[:numCanaries]
+		update = update[numCanaries:]
+
+		if !c.CloudOnly {
+			if err := c.taintAllNeedUpdate(group, canaries); err != nil {
+				return err
+			}
+		}
+		if err := c.rollingUpdateCanaries(group, canaries, settings.Canary, sleepAfterTerminate); err != nil {
+			return err
+		}
+		if len(update) == 0 {
+			return nil
+		}
+		if maxSurge > len(update) {
+			maxSurge = len(update)
+		}
+		if !c.CloudOnly {
+			if err := c.taintAllNeedUpdate(group, update); err != nil {
+				return err
+			}
+		}
+		noneReady = false
+	}
 
 	if maxSurge > 0 && !c.CloudOnly {
-		skippedNodes := 0
-		for numSurge := 1; numSurge <= maxSurge; numSurge++ {
-			u := update[len(update)-numSurge+skippedNodes]
-			if u.Status != cloudinstances.CloudInstanceStatusDetached {
-				if err := c.detachInstance(u); err != nil {
-					// If detaching a node fails, we simply proceed to the next one instead of
-					// bubbling up the error.
-					skippedNodes++
-					numSurge--
-				}
+		// Instances that are already detached count towards the surge. The drain plan orders them
+		// last within their batch, so they are not necessarily at the end of update.
+		numSurge := 0
+		for _, u := range update {
+			if u.Status == cloudinstances.CloudInstanceStatusDetached {
+				numSurge++
+			}
+		}
+
+		// Detach the instances that are drained last
+		for i := len(update) - 1; i >= 0 && numSurge < maxSurge; i-- {
+			u := update[i]
+			if u.Status == cloudinstances.CloudInstanceStatusDetached {
+				continue
+			}
+			if err := c.detachInstance(u); err != nil {
+				// If detaching a node fails, we simply proceed to the next one instead of
+				// bubbling up the error.
+				continue
+			}
+			numSurge++
+
+			// If noneReady, wait until after one node is detached and its replacement validates
+			// before detaching more in case the current spec does not result in usable nodes.
+			if numSurge == maxSurge || noneReady {
+				// Wait for the minimum interval
+				klog.Infof("waiting for %v after detaching instance", sleepAfterTerminate)
+				time.Sleep(sleepAfterTerminate)
 
-				// If noneReady, wait until after one node is detached and its replacement validates
-				// before detaching more in case the current spec does not result in usable nodes.
-				if numSurge == maxSurge || noneReady {
-					// Wait for the minimum interval
-					klog.Infof("waiting for %v after detaching instance", sleepAfterTerminate)
-					time.Sleep(sleepAfterTerminate)
-
-					if err := c.maybeValidate(" after detaching instance", c.ValidateCount, group); err != nil {
-						return err
-					}
-					noneReady = false
+				if err := c.maybeValidate(" after detaching instance", c.ValidateCount, group); err != nil {
+					return err
 				}
+				noneReady = false
 			}
 		}
 	}
@@ -183,7 +251,20 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 	terminateChan := make(chan error, maxConcurrency)
 
+	batchOf := plan.batchOf()
+	currentBatch := 0
+
 	for uIdx, u := range update {
+		// Start draining the next batch only once the previous one has finished, so that
+		// their PodDisruptionBudgets have recovered.
+		if batch := batchOf[u.ID]; batch != currentBatch {
+			if err = c.waitForDrains(runningDrains, terminateChan, group); err != nil {
+				return err
+			}
+			runningDrains = 0
+			currentBatch = batch
+		}
+
 		go func(m *cloudinstances.CloudInstance) {
 			terminateChan <- c.drainTerminateAndWait(m, sleepAfterTerminate)
 		}(u)
@@ -206,6 +287,11 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 
+		err = c.runAfterValidateHooks(group)
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
 		if c.Interactive {
 			nodeName := ""
 			if u.Node != nil {
@@ -238,40 +324,44 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		}
 	}
 
-	if runningDrains > 0 {
-		for runningDrains > 0 {
-			err = <-terminateChan
-			runningDrains--
-			if err != nil {
-				return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
-			}
-		}
+	return c.waitForDrains(runningDrains, terminateChan, group)
+}
 
-		err = c.maybeValidate(" after terminating instance", c.ValidateCount, group)
+// waitForDrains waits for the running drains to finish and, if there were any, validates the cluster.
+func (c *RollingUpdateCluster) waitForDrains(runningDrains int, terminateChan chan error, group *cloudinstances.CloudInstanceGroup) error {
+	if runningDrains == 0 {
+		return nil
+	}
+
+	for runningDrains > 0 {
+		err := <-terminateChan
+		runningDrains--
 		if err != nil {
-			return err
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 	}
 
-	return nil
+	if err := c.maybeValidate(" after terminating instance", c.ValidateCount, group); err != nil {
+		return err
+	}
+
+	return c.runAfterValidateHooks(group)
 }
 
-func prioritizeUpdate(update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
-	// The priorities are, in order:
-	//   attached before detached
-	//   TODO unhealthy before healthy
-	//   NeedUpdate before Ready (preserve original order)
-	result := make([]*cloudinstances.CloudInstance, 0, len(update))
-	var detached []*cloudinstances.CloudInstance
+// skipCheckpointedInstances drops the instances that were terminated before the rolling update was interrupted
+// and marks the ones that were detached, so that they are not detached again.
+func (c *RollingUpdateCluster) skipCheckpointedInstances(update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
+	var result []*cloudinstances.CloudInstance
 	for _, u := range update {
-		if u.Status == cloudinstances.CloudInstanceStatusDetached {
-			detached = append(detached, u)
-		} else {
-			result = append(result, u)
+		if c.checkpoint.isTerminated(u.ID) {
+			klog.Infof("Skipping instance %q, which was terminated before the rolling update was interrupted.", u.ID)
+			continue
+		}
+		if c.checkpoint.isDetached(u.ID) {
+			u.Status = cloudinstances.CloudInstanceStatusDetached
 		}
+		result = append(result, u)
 	}
-
-	result = append(result, detached...)
 	return result
 }
 
@@ -284,7 +374,7 @@ func waitForPendingBeforeReturningError(runningDrains int, terminateChan chan er
 }
 
 func (c *RollingUpdateCluster) taintAllNeedUpdate(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance) error {
-	var toTaint []*corev1.Node
+	var toTaint []*cloudinstances.CloudInstance
 	for _, u := range update {
 		if u.Node != nil && !u.Node.Spec.Unschedulable {
 			foundTaint := false
@@ -294,7 +384,7 @@ func (c *RollingUpdateCluster) taintAllNeedUpdate(group *cloudinstances.CloudIns
 				}
 			}
 			if !foundTaint {
-				toTaint = append(toTaint, u.Node)
+				toTaint = append(toTaint, u)
 			}
 		}
 	}
@@ -304,13 +394,16 @@ func (c *RollingUpdateCluster) taintAllNeedUpdate(group *cloudinstances.CloudIns
 			noun = "node"
 		}
 		klog.Infof("Tainting %d %s in %q instancegroup.", len(toTaint), noun, group.InstanceGroup.Name)
-		for _, n := range toTaint {
+		for _, u := range toTaint {
+			n := u.Node
 			if err := c.patchTaint(n); err != nil {
 				if c.FailOnDrainError {
 					return fmt.Errorf("failed to taint node %q: %v", n, err)
 				}
 				klog.Infof("Ignoring error tainting node %q: %v", n, err)
+				continue
 			}
+			c.emitInstance(EventInstanceTainted, u, 0, nil)
 		}
 	}
 	return nil
@@ -378,6 +471,7 @@ func (c *RollingUpdateCluster) patchExcludeFromLB(node *corev1.Node) error {
 
 func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
 	instanceID := u.ID
+	startTime := time.Now()
 
 	nodeName := ""
 	if u.Node != nil {
@@ -386,6 +480,10 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 
 	isBastion := u.CloudInstanceGroup.InstanceGroup.IsBastion()
 
+	if err := c.runBeforeDrainHooks(u); err != nil {
+		return err
+	}
+
 	if isBastion {
 		// We don't want to validate for bastions - they aren't part of the cluster
 	} else if c.CloudOnly {
@@ -396,8 +494,12 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 
 		if u.Node != nil {
 			klog.Infof("Draining the node: %q.", nodeName)
+			c.emitInstance(EventDrainStarted, u, 0, nil)
 
-			if err := c.drainNode(u); err != nil {
+			drainStart := time.Now()
+			err := c.drainNode(u)
+			c.emitInstance(EventDrainFinished, u, time.Since(drainStart), err)
+			if err != nil {
 				if c.FailOnDrainError {
 					return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
 				}
@@ -426,6 +528,15 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 		return err
 	}
 
+	if err := c.checkpoint.instanceTerminated(instanceID); err != nil {
+		return err
+	}
+	c.emitInstance(EventInstanceTerminated, u, time.Since(startTime), nil)
+
+	if err := c.runAfterTerminateHooks(u); err != nil {
+		return err
+	}
+
 	if err := c.reconcileInstanceGroup(); err != nil {
 		klog.Errorf("error reconciling instance group %q: %v", u.CloudInstanceGroup.HumanName, err)
 		return err
@@ -496,7 +607,10 @@ func (c *RollingUpdateCluster) validateClusterWithTimeout(validateCount int, gro
 
 	for {
 		// Note that we validate at least once before checking the timeout, in case the cluster is healthy with a short timeout
+		validateStart := time.Now()
 		result, err := c.ClusterValidator.Validate()
+		c.emitValidation(group, result, time.Since(validateStart), err)
+		c.checkpoint.validated(group.InstanceGroup.Name, result, err)
 		if err == nil && !hasFailureRelevantToGroup(result.Failures, group) {
 			successCount++
 			if successCount >= validateCount {
@@ -576,8 +690,9 @@ func (c *RollingUpdateCluster) detachInstance(u *cloudinstances.CloudInstance) e
 		}
 		return fmt.Errorf("error detaching instance %q: %v", id, err)
 	}
+	c.emitInstance(EventInstanceDetached, u, 0, nil)
 
-	return nil
+	return c.checkpoint.instanceDetached(id)
 }
 
 // deleteInstance deletes an Cloud Instance.
@@ -617,13 +732,19 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		return fmt.Errorf("node name not set")
 	}
 
+	// Keep the drain output out of the event stream, which is typically written to stdout
+	var drainOut io.Writer = os.Stdout
+	if c.Events != nil {
+		drainOut = os.Stderr
+	}
+
 	helper := &drain.Helper{
 		Ctx:                 c.Ctx,
 		Client:              c.K8sClient,
 		Force:               true,
 		GracePeriodSeconds:  -1,
 		IgnoreAllDaemonSets: true,
-		Out:                 os.Stdout,
+		Out:                 drainOut,
 		ErrOut:              os.Stderr,
 
 		// We want to proceed even when pods are using emptyDir volumes
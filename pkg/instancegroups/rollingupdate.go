@@ -19,6 +19,7 @@ package instancegroups
 import (
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 // RollingUpdateCluster is a struct containing cluster information for a rolling update.
@@ -77,6 +79,30 @@ type RollingUpdateCluster struct {
 
 	// ValidateCount is the amount of time that a cluster needs to be validated after single node update
 	ValidateCount int
+
+	// CheckpointPath is where the progress of the rolling update is persisted; if nil, progress is not persisted
+	CheckpointPath vfs.Path
+
+	// Resume continues an interrupted rolling update from the progress recorded at CheckpointPath
+	Resume bool
+
+	// Selection is the selection of instance groups being updated, which a resumed rolling update must match
+	Selection RollingUpdateSelection
+
+	// Hooks are called around the replacement of each instance, before the hooks declared in the RollingUpdate spec
+	Hooks []InstanceHook
+
+	// Events, if set, receives a JSON-encoded Event per line for each step of the rolling update
+	Events io.Writer
+
+	// RecordStatus records the outcome of the rolling update of each instance group through Clientset
+	RecordStatus bool
+
+	// checkpoint tracks the progress of the current rolling update
+	checkpoint *checkpointTracker
+
+	// eventsMutex serializes writes to Events
+	eventsMutex sync.Mutex
 }
 
 // AdjustNeedUpdate adjusts the set of instances that need updating, using factors outside those known by the cloud implementation
@@ -94,8 +120,23 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		return nil
 	}
 
-	var resultsMutex sync.Mutex
 	results := make(map[string]error)
+	err := c.rollingUpdate(groups, results)
+	if c.RecordStatus {
+		if recordErr := c.recordStatus(groups, results, err); recordErr != nil {
+			klog.Warningf("unable to record the rolling update status: %v", recordErr)
+		}
+	}
+	return err
+}
+
+// rollingUpdate performs the rolling update, storing the outcome of each instance group it updated in results.
+func (c *RollingUpdateCluster) rollingUpdate(groups map[string]*cloudinstances.CloudInstanceGroup, results map[string]error) error {
+	if err := c.loadCheckpoint(); err != nil {
+		return err
+	}
+
+	var resultsMutex sync.Mutex
 
 	masterGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
 	apiServerGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
@@ -156,6 +197,8 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		for _, k := range sortGroups(masterGroups) {
 			err := c.rollingUpdateInstanceGroup(masterGroups[k], c.MasterInterval)
 
+			results[k] = err
+
 			// Do not continue update if master(s) failed, cluster is potentially in an unhealthy state
 			if err != nil {
 				return fmt.Errorf("master not healthy after update, stopping rolling-update: %q", err)
@@ -205,6 +248,10 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		}
 	}
 
+	if err := c.checkpoint.remove(); err != nil {
+		return err
+	}
+
 	klog.Infof("Rolling update completed for cluster %q!", c.ClusterName)
 	return nil
 }
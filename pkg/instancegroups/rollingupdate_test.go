@@ -17,7 +17,9 @@ limitations under the License.
 package instancegroups
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"strings"
 	"sync"
@@ -42,6 +44,7 @@ import (
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 const (
@@ -1503,6 +1506,223 @@ func TestRollingUpdateMaxSurgeAllNeedUpdateMaxAlreadyDetached(t *testing.T) {
 	concurrentTest.AssertComplete()
 }
 
+func TestRollingUpdateResumeAfterValidationFailure(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	c.CheckpointPath = vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update-checkpoint")
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	c.ClusterValidator = &instanceGroupNodeSpecificErrorClusterValidator{
+		InstanceGroup: groups["node-2"].InstanceGroup,
+	}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+	assertGroupInstanceCount(t, cloud, "master-1", 0)
+	assertGroupInstanceCount(t, cloud, "bastion-1", 0)
+
+	checkpoint, err := ReadCheckpoint(c.CheckpointPath)
+	if assert.NoError(t, err, "reading checkpoint") && assert.NotNil(t, checkpoint, "checkpoint") {
+		assert.ElementsMatch(t, []string{"bastion-1", "master-1", "node-1"}, checkpoint.CompletedInstanceGroups)
+		assert.Len(t, checkpoint.TerminatedInstances, 6, "terminated instances")
+		if assert.NotNil(t, checkpoint.LastValidation, "last validation") {
+			assert.False(t, checkpoint.LastValidation.Succeeded, "last validation succeeded")
+			assert.Equal(t, "node-2", checkpoint.LastValidation.InstanceGroup)
+		}
+	}
+
+	c.ClusterValidator = &successfulClusterValidator{}
+	c.Resume = true
+	c.K8sClient.(*fake.Clientset).ClearActions()
+
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "resumed rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-2", 0)
+	for _, action := range c.K8sClient.(*fake.Clientset).Actions() {
+		if a, ok := action.(testingclient.DeleteAction); ok {
+			assert.True(t, strings.HasPrefix(a.GetName(), "node-2"), "only node-2 nodes are deleted on resume, not %s", a.GetName())
+		}
+	}
+
+	checkpoint, err = ReadCheckpoint(c.CheckpointPath)
+	assert.NoError(t, err, "reading checkpoint")
+	assert.Nil(t, checkpoint, "checkpoint removed after completion")
+}
+
+func TestRollingUpdateResumeSkipsTerminatedInstances(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	c.CheckpointPath = vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update-checkpoint")
+	c.Resume = true
+
+	err := c.CheckpointPath.WriteFile(strings.NewReader(`{"terminatedInstances":["node-1a"]}`), nil)
+	assert.NoError(t, err, "writing checkpoint")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+	for _, action := range c.K8sClient.(*fake.Clientset).Actions() {
+		if a, ok := action.(testingclient.DeleteAction); ok {
+			assert.NotEqual(t, "node-1a.local", a.GetName(), "terminated instance is not drained again")
+		}
+	}
+}
+
+func TestRollingUpdateResumeRequiresSameSelection(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	c.CheckpointPath = vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update-checkpoint")
+	c.Resume = true
+	c.Selection = RollingUpdateSelection{InstanceGroups: []string{"node-1", "node-2"}}
+
+	err := c.CheckpointPath.WriteFile(strings.NewReader(`{"selection":{"instanceGroups":["node-1"]},"completedInstanceGroups":["node-1"]}`), nil)
+	assert.NoError(t, err, "writing checkpoint")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "resuming with a different selection")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+
+	c.Selection = RollingUpdateSelection{InstanceGroups: []string{"node-1"}}
+	delete(groups, "node-2")
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "resuming with the same selection")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+func TestRollingUpdateWithoutResumeDiscardsCheckpoint(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	c.CheckpointPath = vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update-checkpoint")
+
+	err := c.CheckpointPath.WriteFile(strings.NewReader(`{"completedInstanceGroups":["node-1"]}`), nil)
+	assert.NoError(t, err, "writing checkpoint")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateEmitsEvents(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	var events bytes.Buffer
+	c.Events = &events
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	counts := map[EventType]int{}
+	var types []EventType
+	decoder := json.NewDecoder(&events)
+	for decoder.More() {
+		var event Event
+		if !assert.NoError(t, decoder.Decode(&event), "decoding event") {
+			return
+		}
+		assert.Equal(t, "node-1", event.InstanceGroup, "event instance group")
+		switch event.Type {
+		case EventInstanceTainted, EventDrainStarted, EventDrainFinished, EventInstanceTerminated:
+			assert.NotEmpty(t, event.InstanceID, "instance ID of %s event", event.Type)
+			assert.Equal(t, event.InstanceID+".local", event.NodeName, "node name of %s event", event.Type)
+		}
+		counts[event.Type]++
+		types = append(types, event.Type)
+	}
+
+	if assert.NotEmpty(t, types, "events") {
+		assert.Equal(t, EventGroupStarted, types[0], "first event")
+		assert.Equal(t, EventGroupFinished, types[len(types)-1], "last event")
+	}
+	assert.Equal(t, 3, counts[EventInstanceTainted], "InstanceTainted events")
+	assert.Equal(t, 3, counts[EventDrainStarted], "DrainStarted events")
+	assert.Equal(t, 3, counts[EventDrainFinished], "DrainFinished events")
+	assert.Equal(t, 3, counts[EventInstanceTerminated], "InstanceTerminated events")
+	assert.Equal(t, 7, counts[EventValidationAttempt], "ValidationAttempt events")
+}
+
+type recordingHook struct {
+	mutex          sync.Mutex
+	beforeDrain    []string
+	afterTerminate []string
+	afterValidate  int
+	failOn         string
+}
+
+func (h *recordingHook) BeforeDrain(ctx context.Context, instance *cloudinstances.CloudInstance) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.beforeDrain = append(h.beforeDrain, instance.ID)
+	if instance.ID == h.failOn {
+		return errors.New("hook failure")
+	}
+	return nil
+}
+
+func (h *recordingHook) AfterTerminate(ctx context.Context, instance *cloudinstances.CloudInstance) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.afterTerminate = append(h.afterTerminate, instance.ID)
+	return nil
+}
+
+func (h *recordingHook) AfterValidate(ctx context.Context, group *cloudinstances.CloudInstanceGroup) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.afterValidate++
+	return nil
+}
+
+func TestRollingUpdateCallsHooks(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	hook := &recordingHook{}
+	c.Hooks = []InstanceHook{hook}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assert.Equal(t, []string{"node-1a", "node-1b", "node-1c"}, hook.beforeDrain, "BeforeDrain calls")
+	assert.Equal(t, []string{"node-1a", "node-1b", "node-1c"}, hook.afterTerminate, "AfterTerminate calls")
+	assert.Equal(t, 3, hook.afterValidate, "AfterValidate calls")
+}
+
+func TestRollingUpdateHookFailureStopsUpdate(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	hook := &recordingHook{failOn: "node-1b"}
+	c.Hooks = []InstanceHook{hook}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assert.Equal(t, []string{"node-1a", "node-1b"}, hook.beforeDrain, "BeforeDrain calls")
+	assert.Equal(t, []string{"node-1a"}, hook.afterTerminate, "AfterTerminate calls")
+}
+
 func assertCordon(t *testing.T, action testingclient.PatchAction) {
 	assert.Equal(t, "nodes", action.GetResource().Resource)
 	assert.Equal(t, cordonPatch, string(action.GetPatch()))
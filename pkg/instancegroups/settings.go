@@ -39,6 +39,12 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 		if rollingUpdate.MaxSurge == nil {
 			rollingUpdate.MaxSurge = def.MaxSurge
 		}
+		if rollingUpdate.Hooks == nil {
+			rollingUpdate.Hooks = def.Hooks
+		}
+		if rollingUpdate.Canary == nil {
+			rollingUpdate.Canary = def.Canary
+		}
 	}
 
 	if rollingUpdate.DrainAndTerminate == nil {
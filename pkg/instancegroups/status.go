@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// recordStatus records the outcome of the rolling update of the cluster and of each of the groups.
+// results holds the outcome of the groups the rolling update got to; updateErr is the outcome of the whole rolling update.
+func (c *RollingUpdateCluster) recordStatus(groups map[string]*cloudinstances.CloudInstanceGroup, results map[string]error, updateErr error) error {
+	status, err := c.Clientset.GetRecordedStatus(c.Ctx, c.Cluster)
+	if err != nil {
+		return err
+	}
+	if status.InstanceGroups == nil {
+		status.InstanceGroups = make(map[string]*api.InstanceGroupStatus)
+	}
+
+	for k, group := range groups {
+		name := group.InstanceGroup.ObjectMeta.Name
+		groupStatus := &api.InstanceGroupStatus{
+			Instances:  int32(len(group.Ready) + len(group.NeedUpdate)),
+			NeedUpdate: int32(len(group.NeedUpdate)),
+		}
+		if existing := status.InstanceGroups[name]; existing != nil {
+			groupStatus.Conditions = existing.Conditions
+		}
+
+		condition := metav1.Condition{
+			Type: api.InstanceGroupConditionInstancesUpToDate,
+		}
+		groupErr, updated := results[k]
+		switch {
+		case updated && groupErr == nil:
+			groupStatus.NeedUpdate = 0
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = api.RollingUpdateReasonSucceeded
+			condition.Message = "The rolling update of the instance group completed"
+		case updated:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = api.RollingUpdateReasonFailed
+			condition.Message = groupErr.Error()
+		default:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = api.RollingUpdateReasonStopped
+			condition.Message = "The rolling update stopped before updating the instance group"
+		}
+		meta.SetStatusCondition(&groupStatus.Conditions, condition)
+
+		status.InstanceGroups[name] = groupStatus
+	}
+
+	condition := metav1.Condition{
+		Type:    api.ClusterConditionRollingUpdateSucceeded,
+		Status:  metav1.ConditionTrue,
+		Reason:  api.RollingUpdateReasonSucceeded,
+		Message: "The rolling update completed",
+	}
+	if updateErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = api.RollingUpdateReasonFailed
+		condition.Message = updateErr.Error()
+	}
+	meta.SetStatusCondition(&status.Cluster.Conditions, condition)
+
+	return c.Clientset.UpdateRecordedStatus(c.Ctx, c.Cluster, status)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func getTestSetupRecordingStatus(t *testing.T) (*RollingUpdateCluster, *awsup.MockAWSCloud) {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://state")
+	require.NoError(t, err)
+
+	c, cloud := getTestSetup()
+	c.Clientset = vfsclientset.NewVFSClientset(basePath)
+	c.RecordStatus = true
+	return c, cloud
+}
+
+func TestRollingUpdateRecordsStatus(t *testing.T) {
+	c, cloud := getTestSetupRecordingStatus(t)
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	require.NoError(t, err, "rolling update")
+
+	status, err := c.Clientset.GetRecordedStatus(c.Ctx, c.Cluster)
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(status.Cluster.Conditions, kopsapi.ClusterConditionRollingUpdateSucceeded))
+
+	require.Len(t, status.InstanceGroups, 4)
+	node1 := status.InstanceGroups["node-1"]
+	require.NotNil(t, node1)
+	assert.Equal(t, int32(3), node1.Instances)
+	assert.Equal(t, int32(0), node1.NeedUpdate)
+	assert.True(t, meta.IsStatusConditionTrue(node1.Conditions, kopsapi.InstanceGroupConditionInstancesUpToDate))
+}
+
+func TestRollingUpdateRecordsFailedStatus(t *testing.T) {
+	c, cloud := getTestSetupRecordingStatus(t)
+	c.ClusterValidator = &failingClusterValidator{}
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	updateErr := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	require.Error(t, updateErr, "rolling update")
+
+	status, err := c.Clientset.GetRecordedStatus(c.Ctx, c.Cluster)
+	require.NoError(t, err)
+	succeeded := meta.FindStatusCondition(status.Cluster.Conditions, kopsapi.ClusterConditionRollingUpdateSucceeded)
+	require.NotNil(t, succeeded)
+	assert.Equal(t, v1meta.ConditionFalse, succeeded.Status)
+	assert.Equal(t, updateErr.Error(), succeeded.Message)
+
+	bastion := meta.FindStatusCondition(status.InstanceGroups["bastion-1"].Conditions, kopsapi.InstanceGroupConditionInstancesUpToDate)
+	require.NotNil(t, bastion)
+	assert.Equal(t, kopsapi.RollingUpdateReasonFailed, bastion.Reason)
+
+	node1 := status.InstanceGroups["node-1"]
+	require.NotNil(t, node1)
+	assert.Equal(t, int32(3), node1.NeedUpdate, "instances of groups the rolling update did not get to still need updating")
+	upToDate := meta.FindStatusCondition(node1.Conditions, kopsapi.InstanceGroupConditionInstancesUpToDate)
+	require.NotNil(t, upToDate)
+	assert.Equal(t, kopsapi.RollingUpdateReasonStopped, upToDate.Reason)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// healthyGroupsCloud reports a fixed set of healthy cloud groups to the cluster validator.
+type healthyGroupsCloud struct {
+	*awsup.MockAWSCloud
+	groups map[string]*cloudinstances.CloudInstanceGroup
+}
+
+func (c *healthyGroupsCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return c.groups, nil
+}
+
+// getTestSetupValidatingCertificates returns a rolling update validated by the cluster validator,
+// for a cluster whose keystore holds a certificate that is close to expiry.
+func getTestSetupValidatingCertificates(t *testing.T) (*RollingUpdateCluster, *awsup.MockAWSCloud) {
+	c, cloud := getTestSetup()
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	require.NoError(t, err)
+	c.Cluster.Spec.ConfigBase = basePath.Join(c.Cluster.Name).Path()
+	clientset := vfsclientset.NewVFSClientset(basePath)
+
+	keyStore, err := clientset.KeyStore(c.Cluster)
+	require.NoError(t, err)
+	cert, key, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:     "ca",
+		Subject:  pkix.Name{CommonName: "kubernetes-ca"},
+		Validity: 10 * 24 * time.Hour,
+	}, nil)
+	require.NoError(t, err)
+	keyset, err := fi.NewKeyset(cert, key)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.StoreKeyset("kubernetes-ca", keyset))
+
+	ig := kopsapi.InstanceGroup{
+		ObjectMeta: v1meta.ObjectMeta{Name: "node-1"},
+		Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleNode},
+	}
+	healthy := &healthyGroupsCloud{
+		MockAWSCloud: cloud,
+		groups: map[string]*cloudinstances.CloudInstanceGroup{
+			"node-1": {
+				InstanceGroup: &ig,
+				MinSize:       1,
+				Ready: []*cloudinstances.CloudInstance{
+					{
+						ID: "node-1-healthy",
+						Node: &v1.Node{
+							ObjectMeta: v1meta.ObjectMeta{Name: "node-1-healthy.local"},
+							Status: v1.NodeStatus{
+								Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c.ClusterValidator, err = validation.NewClusterValidator(c.Cluster, healthy, &kopsapi.InstanceGroupList{Items: []kopsapi.InstanceGroup{ig}}, "", c.K8sClient, clientset)
+	require.NoError(t, err)
+	return c, cloud
+}
+
+func TestRollingUpdateNotBlockedByValidatorsNotEnabled(t *testing.T) {
+	c, cloud := getTestSetupValidatingCertificates(t)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateBlockedByEnabledValidators(t *testing.T) {
+	c, cloud := getTestSetupValidatingCertificates(t)
+	c.Cluster.Spec.Validation = &kopsapi.ClusterValidationSpec{
+		Validators: []string{"certificates"},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os/user"
 	"sort"
+	"strings"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -34,11 +35,45 @@ import (
 
 const DefaultKubecfgAdminLifetime = 18 * time.Hour
 
-func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.SecretStore, cloud fi.Cloud, admin time.Duration, configUser string, internal bool, kopsStateStore string, useKopsAuthenticationPlugin bool) (*KubeconfigBuilder, error) {
+// DefaultKubecfgUserLifetime is the default lifetime of the user certificates issued by kops export kubecfg.
+const DefaultKubecfgUserLifetime = 8 * time.Hour
+
+// DefaultUserCertificateMaxValidity is the maximum validity of user certificates if the cluster spec does not set one.
+const DefaultUserCertificateMaxValidity = 24 * time.Hour
+
+// UserCertificate describes a client certificate issued to a user by the users CA.
+type UserCertificate struct {
+	// Identity is the name of the user, the common name of the certificate.
+	Identity string
+	// Groups are the groups of the user, the organizations of the certificate.
+	Groups []string
+	// Lifetime is the validity of the certificate.
+	Lifetime time.Duration
+}
+
+// BuildKubecfgOptions holds the options of BuildKubecfg.
+type BuildKubecfgOptions struct {
+	// Admin is the lifetime of an admin client certificate to add; none is added if zero.
+	Admin time.Duration
+	// User is the name of the kubeconfig user; the cluster name if empty.
+	User string
+	// Internal uses the internal DNS name of the API server.
+	Internal bool
+	// KopsStateStore is the state store passed to the kOps authentication plugin.
+	KopsStateStore string
+	// UseKopsAuthenticationPlugin authenticates with the kOps authentication plugin.
+	UseKopsAuthenticationPlugin bool
+	// UseOIDCAuthentication authenticates with the OpenID Connect provider of the cluster.
+	UseOIDCAuthentication bool
+	// UserCertificate is a user client certificate to add, issued by the users CA.
+	UserCertificate *UserCertificate
+}
+
+func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.SecretStore, cloud fi.Cloud, options BuildKubecfgOptions) (*KubeconfigBuilder, error) {
 	clusterName := cluster.ObjectMeta.Name
 
 	var master string
-	if internal {
+	if options.Internal {
 		master = cluster.Spec.MasterInternalName
 		if master == "" {
 			master = "api.internal." + clusterName
@@ -100,7 +135,7 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 	b := NewKubeconfigBuilder()
 
 	// Use the secondary load balancer port if a certificate is on the primary listener
-	if admin != 0 && cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil && cluster.Spec.API.LoadBalancer.SSLCertificate != "" && cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork {
+	if (options.Admin != 0 || options.UserCertificate != nil) && cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil && cluster.Spec.API.LoadBalancer.SSLCertificate != "" && cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork {
 		server = server + ":8443"
 	}
 
@@ -109,7 +144,7 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 
 	// add the CA Cert to the kubeconfig only if we didn't specify a certificate for the LB
 	//  or if we're using admin credentials and the secondary port
-	if cluster.Spec.API == nil || cluster.Spec.API.LoadBalancer == nil || cluster.Spec.API.LoadBalancer.SSLCertificate == "" || cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork || internal {
+	if cluster.Spec.API == nil || cluster.Spec.API.LoadBalancer == nil || cluster.Spec.API.LoadBalancer.SSLCertificate == "" || cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork || options.Internal {
 		keySet, err := keyStore.FindKeyset(fi.CertificateIDCA)
 		if err != nil {
 			return nil, fmt.Errorf("error fetching CA keypair: %v", err)
@@ -124,7 +159,7 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 		}
 	}
 
-	if admin != 0 {
+	if options.Admin != 0 {
 		cn := "kubecfg"
 		user, err := user.Current()
 		if err != nil || user == nil {
@@ -140,30 +175,59 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 				CommonName:   cn,
 				Organization: []string{rbac.SystemPrivilegedGroup},
 			},
-			Validity: admin,
+			Validity: options.Admin,
 		}
-		cert, privateKey, _, err := pki.IssueCert(&req, keyStore)
-		if err != nil {
+		if err := b.issueClientCert(&req, keyStore); err != nil {
 			return nil, err
 		}
-		b.ClientCert, err = cert.AsBytes()
-		if err != nil {
+	}
+
+	if options.UserCertificate != nil {
+		if options.Admin != 0 {
+			return nil, fmt.Errorf("cannot issue both admin and user certificates")
+		}
+		if options.UseKopsAuthenticationPlugin || options.UseOIDCAuthentication {
+			return nil, fmt.Errorf("cannot use a user certificate together with an authentication plugin")
+		}
+		if cluster.Spec.Authentication == nil || cluster.Spec.Authentication.Users == nil {
+			return nil, fmt.Errorf("cluster %q does not have user certificates configured", clusterName)
+		}
+		if err := validateUserCertificate(options.UserCertificate, cluster.Spec.Authentication.Users); err != nil {
 			return nil, err
 		}
-		b.ClientKey, err = privateKey.AsBytes()
-		if err != nil {
+
+		req := pki.IssueCertRequest{
+			Signer: fi.CertificateIDUsersCA,
+			Type:   "client",
+			Subject: pkix.Name{
+				CommonName:   options.UserCertificate.Identity,
+				Organization: options.UserCertificate.Groups,
+			},
+			Validity: options.UserCertificate.Lifetime,
+		}
+		if err := b.issueClientCert(&req, keyStore); err != nil {
 			return nil, err
 		}
 	}
 
-	if useKopsAuthenticationPlugin {
+	if options.UseKopsAuthenticationPlugin {
 		b.AuthenticationExec = []string{
 			"kops",
 			"helpers",
 			"kubectl-auth",
 			"--cluster=" + clusterName,
-			"--state=" + kopsStateStore,
+			"--state=" + options.KopsStateStore,
+		}
+	}
+
+	if options.UseOIDCAuthentication {
+		if options.UseKopsAuthenticationPlugin {
+			return nil, fmt.Errorf("cannot use both the kOps authentication plugin and OpenID Connect authentication")
 		}
+		if cluster.Spec.Authentication == nil || cluster.Spec.Authentication.OIDC == nil {
+			return nil, fmt.Errorf("cluster %q does not have OpenID Connect authentication configured", clusterName)
+		}
+		b.AuthenticationExec = buildOIDCAuthenticationExec(cluster.Spec.Authentication.OIDC)
 	}
 
 	b.Server = server
@@ -196,11 +260,79 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 		}
 	}
 
-	if configUser == "" {
+	if options.User == "" {
 		b.User = cluster.ObjectMeta.Name
 	} else {
-		b.User = configUser
+		b.User = options.User
 	}
 
 	return b, nil
 }
+
+// issueClientCert issues the client certificate and key of the kubeconfig.
+func (b *KubeconfigBuilder) issueClientCert(req *pki.IssueCertRequest, keyStore fi.Keystore) error {
+	cert, privateKey, _, err := pki.IssueCert(req, keyStore)
+	if err != nil {
+		return err
+	}
+	b.ClientCert, err = cert.AsBytes()
+	if err != nil {
+		return err
+	}
+	b.ClientKey, err = privateKey.AsBytes()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateUserCertificate checks that a user certificate may be issued by the users CA.
+// Groups with the system: prefix are refused, so that users CA certificates never grant system privileges.
+func validateUserCertificate(userCertificate *UserCertificate, users *kops.UserAuthenticationSpec) error {
+	if userCertificate.Identity == "" {
+		return fmt.Errorf("identity is required for a user certificate")
+	}
+	if strings.HasPrefix(userCertificate.Identity, "system:") {
+		return fmt.Errorf("identity %q is reserved", userCertificate.Identity)
+	}
+	for _, group := range userCertificate.Groups {
+		if group == "" || strings.HasPrefix(group, "system:") {
+			return fmt.Errorf("group %q cannot be used in a user certificate", group)
+		}
+	}
+
+	maxValidity := DefaultUserCertificateMaxValidity
+	if users.MaxValidity != nil {
+		maxValidity = users.MaxValidity.Duration
+	}
+	if userCertificate.Lifetime <= 0 {
+		return fmt.Errorf("lifetime of the user certificate must be positive")
+	}
+	if userCertificate.Lifetime > maxValidity {
+		return fmt.Errorf("lifetime %v of the user certificate exceeds the maximum validity %v", userCertificate.Lifetime, maxValidity)
+	}
+	return nil
+}
+
+// buildOIDCAuthenticationExec returns the command kubectl runs to obtain tokens from the OpenID Connect identity provider.
+// It uses the kubelogin plugin (kubectl oidc-login).
+func buildOIDCAuthenticationExec(oidc *kops.OIDCAuthenticationSpec) []string {
+	exec := []string{
+		"kubectl",
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + oidc.IssuerURL,
+		"--oidc-client-id=" + oidc.ClientID,
+	}
+
+	scopes := oidc.ExtraScopes
+	if len(scopes) == 0 && oidc.GroupsClaim != "" {
+		// Identity providers commonly only include the groups claim if the scope of the same name is requested
+		scopes = []string{oidc.GroupsClaim}
+	}
+	for _, scope := range scopes {
+		exec = append(exec, "--oidc-extra-scope="+scope)
+	}
+
+	return exec
+}
@@ -154,6 +154,8 @@ func TestBuildKubecfg(t *testing.T) {
 		user                        string
 		internal                    bool
 		useKopsAuthenticationPlugin bool
+		useOIDCAuthentication       bool
+		userCertificate             *UserCertificate
 	}
 
 	publicCluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
@@ -162,6 +164,18 @@ func TestBuildKubecfg(t *testing.T) {
 	certCluster := buildMinimalCluster("testcluster", "testcluster.test.com", true, false)
 	certNLBCluster := buildMinimalCluster("testcluster", "testcluster.test.com", true, true)
 	certGossipNLBCluster := buildMinimalCluster("testgossipcluster.k8s.local", "", true, true)
+	oidcCluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
+	oidcCluster.Spec.Authentication = &kops.AuthenticationSpec{
+		OIDC: &kops.OIDCAuthenticationSpec{
+			IssuerURL:   "https://accounts.example.com",
+			ClientID:    "kubernetes",
+			GroupsClaim: "groups",
+		},
+	}
+	usersCluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
+	usersCluster.Spec.Authentication = &kops.AuthenticationSpec{
+		Users: &kops.UserAuthenticationSpec{},
+	}
 
 	tests := []struct {
 		name           string
@@ -308,6 +322,94 @@ func TestBuildKubecfg(t *testing.T) {
 			},
 			wantClientCert: false,
 		},
+		{
+			name: "Public DNS with OIDC authentication",
+			args: args{
+				cluster:               oidcCluster,
+				status:                fakeStatusCloud{},
+				useOIDCAuthentication: true,
+			},
+			want: &KubeconfigBuilder{
+				Context: "testcluster",
+				Server:  "https://testcluster.test.com",
+				CACerts: []byte(nextCertificate + certData),
+				User:    "testcluster",
+				AuthenticationExec: []string{
+					"kubectl",
+					"oidc-login",
+					"get-token",
+					"--oidc-issuer-url=https://accounts.example.com",
+					"--oidc-client-id=kubernetes",
+					"--oidc-extra-scope=groups",
+				},
+			},
+			wantClientCert: false,
+		},
+		{
+			name: "OIDC authentication without OIDC configured",
+			args: args{
+				cluster:               publicCluster,
+				status:                fakeStatusCloud{},
+				useOIDCAuthentication: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Public DNS with user certificate",
+			args: args{
+				cluster: usersCluster,
+				status:  fakeStatusCloud{},
+				userCertificate: &UserCertificate{
+					Identity: "alice",
+					Groups:   []string{"developers"},
+					Lifetime: 8 * time.Hour,
+				},
+			},
+			want: &KubeconfigBuilder{
+				Context: "testcluster",
+				Server:  "https://testcluster.test.com",
+				CACerts: []byte(nextCertificate + certData),
+				User:    "testcluster",
+			},
+			wantClientCert: true,
+		},
+		{
+			name: "User certificate exceeding the maximum validity",
+			args: args{
+				cluster: usersCluster,
+				status:  fakeStatusCloud{},
+				userCertificate: &UserCertificate{
+					Identity: "alice",
+					Lifetime: 48 * time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "User certificate in a system group",
+			args: args{
+				cluster: usersCluster,
+				status:  fakeStatusCloud{},
+				userCertificate: &UserCertificate{
+					Identity: "alice",
+					Groups:   []string{"system:masters"},
+					Lifetime: time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "User certificate without user certificates configured",
+			args: args{
+				cluster: publicCluster,
+				status:  fakeStatusCloud{},
+				userCertificate: &UserCertificate{
+					Identity: "alice",
+					Lifetime: time.Hour,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Test Kube Config Data For internal DNS name with admin",
 			args: args{
@@ -359,7 +461,15 @@ func TestBuildKubecfg(t *testing.T) {
 				},
 			}
 
-			got, err := BuildKubecfg(tt.args.cluster, keyStore, tt.args.secretStore, tt.args.status, tt.args.admin, tt.args.user, tt.args.internal, kopsStateStore, tt.args.useKopsAuthenticationPlugin)
+			got, err := BuildKubecfg(tt.args.cluster, keyStore, tt.args.secretStore, tt.args.status, BuildKubecfgOptions{
+				Admin:                       tt.args.admin,
+				User:                        tt.args.user,
+				Internal:                    tt.args.internal,
+				KopsStateStore:              kopsStateStore,
+				UseKopsAuthenticationPlugin: tt.args.useKopsAuthenticationPlugin,
+				UseOIDCAuthentication:       tt.args.useOIDCAuthentication,
+				UserCertificate:             tt.args.userCertificate,
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("BuildKubecfg() error = %v, wantErr %v", err, tt.wantErr)
 				return
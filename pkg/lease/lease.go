@@ -0,0 +1,286 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lease implements a lock on a cluster's state store, held as a lease object stored in the state store itself.
+// Because it only needs to read, create, write and remove a file, it works on every vfs backend.
+// On backends without conditional writes, such as S3, two writers racing for a free lock are
+// resolved on a best-effort basis, by reading the lock back after a short delay.
+package lease
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// PathLock is the path, relative to the cluster's config base, of the lease object
+const PathLock = "lock"
+
+// DefaultTTL is how long a lease is valid if it is not renewed
+const DefaultTTL = 10 * time.Minute
+
+// settleDelay is how long we wait before reading back a lease we wrote, to detect a concurrent writer
+var settleDelay = time.Second
+
+// Lease is the lease object stored in the state store
+type Lease struct {
+	// ID identifies the holder uniquely, even when the same user runs kOps twice
+	ID string `json:"id"`
+	// Holder is a human readable description of the holder
+	Holder string `json:"holder"`
+	// Operation is what the holder is doing
+	Operation string `json:"operation,omitempty"`
+	// AcquireTime is when the lease was acquired
+	AcquireTime time.Time `json:"acquireTime"`
+	// RenewTime is when the lease was last renewed
+	RenewTime time.Time `json:"renewTime"`
+	// TTLSeconds is how long the lease is valid after it was renewed
+	TTLSeconds int64 `json:"ttlSeconds"`
+}
+
+// Expiry returns when the lease expires if it is not renewed
+func (l *Lease) Expiry() time.Time {
+	return l.RenewTime.Add(time.Duration(l.TTLSeconds) * time.Second)
+}
+
+// Options configures a lease
+type Options struct {
+	// Operation is what we hold the lease for, e.g. "update cluster"
+	Operation string
+	// TTL is how long the lease is valid if it is not renewed; DefaultTTL if zero
+	TTL time.Duration
+	// Force takes over the lease even if it is held by someone else
+	Force bool
+}
+
+// Lock is a held lease, which is renewed in the background until it is released
+type Lock struct {
+	path  vfs.Path
+	acl   vfs.ACL
+	lease Lease
+
+	mutex   sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// HeldError is returned when the lease is held by someone else
+type HeldError struct {
+	Lease *Lease
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("the state store is locked by %s for %q since %s, until %s; if that is stale, use --force-unlock",
+		e.Lease.Holder, e.Lease.Operation, e.Lease.AcquireTime.Format(time.RFC3339), e.Lease.Expiry().Format(time.RFC3339))
+}
+
+// Acquire takes the lease stored under the configBase of cluster, failing with a HeldError if someone else holds it.
+func Acquire(configBase vfs.Path, cluster *kops.Cluster, options Options) (*Lock, error) {
+	ttl := options.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	now := time.Now().UTC()
+	l := &Lock{
+		path: configBase.Join(PathLock),
+		lease: Lease{
+			ID:          uuid.New().String(),
+			Holder:      holder(),
+			Operation:   options.Operation,
+			AcquireTime: now,
+			RenewTime:   now,
+			TTLSeconds:  int64(ttl / time.Second),
+		},
+	}
+
+	acl, err := acls.GetACL(l.path, cluster)
+	if err != nil {
+		return nil, err
+	}
+	l.acl = acl
+
+	existing, err := read(l.path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(&l.lease)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing lease: %v", err)
+	}
+
+	switch {
+	case existing == nil:
+		err = l.path.CreateFile(bytes.NewReader(data), l.acl)
+		if os.IsExist(err) {
+			// Someone else got there first
+			existing, err = read(l.path)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				return nil, &HeldError{Lease: existing}
+			}
+			return nil, fmt.Errorf("lease %s was created and removed concurrently; try again", l.path)
+		}
+	case options.Force:
+		klog.Warningf("forcibly taking over the state store lock held by %s for %q", existing.Holder, existing.Operation)
+		err = l.path.WriteFile(bytes.NewReader(data), l.acl)
+	case time.Now().After(existing.Expiry()):
+		klog.Warningf("taking over the state store lock held by %s for %q, which expired at %s", existing.Holder, existing.Operation, existing.Expiry().Format(time.RFC3339))
+		err = l.path.WriteFile(bytes.NewReader(data), l.acl)
+	default:
+		return nil, &HeldError{Lease: existing}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing lease %s: %v", l.path, err)
+	}
+
+	// Read the lease back, in case a concurrent writer overwrote it
+	time.Sleep(settleDelay)
+	current, err := read(l.path)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.ID != l.lease.ID {
+		if current != nil {
+			return nil, &HeldError{Lease: current}
+		}
+		return nil, fmt.Errorf("lease %s was removed while acquiring it; try again", l.path)
+	}
+
+	l.stop = make(chan struct{})
+	l.stopped = make(chan struct{})
+	go l.renewLoop(ttl / 3)
+
+	return l, nil
+}
+
+// Lease returns the lease we hold
+func (l *Lock) Lease() Lease {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.lease
+}
+
+// Renew extends the lease, failing if it was taken over by someone else
+func (l *Lock) Renew() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current, err := read(l.path)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.ID != l.lease.ID {
+		if current != nil {
+			return fmt.Errorf("the state store lock was taken over by %s", current.Holder)
+		}
+		return fmt.Errorf("the state store lock was removed")
+	}
+
+	l.lease.RenewTime = time.Now().UTC()
+	data, err := json.Marshal(&l.lease)
+	if err != nil {
+		return fmt.Errorf("error serializing lease: %v", err)
+	}
+	if err := l.path.WriteFile(bytes.NewReader(data), l.acl); err != nil {
+		return fmt.Errorf("error writing lease %s: %v", l.path, err)
+	}
+	return nil
+}
+
+// Release stops renewing the lease and removes it, unless it was taken over by someone else
+func (l *Lock) Release() error {
+	close(l.stop)
+	<-l.stopped
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current, err := read(l.path)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.ID != l.lease.ID {
+		klog.Warningf("not releasing the state store lock, as it is no longer ours")
+		return nil
+	}
+	if err := l.path.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing lease %s: %v", l.path, err)
+	}
+	return nil
+}
+
+func (l *Lock) renewLoop(interval time.Duration) {
+	defer close(l.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.Renew(); err != nil {
+				klog.Warningf("unable to renew the state store lock: %v", err)
+			}
+		}
+	}
+}
+
+// read returns the lease stored at p, or nil if there is none
+func read(p vfs.Path) (*Lease, error) {
+	data, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading lease %s: %v", p, err)
+	}
+	lease := &Lease{}
+	if err := json.Unmarshal(data, lease); err != nil {
+		return nil, fmt.Errorf("error parsing lease %s: %v", p, err)
+	}
+	return lease, nil
+}
+
+// CurrentUser describes who is running kOps, as user@hostname, for the records kept in the state store.
+func CurrentUser() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		name += "@" + hostname
+	}
+	return name
+}
+
+func holder() string {
+	return fmt.Sprintf("%s (pid %d)", CurrentUser(), os.Getpid())
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lease
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func init() {
+	settleDelay = 0
+}
+
+func configBases(t *testing.T) map[string]vfs.Path {
+	vfs.Context.ResetMemfsContext(true)
+	memfs, err := vfs.Context.BuildVfsPath("memfs://state/cluster.example.com")
+	require.NoError(t, err)
+
+	fs, err := vfs.Context.BuildVfsPath("file://" + t.TempDir())
+	require.NoError(t, err)
+
+	return map[string]vfs.Path{
+		"memfs": memfs,
+		"fs":    fs,
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	for name, configBase := range configBases(t) {
+		t.Run(name, func(t *testing.T) {
+			lock, err := Acquire(configBase, &kops.Cluster{}, Options{Operation: "update cluster"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(DefaultTTL/time.Second), lock.Lease().TTLSeconds)
+
+			_, err = Acquire(configBase, &kops.Cluster{}, Options{Operation: "update cluster"})
+			require.Error(t, err)
+			heldError, ok := err.(*HeldError)
+			require.True(t, ok, "expected a HeldError, got %v", err)
+			assert.Equal(t, lock.Lease().ID, heldError.Lease.ID)
+			assert.Contains(t, err.Error(), "--force-unlock")
+
+			require.NoError(t, lock.Renew())
+
+			require.NoError(t, lock.Release())
+			_, err = configBase.Join(PathLock).ReadFile()
+			assert.Error(t, err, "lease should be removed when released")
+
+			lock, err = Acquire(configBase, &kops.Cluster{}, Options{Operation: "update cluster"})
+			require.NoError(t, err)
+			require.NoError(t, lock.Release())
+		})
+	}
+}
+
+func TestAcquireTakeover(t *testing.T) {
+	grid := []struct {
+		name      string
+		renewTime time.Time
+		force     bool
+		expectErr bool
+	}{
+		{name: "held", renewTime: time.Now(), expectErr: true},
+		{name: "expired", renewTime: time.Now().Add(-time.Hour)},
+		{name: "forced", renewTime: time.Now(), force: true},
+	}
+	for name, configBase := range configBases(t) {
+		for _, g := range grid {
+			t.Run(name+"/"+g.name, func(t *testing.T) {
+				stale := &Lease{ID: "other", Holder: "bob@laptop", RenewTime: g.renewTime, TTLSeconds: 60}
+				data, err := json.Marshal(stale)
+				require.NoError(t, err)
+				require.NoError(t, configBase.Join(PathLock).WriteFile(bytes.NewReader(data), nil))
+
+				lock, err := Acquire(configBase, &kops.Cluster{}, Options{Force: g.force})
+				if g.expectErr {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), "bob@laptop")
+					return
+				}
+				require.NoError(t, err)
+				assert.NotEqual(t, "other", lock.Lease().ID)
+				require.NoError(t, lock.Release())
+			})
+		}
+	}
+}
+
+func TestReleaseAfterTakeover(t *testing.T) {
+	for name, configBase := range configBases(t) {
+		t.Run(name, func(t *testing.T) {
+			lock, err := Acquire(configBase, &kops.Cluster{}, Options{})
+			require.NoError(t, err)
+
+			other, err := Acquire(configBase, &kops.Cluster{}, Options{Force: true})
+			require.NoError(t, err)
+
+			assert.Error(t, lock.Renew(), "renewing a lease that was taken over should fail")
+
+			// Releasing must not remove the lease that is now held by someone else
+			require.NoError(t, lock.Release())
+			_, err = configBase.Join(PathLock).ReadFile()
+			assert.NoError(t, err)
+
+			require.NoError(t, other.Release())
+		})
+	}
+}
@@ -224,6 +224,9 @@ func (b *BootstrapScriptBuilder) ResourceNodeUp(c *fi.ModelBuilderContext, ig *k
 		if b.UseEtcdManager() {
 			keypairs = append(keypairs, "etcd-clients-ca")
 		}
+		if b.Cluster.Spec.Authentication != nil && b.Cluster.Spec.Authentication.Users != nil {
+			keypairs = append(keypairs, fi.CertificateIDUsersCA)
+		}
 	}
 
 	caTasks := map[string]*fitasks.Keypair{}
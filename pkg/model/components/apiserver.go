@@ -75,6 +75,27 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		if clusterSpec.Authentication.Kopeio != nil {
 			c.AuthenticationTokenWebhookConfigFile = fi.String("/etc/kubernetes/authn.config")
 		}
+		if oidc := clusterSpec.Authentication.OIDC; oidc != nil {
+			// Settings made directly on kubeAPIServer take precedence
+			if c.OIDCIssuerURL == nil {
+				c.OIDCIssuerURL = fi.String(oidc.IssuerURL)
+			}
+			if c.OIDCClientID == nil {
+				c.OIDCClientID = fi.String(oidc.ClientID)
+			}
+			if c.OIDCUsernameClaim == nil && oidc.UsernameClaim != "" {
+				c.OIDCUsernameClaim = fi.String(oidc.UsernameClaim)
+			}
+			if c.OIDCUsernamePrefix == nil && oidc.UsernamePrefix != "" {
+				c.OIDCUsernamePrefix = fi.String(oidc.UsernamePrefix)
+			}
+			if c.OIDCGroupsClaim == nil && oidc.GroupsClaim != "" {
+				c.OIDCGroupsClaim = fi.String(oidc.GroupsClaim)
+			}
+			if c.OIDCGroupsPrefix == nil && oidc.GroupsPrefix != "" {
+				c.OIDCGroupsPrefix = fi.String(oidc.GroupsPrefix)
+			}
+		}
 	}
 
 	if clusterSpec.Authorization == nil || clusterSpec.Authorization.IsEmpty() {
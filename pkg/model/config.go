@@ -42,7 +42,10 @@ func (b *ConfigBuilder) Build(c *fi.ModelBuilderContext) error {
 		Contents:  fi.NewStringResource(kopsbase.Version),
 	})
 
-	versionedYaml, err := kopscodecs.ToVersionedYaml(b.Cluster)
+	// The resourceVersion identifies the revision in the state store, not part of the completed spec
+	completed := b.Cluster.DeepCopy()
+	completed.ResourceVersion = ""
+	versionedYaml, err := kopscodecs.ToVersionedYaml(completed)
 	if err != nil {
 		return fmt.Errorf("serializing completed cluster spec: %w", err)
 	}
@@ -38,6 +38,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/stateencryption"
 	"k8s.io/kops/pkg/util/stringorslice"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
@@ -287,6 +288,8 @@ func (r *NodeRoleAPIServer) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		addKMSIAMPolicies(p, stringorslice.Slice(b.KMSKeys))
 	}
 
+	addStateEncryptionPolicies(p, b.Cluster)
+
 	if b.Cluster.Spec.IAM.AllowContainerRegistry {
 		addECRPermissions(p)
 	}
@@ -332,6 +335,12 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		addKMSIAMPolicies(p, stringorslice.Slice(b.KMSKeys))
 	}
 
+	addStateEncryptionPolicies(p, b.Cluster)
+
+	if b.Cluster.Spec.AutoApply != nil {
+		addAutoApplyPolicies(p, b.Cluster, b.IAMPrefix())
+	}
+
 	// Protokube needs dns-controller permissions in instance role even if UseServiceAccountIAM.
 	AddDNSControllerPermissions(b, p)
 
@@ -390,6 +399,8 @@ func (r *NodeRoleNode) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
 	}
 
+	addStateEncryptionPolicies(p, b.Cluster)
+
 	if b.Cluster.Spec.IAM.AllowContainerRegistry {
 		addECRPermissions(p)
 	}
@@ -625,6 +636,15 @@ func WriteableVFSPaths(cluster *kops.Cluster, role Subject) ([]vfs.Path, error)
 
 			backupStores.Insert(backupStore)
 		}
+
+		// kops-controller writes the cluster's configuration when it applies the cluster spec
+		if cluster.Spec.AutoApply != nil && cluster.Spec.ConfigStore != "" {
+			vfsPath, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigStore, err)
+			}
+			paths = append(paths, vfsPath)
+		}
 	}
 
 	return paths, nil
@@ -947,6 +967,180 @@ func addKMSIAMPolicies(p *Policy, resource stringorslice.StringOrSlice) {
 	)
 }
 
+// addStateEncryptionPolicies allows decrypting the private keys and secrets in the state store,
+// if they are encrypted with an AWS KMS key.
+func addStateEncryptionPolicies(p *Policy, cluster *kops.Cluster) {
+	key := stateencryption.AWSKMSKey(cluster.Spec.StateEncryptionKey)
+	if key == "" {
+		return
+	}
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Slice([]string{"kms:Decrypt"}),
+		Resource: stringorslice.Slice([]string{key}),
+	})
+}
+
+// addAutoApplyPolicies allows kops-controller to check the phases of the cluster spec against the cloud,
+// and to apply the phases whose autoApply policy is Auto.
+func addAutoApplyPolicies(p *Policy, cluster *kops.Cluster, iamPrefix string) {
+	autoApply := cluster.Spec.AutoApply
+	clusterName := cluster.GetName()
+
+	// The dry run reads the resources of every phase
+	p.unconditionalAction.Insert(
+		"autoscaling:Describe*",
+		"ec2:Describe*",
+		"elasticloadbalancing:Describe*",
+		"iam:GetInstanceProfile",
+		"iam:GetRole",
+		"iam:GetRolePolicy",
+		"iam:ListAttachedRolePolicies",
+		"iam:ListInstanceProfiles",
+		"iam:ListRolePolicies",
+		"route53:GetHostedZone",
+		"route53:ListHostedZones",
+		"route53:ListResourceRecordSets",
+	)
+
+	if autoApply.Network == kops.AutoApplyPolicyAuto {
+		p.unconditionalAction.Insert(
+			"ec2:AllocateAddress",
+			"ec2:CreateDhcpOptions",
+			"ec2:CreateInternetGateway",
+			"ec2:CreateNatGateway",
+			"ec2:CreateRouteTable",
+			"ec2:CreateSubnet",
+			"ec2:CreateTags",
+			"ec2:CreateVpc",
+		)
+		p.clusterTaggedAction.Insert(
+			"ec2:AssociateDhcpOptions",
+			"ec2:AssociateRouteTable",
+			"ec2:AttachInternetGateway",
+			"ec2:CreateRoute",
+			"ec2:DeleteDhcpOptions",
+			"ec2:DeleteInternetGateway",
+			"ec2:DeleteNatGateway",
+			"ec2:DeleteRoute",
+			"ec2:DeleteRouteTable",
+			"ec2:DeleteSubnet",
+			"ec2:DeleteTags",
+			"ec2:DeleteVpc",
+			"ec2:DetachInternetGateway",
+			"ec2:DisassociateRouteTable",
+			"ec2:ModifySubnetAttribute",
+			"ec2:ModifyVpcAttribute",
+			"ec2:ReleaseAddress",
+			"ec2:ReplaceRoute",
+		)
+	}
+
+	// The IAM roles and instance profiles of the cluster are named <name>.<cluster name>
+	iamResources := stringorslice.Of(
+		iamPrefix+":iam::*:instance-profile/*."+clusterName,
+		iamPrefix+":iam::*:role/*."+clusterName,
+	)
+
+	if autoApply.Security == kops.AutoApplyPolicyAuto {
+		p.unconditionalAction.Insert(
+			"ec2:CreateSecurityGroup",
+			"ec2:CreateTags",
+		)
+		p.clusterTaggedAction.Insert(
+			"ec2:AuthorizeSecurityGroupEgress",
+			"ec2:AuthorizeSecurityGroupIngress",
+			"ec2:DeleteSecurityGroup",
+			"ec2:RevokeSecurityGroupEgress",
+			"ec2:RevokeSecurityGroupIngress",
+		)
+		p.Statement = append(p.Statement, &Statement{
+			Effect: StatementEffectAllow,
+			Action: stringorslice.Of(
+				"iam:AddRoleToInstanceProfile",
+				"iam:CreateInstanceProfile",
+				"iam:DeleteInstanceProfile",
+				"iam:RemoveRoleFromInstanceProfile",
+				"iam:TagInstanceProfile",
+				"iam:TagRole",
+				"iam:UntagRole",
+			),
+			Resource: iamResources,
+		})
+
+		// The roles can only be created or given policies if they keep the permissions boundary,
+		// otherwise the control plane could grant itself any permission.
+		// Validation requires the boundary when the security phase is applied automatically.
+		if cluster.Spec.IAM != nil && cluster.Spec.IAM.PermissionsBoundary != nil {
+			p.Statement = append(p.Statement, &Statement{
+				Effect: StatementEffectAllow,
+				Action: stringorslice.Of(
+					"iam:AttachRolePolicy",
+					"iam:CreateRole",
+					"iam:DeleteRolePolicy",
+					"iam:DetachRolePolicy",
+					"iam:PutRolePolicy",
+				),
+				Resource: stringorslice.Of(iamPrefix + ":iam::*:role/*." + clusterName),
+				Condition: Condition{
+					"StringEquals": map[string]string{
+						"iam:PermissionsBoundary": *cluster.Spec.IAM.PermissionsBoundary,
+					},
+				},
+			})
+		}
+	}
+
+	// The cluster phase is applied unless its policy is Never
+	if autoApply.Cluster != kops.AutoApplyPolicyNever {
+		p.unconditionalAction.Insert(
+			"autoscaling:CreateAutoScalingGroup",
+			"autoscaling:CreateOrUpdateTags",
+			"ec2:CreateLaunchTemplate",
+			"ec2:CreateTags",
+			"ec2:CreateVolume",
+			"ec2:ImportKeyPair",
+			"ec2:RunInstances",
+			"elasticloadbalancing:RemoveTags",
+			"elasticloadbalancing:SetSecurityGroups",
+			"elasticloadbalancing:SetSubnets",
+		)
+		p.clusterTaggedAction.Insert(
+			"autoscaling:AttachLoadBalancerTargetGroups",
+			"autoscaling:AttachLoadBalancers",
+			"autoscaling:DeleteAutoScalingGroup",
+			"autoscaling:DeleteLifecycleHook",
+			"autoscaling:DeleteTags",
+			"autoscaling:DetachLoadBalancerTargetGroups",
+			"autoscaling:DetachLoadBalancers",
+			"autoscaling:DisableMetricsCollection",
+			"autoscaling:EnableMetricsCollection",
+			"autoscaling:PutLifecycleHook",
+			"autoscaling:ResumeProcesses",
+			"autoscaling:SuspendProcesses",
+			"autoscaling:UpdateAutoScalingGroup",
+			"ec2:CreateLaunchTemplateVersion",
+			"ec2:DeleteLaunchTemplate",
+			"ec2:ModifyLaunchTemplate",
+		)
+		// The launch templates pass the instance profiles of the cluster to the instances
+		p.Statement = append(p.Statement, &Statement{
+			Effect:   StatementEffectAllow,
+			Action:   stringorslice.Slice([]string{"iam:PassRole"}),
+			Resource: iamResources,
+		})
+	}
+
+	// New keypairs and secrets are encrypted with the state encryption key
+	if key := stateencryption.AWSKMSKey(cluster.Spec.StateEncryptionKey); key != "" {
+		p.Statement = append(p.Statement, &Statement{
+			Effect:   StatementEffectAllow,
+			Action:   stringorslice.Slice([]string{"kms:Encrypt"}),
+			Resource: stringorslice.Slice([]string{key}),
+		})
+	}
+}
+
 func addKMSGenerateRandomPolicies(p *Policy) {
 	// For nodeup to seed the instance's random number generator.
 	p.unconditionalAction.Insert(
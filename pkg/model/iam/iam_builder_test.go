@@ -118,6 +118,9 @@ func TestPolicyGeneration(t *testing.T) {
 	grid := []struct {
 		Role                   Subject
 		AllowContainerRegistry bool
+		StateEncryptionKey     string
+		AutoApply              *kops.AutoApplySpec
+		PermissionsBoundary    *string
 		Policy                 string
 	}{
 		{
@@ -140,6 +143,36 @@ func TestPolicyGeneration(t *testing.T) {
 			AllowContainerRegistry: true,
 			Policy:                 "tests/iam_builder_node_strict_ecr.json",
 		},
+		{
+			Role:               &NodeRoleMaster{},
+			StateEncryptionKey: "aws-kms://arn:aws:kms:us-east-1:123456789012:key/state-key",
+			Policy:             "tests/iam_builder_master_state_encryption.json",
+		},
+		{
+			Role:               &NodeRoleNode{},
+			StateEncryptionKey: "aws-kms://arn:aws:kms:us-east-1:123456789012:key/state-key",
+			Policy:             "tests/iam_builder_node_state_encryption.json",
+		},
+		{
+			Role:      &NodeRoleMaster{},
+			AutoApply: &kops.AutoApplySpec{},
+			Policy:    "tests/iam_builder_master_auto_apply.json",
+		},
+		{
+			Role: &NodeRoleMaster{},
+			AutoApply: &kops.AutoApplySpec{
+				Network:  kops.AutoApplyPolicyAuto,
+				Security: kops.AutoApplyPolicyAuto,
+				Cluster:  kops.AutoApplyPolicyAuto,
+			},
+			PermissionsBoundary: fi.String("arn:aws:iam::123456789012:policy/kops-boundary"),
+			Policy:              "tests/iam_builder_master_auto_apply_all.json",
+		},
+		{
+			Role:      &NodeRoleNode{},
+			AutoApply: &kops.AutoApplySpec{},
+			Policy:    "tests/iam_builder_node_strict.json",
+		},
 		{
 			Role:                   &NodeRoleBastion{},
 			AllowContainerRegistry: false,
@@ -156,9 +189,12 @@ func TestPolicyGeneration(t *testing.T) {
 		b := &PolicyBuilder{
 			Cluster: &kops.Cluster{
 				Spec: kops.ClusterSpec{
-					ConfigStore: "s3://kops-tests/iam-builder-test.k8s.local",
+					ConfigStore:        "s3://kops-tests/iam-builder-test.k8s.local",
+					StateEncryptionKey: x.StateEncryptionKey,
+					AutoApply:          x.AutoApply,
 					IAM: &kops.IAMSpec{
 						AllowContainerRegistry: x.AllowContainerRegistry,
+						PermissionsBoundary:    x.PermissionsBoundary,
 					},
 					EtcdClusters: []kops.EtcdClusterSpec{
 						{
@@ -161,6 +161,16 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		c.AddTask(serviceAccount)
 	}
 
+	if b.Cluster.Spec.Authentication != nil && b.Cluster.Spec.Authentication.Users != nil {
+		// The users CA is separate from the cluster CA, so it can be rotated and distrusted independently.
+		c.AddTask(&fitasks.Keypair{
+			Name:      fi.String(fi.CertificateIDUsersCA),
+			Lifecycle: b.Lifecycle,
+			Subject:   "cn=kubernetes-users",
+			Type:      "ca",
+		})
+	}
+
 	// @TODO this is VERY presumptuous, i'm going on the basis we can make it configurable in the future.
 	// But I'm conscious not to do too much work on bootstrap tokens as it might overlay further down the
 	// line with the machines api
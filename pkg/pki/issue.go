@@ -18,6 +18,7 @@ package pki
 
 import (
 	"crypto"
+	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
@@ -34,6 +35,11 @@ var wellKnownCertificateTypes = map[string]string{
 	"server":       "ExtKeyUsageServerAuth,KeyUsageDigitalSignature,KeyUsageKeyEncipherment",
 }
 
+// remoteCertDefaultValidity is the validity requested from remote signers when the request has none,
+// rather than leaving it to the remote signer's default. It matches the shortest validity of the
+// certificates issued by nodeup (nodetasks.IssuedCertMinimumValidity).
+const remoteCertDefaultValidity = 455 * 24 * time.Hour
+
 type IssueCertRequest struct {
 	// Signer is the keypair to use to sign. Ignored if Type is "CA", in which case the cert will be self-signed.
 	Signer string
@@ -48,7 +54,7 @@ type IssueCertRequest struct {
 	PublicKey crypto.PublicKey
 	// PrivateKey is the private key for this certificate. If both this and PublicKey are nil, a new private key will be generated.
 	PrivateKey *PrivateKey
-	// Validity is the certificate validity. The default is 10 years.
+	// Validity is the certificate validity. The default is 10 years, or 455 days if the signer is remote.
 	Validity time.Duration
 
 	// Serial is the certificate serial number. If nil, a random number will be generated.
@@ -61,6 +67,16 @@ type Keystore interface {
 	FindPrimaryKeypair(name string) (*Certificate, *PrivateKey, error)
 }
 
+// RemoteSigner is implemented by keystores that hold the private keys of some signers remotely,
+// such as in Vault's PKI secrets engine, and sign certificate requests with them instead of returning them.
+type RemoteSigner interface {
+	// IsRemoteSigner returns true if the private key of the named signer is held remotely.
+	IsRemoteSigner(name string) (bool, error)
+	// SignCertificateRequest signs csr with the named signer, with the usages and expiry of template.
+	// It returns the issued certificate and the certificate of the signer.
+	SignCertificateRequest(name string, csr *x509.CertificateRequest, template *x509.Certificate) (issuedCertificate *Certificate, caCertificate *Certificate, err error)
+}
+
 // IssueCert issues a certificate, either a self-signed CA or from a CA in a keystore.
 func IssueCert(request *IssueCertRequest, keystore Keystore) (issuedCertificate *Certificate, issuedKey *PrivateKey, caCertificate *Certificate, err error) {
 	certificateType := request.Type
@@ -115,6 +131,16 @@ func IssueCert(request *IssueCertRequest, keystore Keystore) (issuedCertificate
 	var caPrivateKey *PrivateKey
 	var signer *x509.Certificate
 	if !template.IsCA {
+		if remoteSigner, ok := keystore.(RemoteSigner); ok {
+			remote, err := remoteSigner.IsRemoteSigner(request.Signer)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if remote {
+				return issueRemoteCert(request, template, remoteSigner)
+			}
+		}
+
 		var err error
 		caCertificate, caPrivateKey, err = keystore.FindPrimaryKeypair(request.Signer)
 		if err != nil {
@@ -155,3 +181,44 @@ func IssueCert(request *IssueCertRequest, keystore Keystore) (issuedCertificate
 
 	return certificate, privateKey, caCertificate, err
 }
+
+// issueRemoteCert issues a certificate by sending a certificate request to a signer whose private key is held remotely.
+func issueRemoteCert(request *IssueCertRequest, template *x509.Certificate, remoteSigner RemoteSigner) (*Certificate, *PrivateKey, *Certificate, error) {
+	privateKey := request.PrivateKey
+	if privateKey == nil {
+		if request.PublicKey != nil {
+			return nil, nil, nil, fmt.Errorf("ca key for %q is held remotely; cannot issue certificates for a public key without its private key", request.Signer)
+		}
+		var err error
+		privateKey, err = GeneratePrivateKey()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	validity := request.Validity
+	if validity == 0 {
+		validity = remoteCertDefaultValidity
+	}
+	template.NotAfter = time.Now().Add(validity).UTC()
+
+	csrTemplate := &x509.CertificateRequest{
+		Subject:     template.Subject,
+		DNSNames:    template.DNSNames,
+		IPAddresses: template.IPAddresses,
+	}
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, csrTemplate, privateKey.Key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error creating certificate request: %v", err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrBytes)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error parsing certificate request: %v", err)
+	}
+
+	certificate, caCertificate, err := remoteSigner.SignCertificateRequest(request.Signer, csr, template)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error signing certificate with %q: %v", request.Signer, err)
+	}
+	return certificate, privateKey, caCertificate, nil
+}
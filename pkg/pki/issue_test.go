@@ -17,9 +17,11 @@ limitations under the License.
 package pki
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"math/big"
 	"net"
 	"os"
 	"testing"
@@ -222,3 +224,90 @@ func TestIssueCert(t *testing.T) {
 	}
 
 }
+
+type mockRemoteSigner struct {
+	t      *testing.T
+	signer string
+	cert   *Certificate
+	key    *PrivateKey
+}
+
+func (m *mockRemoteSigner) FindPrimaryKeypair(name string) (*Certificate, *PrivateKey, error) {
+	m.t.Errorf("FindPrimaryKeypair called for remote signer %q", name)
+	return nil, nil, nil
+}
+
+func (m *mockRemoteSigner) IsRemoteSigner(name string) (bool, error) {
+	return name == m.signer, nil
+}
+
+func (m *mockRemoteSigner) SignCertificateRequest(name string, csr *x509.CertificateRequest, template *x509.Certificate) (*Certificate, *Certificate, error) {
+	assert.Equal(m.t, m.signer, name, "name argument")
+	assert.NoError(m.t, csr.CheckSignature(), "certificate request signature")
+
+	signed := *template
+	signed.Subject = csr.Subject
+	signed.DNSNames = csr.DNSNames
+	signed.IPAddresses = csr.IPAddresses
+	signed.SerialNumber = big.NewInt(1)
+	signed.NotBefore = time.Now()
+	der, err := x509.CreateCertificate(rand.Reader, &signed, m.cert.Certificate, csr.PublicKey, m.key.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Certificate{Subject: cert.Subject, Certificate: cert, PublicKey: cert.PublicKey}, m.cert, nil
+}
+
+func TestIssueRemoteCert(t *testing.T) {
+	caCertificate, caPrivateKey, _, err := IssueCert(&IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "Remote CA"},
+	}, nil)
+	require.NoError(t, err)
+
+	keystore := &mockRemoteSigner{
+		t:      t,
+		signer: "remote-ca",
+		cert:   caCertificate,
+		key:    caPrivateKey,
+	}
+
+	certificate, key, caCert, err := IssueCert(&IssueCertRequest{
+		Signer:         "remote-ca",
+		Type:           "server",
+		Subject:        pkix.Name{CommonName: "Test server"},
+		AlternateNames: []string{"localhost", "127.0.0.1"},
+		Validity:       24 * time.Hour,
+	}, keystore)
+	require.NoError(t, err)
+
+	cert := certificate.Certificate
+	assert.NoError(t, cert.CheckSignatureFrom(caCertificate.Certificate), "check signature")
+	assert.Equal(t, caCertificate, caCert, "returned CA cert")
+	assert.Equal(t, "Test server", cert.Subject.CommonName, "Subject")
+	assert.Equal(t, []string{"localhost"}, cert.DNSNames, "DNSNames")
+	assert.Equal(t, []net.IP{net.ParseIP("127.0.0.1").To4()}, cert.IPAddresses, "IPAddresses")
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment, cert.KeyUsage, "KeyUsage")
+	rsaPrivateKey, ok := key.Key.(*rsa.PrivateKey)
+	require.True(t, ok, "private key is RSA")
+	assert.Equal(t, &rsaPrivateKey.PublicKey, cert.PublicKey, "certificate public key matches private key")
+
+	_, _, _, err = IssueCert(&IssueCertRequest{
+		Signer:    "remote-ca",
+		Type:      "client",
+		PublicKey: &rsaPrivateKey.PublicKey,
+	}, keystore)
+	assert.Error(t, err, "issuing for a public key without its private key")
+
+	certificate, _, _, err = IssueCert(&IssueCertRequest{
+		Signer:  "remote-ca",
+		Type:    "client",
+		Subject: pkix.Name{CommonName: "Test client"},
+	}, keystore)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(remoteCertDefaultValidity), certificate.Certificate.NotAfter, time.Minute, "NotAfter without a validity")
+}
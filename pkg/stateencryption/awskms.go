@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stateencryption
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// awsKMSProvider wraps keys with an AWS KMS key
+type awsKMSProvider struct {
+	client *kms.KMS
+	keyID  string
+}
+
+var _ keyEncryptionProvider = &awsKMSProvider{}
+
+// newAWSKMSProvider builds a provider for a key ARN, or a key ID or alias in the default region.
+func newAWSKMSProvider(keyID string) (keyEncryptionProvider, error) {
+	config := aws.NewConfig().WithCredentialsChainVerboseErrors(true)
+	if keyARN, err := arn.Parse(keyID); err == nil {
+		config = config.WithRegion(keyARN.Region)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws session: %v", err)
+	}
+
+	return &awsKMSProvider{
+		client: kms.New(sess),
+		keyID:  keyID,
+	}, nil
+}
+
+func (p *awsKMSProvider) WrapKey(dek []byte) ([]byte, error) {
+	response, err := p.client.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.CiphertextBlob, nil
+}
+
+func (p *awsKMSProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	response, err := p.client.Decrypt(&kms.DecryptInput{
+		KeyId:          aws.String(p.keyID),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
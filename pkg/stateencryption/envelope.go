@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stateencryption implements envelope encryption of private key material and secrets in the state store.
+//
+// Each value is encrypted with its own data encryption key, which is in turn encrypted ("wrapped")
+// by a key encryption key held by a keyEncryptionProvider such as AWS KMS. The envelope names the
+// key encryption key it was wrapped with, so reading an encrypted value needs no configuration,
+// only permission to unwrap with that key.
+package stateencryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// envelopePrefix marks data that is an envelope rather than plaintext
+var envelopePrefix = []byte("kops-envelope/v1:")
+
+// envelope is the stored form of an encrypted value
+type envelope struct {
+	// KeyURL names the key encryption key, e.g. aws-kms://arn:aws:kms:us-east-1:123456789012:key/abcd
+	KeyURL string `json:"keyURL"`
+	// EncryptedKey is the data encryption key, wrapped with the key encryption key
+	EncryptedKey []byte `json:"encryptedKey"`
+	// Nonce is the AES-GCM nonce
+	Nonce []byte `json:"nonce"`
+	// Ciphertext is the value, encrypted with the data encryption key
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// keyEncryptionProvider wraps and unwraps data encryption keys with a key encryption key
+type keyEncryptionProvider interface {
+	// WrapKey encrypts a data encryption key
+	WrapKey(dek []byte) ([]byte, error)
+	// UnwrapKey decrypts a data encryption key wrapped by WrapKey
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// providerFactories builds the keyEncryptionProvider for a key URL, by scheme
+var providerFactories = map[string]func(keyID string) (keyEncryptionProvider, error){
+	"aws-kms": newAWSKMSProvider,
+	"gcp-kms": newGCPKMSProvider,
+}
+
+var (
+	providersMutex sync.Mutex
+	providers      = make(map[string]keyEncryptionProvider)
+)
+
+// AWSKMSKey returns the AWS KMS key named by keyURL, or "" if keyURL is not an AWS KMS key.
+func AWSKMSKey(keyURL string) string {
+	if !strings.HasPrefix(keyURL, "aws-kms://") {
+		return ""
+	}
+	return strings.TrimPrefix(keyURL, "aws-kms://")
+}
+
+// IsEncrypted returns true if data is an envelope.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, envelopePrefix)
+}
+
+// Encrypt encrypts data with a new data encryption key, wrapped with the key encryption key named by keyURL.
+// If keyURL is empty, data is returned unchanged.
+func Encrypt(keyURL string, data []byte) ([]byte, error) {
+	if keyURL == "" {
+		return data, nil
+	}
+
+	provider, err := providerFor(keyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("error generating data encryption key: %v", err)
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+
+	encryptedKey, err := provider.WrapKey(dek)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data encryption key with %s: %v", keyURL, err)
+	}
+
+	e := &envelope{
+		KeyURL:       keyURL,
+		EncryptedKey: encryptedKey,
+		Nonce:        nonce,
+		Ciphertext:   gcm.Seal(nil, nonce, data, nil),
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing envelope: %v", err)
+	}
+	return append(append([]byte{}, envelopePrefix...), b...), nil
+}
+
+// Decrypt decrypts data if it is an envelope, and returns it unchanged otherwise.
+func Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	e := &envelope{}
+	if err := json.Unmarshal(data[len(envelopePrefix):], e); err != nil {
+		return nil, fmt.Errorf("error parsing envelope: %v", err)
+	}
+
+	provider, err := providerFor(e.KeyURL)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := provider.UnwrapKey(e.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data encryption key with %s: %v", e.KeyURL, err)
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, e.Nonce, e.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting envelope: %v", err)
+	}
+	return plaintext, nil
+}
+
+// providerFor returns the keyEncryptionProvider for keyURL, building it on first use.
+func providerFor(keyURL string) (keyEncryptionProvider, error) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	if provider := providers[keyURL]; provider != nil {
+		return provider, nil
+	}
+
+	tokens := strings.SplitN(keyURL, "://", 2)
+	if len(tokens) != 2 || tokens[1] == "" {
+		return nil, fmt.Errorf("invalid state encryption key %q; expected aws-kms://<key> or gcp-kms://<key>", keyURL)
+	}
+	factory := providerFactories[tokens[0]]
+	if factory == nil {
+		return nil, fmt.Errorf("unknown state encryption key type %q in %q", tokens[0], keyURL)
+	}
+	provider, err := factory(tokens[1])
+	if err != nil {
+		return nil, fmt.Errorf("error building state encryption provider for %q: %v", keyURL, err)
+	}
+	providers[keyURL] = provider
+	return provider, nil
+}
+
+func newGCM(dek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	return gcm, nil
+}
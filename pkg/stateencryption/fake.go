@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stateencryption
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// FakeKeyURL is a state encryption key for tests, which needs no cloud.
+// UseFakeKeyForTesting must be called before it is used.
+const FakeKeyURL = "fake://test"
+
+// UseFakeKeyForTesting allows the fake:// keys, such as FakeKeyURL, for the tests of code storing encrypted values.
+// The fake key is derived from the key URL, so it protects nothing and must never be used outside tests.
+func UseFakeKeyForTesting() {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	providerFactories["fake"] = newFakeProvider
+}
+
+// fakeProvider wraps keys with AES-GCM, using a key derived from its name
+type fakeProvider struct {
+	key []byte
+}
+
+var _ keyEncryptionProvider = &fakeProvider{}
+
+func newFakeProvider(name string) (keyEncryptionProvider, error) {
+	key := sha256.Sum256([]byte(name))
+	return &fakeProvider{key: key[:]}, nil
+}
+
+func (p *fakeProvider) WrapKey(dek []byte) ([]byte, error) {
+	gcm, err := newGCM(p.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+	return gcm.Seal(nonce, nonce, dek, nil), nil
+}
+
+func (p *fakeProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	gcm, err := newGCM(p.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	return gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stateencryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// gcpKMSProvider wraps keys with a GCP Cloud KMS key
+type gcpKMSProvider struct {
+	service *cloudkms.Service
+	// name is the resource name of the key, projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>
+	name string
+}
+
+var _ keyEncryptionProvider = &gcpKMSProvider{}
+
+func newGCPKMSProvider(name string) (keyEncryptionProvider, error) {
+	ctx := context.TODO()
+
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error building cloudkms client: %v", err)
+	}
+
+	return &gcpKMSProvider{
+		service: service,
+		name:    name,
+	}, nil
+}
+
+func (p *gcpKMSProvider) WrapKey(dek []byte) ([]byte, error) {
+	request := &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dek),
+	}
+	response, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(p.name, request).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Ciphertext)
+}
+
+func (p *gcpKMSProvider) UnwrapKey(wrapped []byte) ([]byte, error) {
+	request := &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+	}
+	response, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(p.name, request).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Plaintext)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Metrics exports the results of repeated cluster validations as Prometheus gauges.
+type Metrics struct {
+	success     prometheus.Gauge
+	failures    *prometheus.GaugeVec
+	nodes       *prometheus.GaugeVec
+	duration    prometheus.Gauge
+	lastRun     prometheus.Gauge
+	errorsTotal prometheus.Counter
+}
+
+// NewMetrics creates the validation metrics for a cluster and registers them with the registerer.
+func NewMetrics(clusterName string, registerer prometheus.Registerer) (*Metrics, error) {
+	constLabels := prometheus.Labels{"cluster": clusterName}
+	m := &Metrics{
+		success: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "kops_validation_success",
+			Help:        "Whether the last validation of the cluster completed without failures (1) or not (0).",
+			ConstLabels: constLabels,
+		}),
+		failures: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_failures",
+			Help:        "Number of failures found by the last validation, by kind and instance group.",
+			ConstLabels: constLabels,
+		}, []string{"kind", "instance_group"}),
+		nodes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "kops_validation_nodes",
+			Help:        "Number of nodes seen by the last validation, by role and readiness.",
+			ConstLabels: constLabels,
+		}, []string{"role", "ready"}),
+		duration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "kops_validation_duration_seconds",
+			Help:        "Time taken by the last validation.",
+			ConstLabels: constLabels,
+		}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "kops_validation_last_run_timestamp_seconds",
+			Help:        "Time at which the last validation finished, in seconds since the epoch.",
+			ConstLabels: constLabels,
+		}),
+		errorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "kops_validation_errors_total",
+			Help:        "Number of validations that could not be completed.",
+			ConstLabels: constLabels,
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.success, m.failures, m.nodes, m.duration, m.lastRun, m.errorsTotal} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Observe records the result of a validation that took the given duration.
+// If the validation could not be completed, the failures and nodes of the previous validation are kept.
+func (m *Metrics) Observe(result *ValidationCluster, duration time.Duration, err error) {
+	m.duration.Set(duration.Seconds())
+	m.lastRun.Set(float64(time.Now().Unix()))
+
+	if err != nil {
+		m.errorsTotal.Inc()
+		m.success.Set(0)
+		return
+	}
+
+	if len(result.Failures) == 0 {
+		m.success.Set(1)
+	} else {
+		m.success.Set(0)
+	}
+
+	m.failures.Reset()
+	for _, failure := range result.Failures {
+		instanceGroup := ""
+		if failure.InstanceGroup != nil {
+			instanceGroup = failure.InstanceGroup.Name
+		}
+		m.failures.WithLabelValues(failure.Kind, instanceGroup).Inc()
+	}
+
+	m.nodes.Reset()
+	for _, node := range result.Nodes {
+		ready := "false"
+		if node.Status == v1.ConditionTrue {
+			ready = "true"
+		}
+		m.nodes.WithLabelValues(node.Role, ready).Inc()
+	}
+}
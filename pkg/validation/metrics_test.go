@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+// gatherMetrics returns the value of each metric in the registry, keyed by name and the labels other than cluster.
+func gatherMetrics(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "cluster" {
+					assert.Equal(t, "testcluster.k8s.local", label.GetValue())
+					continue
+				}
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			sort.Strings(labels)
+			key := family.GetName()
+			if len(labels) != 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+			switch {
+			case metric.Gauge != nil:
+				values[key] = metric.Gauge.GetValue()
+			case metric.Counter != nil:
+				values[key] = metric.Counter.GetValue()
+			}
+		}
+	}
+	return values
+}
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics("testcluster.k8s.local", registry)
+	require.NoError(t, err)
+
+	nodes := &kopsapi.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+	metrics.Observe(&ValidationCluster{
+		Failures: []*ValidationError{
+			{Kind: "Node", Name: "node-1", InstanceGroup: nodes},
+			{Kind: "Node", Name: "node-2", InstanceGroup: nodes},
+			{Kind: "Pod", Name: "kube-system/coredns"},
+		},
+		Nodes: []*ValidationNode{
+			{Name: "master-1", Role: "master", Status: v1.ConditionTrue},
+			{Name: "node-1", Role: "node", Status: v1.ConditionFalse},
+			{Name: "node-2", Role: "node", Status: v1.ConditionFalse},
+			{Name: "node-3", Role: "node", Status: v1.ConditionTrue},
+		},
+	}, 2*time.Second, nil)
+
+	values := gatherMetrics(t, registry)
+	assert.Equal(t, 0.0, values["kops_validation_success"])
+	assert.Equal(t, 2.0, values["kops_validation_failures{instance_group=nodes,kind=Node}"])
+	assert.Equal(t, 1.0, values["kops_validation_failures{instance_group=,kind=Pod}"])
+	assert.Equal(t, 1.0, values["kops_validation_nodes{ready=true,role=master}"])
+	assert.Equal(t, 2.0, values["kops_validation_nodes{ready=false,role=node}"])
+	assert.Equal(t, 1.0, values["kops_validation_nodes{ready=true,role=node}"])
+	assert.Equal(t, 2.0, values["kops_validation_duration_seconds"])
+	assert.NotZero(t, values["kops_validation_last_run_timestamp_seconds"])
+
+	// An error keeps the results of the previous validation.
+	metrics.Observe(nil, time.Second, errors.New("testing error"))
+	values = gatherMetrics(t, registry)
+	assert.Equal(t, 1.0, values["kops_validation_errors_total"])
+	assert.Equal(t, 2.0, values["kops_validation_failures{instance_group=nodes,kind=Node}"])
+
+	// Resolved failures are no longer reported.
+	metrics.Observe(&ValidationCluster{
+		Nodes: []*ValidationNode{{Name: "master-1", Role: "master", Status: v1.ConditionTrue}},
+	}, time.Second, nil)
+	values = gatherMetrics(t, registry)
+	assert.Equal(t, 1.0, values["kops_validation_success"])
+	assert.NotContains(t, values, "kops_validation_failures{instance_group=nodes,kind=Node}")
+	assert.NotContains(t, values, "kops_validation_nodes{ready=false,role=node}")
+}
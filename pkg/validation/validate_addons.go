@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/channels/pkg/channels"
+)
+
+func init() {
+	RegisterValidator("addons", ValidatorFunc(validateAddons))
+}
+
+// validateAddons checks that the channels addons of the cluster have been applied with the
+// manifest of the current version of the cluster configuration.
+func validateAddons(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	if c.Clientset == nil {
+		return nil, nil
+	}
+
+	configBase, err := c.Clientset.ConfigBaseFor(c.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error building config base: %v", err)
+	}
+	locations := []string{configBase.Join("addons", "bootstrap-channel.yaml").Path()}
+	for _, addon := range c.Cluster.Spec.Addons {
+		locations = append(locations, addon.Manifest)
+	}
+
+	serverVersion, err := c.K8sClient.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error querying kubernetes version: %v", err)
+	}
+	kubernetesVersion, err := semver.ParseTolerant(serverVersion.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse kubernetes version %q", serverVersion.GitVersion)
+	}
+	// Remove Pre, as channels does when choosing the addons to apply
+	kubernetesVersion.Pre = nil
+
+	menu := channels.NewAddonMenu()
+	for _, location := range locations {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse addons location %q: %v", location, err)
+		}
+		addons, err := channels.LoadAddons(location, u)
+		if err != nil {
+			return nil, err
+		}
+		current, err := addons.GetCurrent(kubernetesVersion)
+		if err != nil {
+			return nil, fmt.Errorf("error processing latest versions in %q: %v", location, err)
+		}
+		menu.MergeAddons(current)
+	}
+
+	var names []string
+	for name := range menu.Addons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	applied := make(map[string]map[string]*channels.ChannelVersion)
+	var failures []*ValidationError
+	for _, name := range names {
+		addon := menu.Addons[name]
+		namespace := "kube-system"
+		if addon.Spec.Namespace != nil {
+			namespace = *addon.Spec.Namespace
+		}
+
+		if applied[namespace] == nil {
+			ns, err := c.K8sClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				applied[namespace] = make(map[string]*channels.ChannelVersion)
+			} else if err != nil {
+				return nil, fmt.Errorf("error getting namespace %q: %v", namespace, err)
+			} else {
+				applied[namespace] = channels.FindAddons(ns)
+			}
+		}
+
+		expected := addon.ChannelVersion()
+		existing := applied[namespace][name]
+		switch {
+		case existing == nil:
+			failures = append(failures, &ValidationError{
+				Kind:    "Addon",
+				Name:    name,
+				Message: fmt.Sprintf("addon %q has not been applied", name),
+			})
+		case existing.Id != expected.Id:
+			failures = append(failures, &ValidationError{
+				Kind:    "Addon",
+				Name:    name,
+				Message: fmt.Sprintf("addon %q is applied with id %q, expected %q", name, existing.Id, expected.Id),
+			})
+		case existing.ManifestHash != expected.ManifestHash:
+			failures = append(failures, &ValidationError{
+				Kind:    "Addon",
+				Name:    name,
+				Message: fmt.Sprintf("addon %q is applied with manifest hash %q, expected %q", name, existing.ManifestHash, expected.ManifestHash),
+			})
+		}
+	}
+
+	return failures, nil
+}
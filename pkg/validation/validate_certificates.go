@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func init() {
+	RegisterValidator("certificates", ValidatorFunc(validateCertificates))
+}
+
+// certificateExpiryThreshold is how long before its expiry a certificate fails validation.
+const certificateExpiryThreshold = 30 * 24 * time.Hour
+
+// validateCertificates checks that the primary certificate of each keyset in the keystore is not close to expiry.
+func validateCertificates(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	if c.Clientset == nil {
+		return nil, nil
+	}
+
+	keyStore, err := c.Clientset.KeyStore(c.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets: %v", err)
+	}
+
+	var names []string
+	for name := range keysets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []*ValidationError
+	for _, name := range names {
+		primary := keysets[name].Primary
+		if primary == nil || primary.Certificate == nil || primary.Certificate.Certificate == nil {
+			continue
+		}
+
+		notAfter := primary.Certificate.Certificate.NotAfter
+		remaining := time.Until(notAfter)
+		var message string
+		switch {
+		case remaining <= 0:
+			message = fmt.Sprintf("certificate %q expired at %s", name, notAfter.UTC().Format(time.RFC3339))
+		case remaining < certificateExpiryThreshold:
+			message = fmt.Sprintf("certificate %q expires at %s, in less than %d days", name, notAfter.UTC().Format(time.RFC3339), int(certificateExpiryThreshold.Hours()/24))
+		default:
+			continue
+		}
+		failures = append(failures, &ValidationError{
+			Kind:    "Keyset",
+			Name:    name,
+			Message: message,
+		})
+	}
+
+	return failures, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// checkHTTPClient is used for the httpGet checks.
+var checkHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// validateChecks runs the checks declared in the validation section of the cluster spec.
+func validateChecks(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	if c.Cluster.Spec.Validation == nil {
+		return nil, nil
+	}
+
+	var failures []*ValidationError
+	for i := range c.Cluster.Spec.Validation.Checks {
+		check := &c.Cluster.Spec.Validation.Checks[i]
+
+		var message string
+		var err error
+		switch {
+		case check.Workload != nil:
+			message, err = checkWorkload(ctx, c.K8sClient, check.Workload)
+		case check.HTTPGet != nil:
+			message = checkHTTPGet(ctx, check.HTTPGet)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error running check %q: %v", check.Name, err)
+		}
+
+		if message != "" {
+			failures = append(failures, &ValidationError{
+				Kind:    "Check",
+				Name:    check.Name,
+				Message: fmt.Sprintf("check %q failed: %s", check.Name, message),
+			})
+		}
+	}
+
+	return failures, nil
+}
+
+// checkWorkload returns why the replicas of a workload are not all ready, or the empty string if they are.
+func checkWorkload(ctx context.Context, client kubernetes.Interface, workload *kops.ValidationWorkloadCheck) (string, error) {
+	namespace := workload.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	id := workload.Kind + " " + namespace + "/" + workload.Name
+
+	var ready, desired int32
+	var err error
+	switch workload.Kind {
+	case "Deployment":
+		var d *appsv1.Deployment
+		d, err = client.AppsV1().Deployments(namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err == nil {
+			desired = 1
+			if d.Spec.Replicas != nil {
+				desired = *d.Spec.Replicas
+			}
+			ready = d.Status.ReadyReplicas
+		}
+	case "DaemonSet":
+		var ds *appsv1.DaemonSet
+		ds, err = client.AppsV1().DaemonSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err == nil {
+			desired = ds.Status.DesiredNumberScheduled
+			ready = ds.Status.NumberReady
+		}
+	case "StatefulSet":
+		var ss *appsv1.StatefulSet
+		ss, err = client.AppsV1().StatefulSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err == nil {
+			desired = 1
+			if ss.Spec.Replicas != nil {
+				desired = *ss.Spec.Replicas
+			}
+			ready = ss.Status.ReadyReplicas
+		}
+	default:
+		return "", fmt.Errorf("unsupported workload kind %q", workload.Kind)
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("%s not found", id), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting %s: %v", id, err)
+	}
+
+	if ready < desired {
+		return fmt.Sprintf("%s has %d of %d replicas ready", id, ready, desired), nil
+	}
+	return "", nil
+}
+
+// checkHTTPGet returns why a GET request to the URL did not succeed, or the empty string if it did.
+func checkHTTPGet(ctx context.Context, httpGet *kops.ValidationHTTPGetCheck) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpGet.URL, nil)
+	if err != nil {
+		return fmt.Sprintf("invalid url %q: %v", httpGet.URL, err)
+	}
+	resp, err := checkHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("GET %s: %v", httpGet.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Sprintf("GET %s returned status %d", httpGet.URL, resp.StatusCode)
+	}
+	return ""
+}
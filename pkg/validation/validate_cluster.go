@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/pager"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/upup/pkg/fi"
 
 	v1 "k8s.io/api/core/v1"
@@ -63,6 +64,7 @@ type clusterValidatorImpl struct {
 	instanceGroups []*kops.InstanceGroup
 	host           string
 	k8sClient      kubernetes.Interface
+	clientset      simple.Clientset
 }
 
 func (v *ValidationCluster) addError(failure *ValidationError) {
@@ -98,7 +100,9 @@ func hasPlaceHolderIP(host string) (bool, error) {
 	return false, nil
 }
 
-func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface) (ClusterValidator, error) {
+// NewClusterValidator builds a ClusterValidator. The clientset is used by the validators that
+// check the cluster against its state store; they are skipped if it is nil.
+func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface, clientset simple.Clientset) (ClusterValidator, error) {
 	var instanceGroups []*kops.InstanceGroup
 
 	for i := range instanceGroupList.Items {
@@ -116,6 +120,7 @@ func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupLis
 		instanceGroups: instanceGroups,
 		host:           host,
 		k8sClient:      k8sClient,
+		clientset:      clientset,
 	}, nil
 }
 
@@ -165,6 +170,15 @@ func (v *clusterValidatorImpl) Validate() (*ValidationCluster, error) {
 		return nil, fmt.Errorf("cannot get pod health for %q: %v", clusterName, err)
 	}
 
+	validation.runValidators(ctx, &ValidatorContext{
+		Cluster:            v.cluster,
+		Cloud:              v.cloud,
+		K8sClient:          v.k8sClient,
+		Clientset:          v.clientset,
+		ReadyNodes:         readyNodes,
+		NodeInstanceGroups: nodeInstanceGroupMapping,
+	})
+
 	return validation, nil
 }
 
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"k8s.io/kops/pkg/dns"
+)
+
+func init() {
+	RegisterValidator("dns", ValidatorFunc(validateDNS))
+}
+
+// lookupHost resolves a DNS name; it is replaced in tests.
+var lookupHost = net.LookupHost
+
+// validateDNS checks that the API DNS records that are maintained by dns-controller
+// point only at masters that are ready.
+func validateDNS(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	cluster := c.Cluster
+	if dns.IsGossipHostname(cluster.Name) {
+		return nil, nil
+	}
+
+	masterAddresses := make(map[string]bool)
+	for _, node := range c.readyMasters() {
+		for _, address := range node.Status.Addresses {
+			masterAddresses[address.Address] = true
+		}
+	}
+
+	// Names pointing at the API load balancer are not maintained by dns-controller.
+	var names []string
+	loadBalancer := cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil
+	if cluster.Spec.MasterPublicName != "" && !loadBalancer {
+		names = append(names, cluster.Spec.MasterPublicName)
+	}
+	if cluster.Spec.MasterInternalName != "" && !(loadBalancer && cluster.Spec.API.LoadBalancer.UseForInternalApi) {
+		names = append(names, cluster.Spec.MasterInternalName)
+	}
+
+	var failures []*ValidationError
+	for _, name := range names {
+		addresses, err := lookupHost(name)
+		if err != nil {
+			failures = append(failures, &ValidationError{
+				Kind:    "DNS",
+				Name:    name,
+				Message: fmt.Sprintf("unable to resolve %q: %v", name, err),
+			})
+			continue
+		}
+
+		var stale []string
+		for _, address := range addresses {
+			if !masterAddresses[address] {
+				stale = append(stale, address)
+			}
+		}
+		if len(stale) != 0 {
+			failures = append(failures, &ValidationError{
+				Kind:    "DNS",
+				Name:    name,
+				Message: fmt.Sprintf("%q resolves to %s, which is not the address of a ready master", name, strings.Join(stale, ",")),
+			})
+		}
+	}
+
+	return failures, nil
+}
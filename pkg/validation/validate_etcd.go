@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	RegisterValidator("etcd", ValidatorFunc(validateEtcd))
+}
+
+// validateEtcd checks that every member of each etcd cluster has a ready etcd-manager pod on a ready master.
+func validateEtcd(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	if len(c.Cluster.Spec.EtcdClusters) == 0 {
+		return nil, nil
+	}
+
+	masters := make(map[string]bool)
+	for _, node := range c.readyMasters() {
+		masters[node.Name] = true
+	}
+
+	var failures []*ValidationError
+	for _, etcdCluster := range c.Cluster.Spec.EtcdClusters {
+		app := "etcd-manager-" + etcdCluster.Name
+		pods, err := c.K8sClient.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=" + app})
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s pods: %v", app, err)
+		}
+
+		healthy := 0
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if masters[pod.Spec.NodeName] && isPodReady(pod) {
+				healthy++
+			}
+		}
+
+		members := len(etcdCluster.Members)
+		if healthy >= members {
+			continue
+		}
+		message := fmt.Sprintf("etcd cluster %q has %d of %d members healthy", etcdCluster.Name, healthy, members)
+		if healthy <= members/2 {
+			message += " and has lost quorum"
+		}
+		failures = append(failures, &ValidationError{
+			Kind:    "EtcdCluster",
+			Name:    etcdCluster.Name,
+			Message: message,
+		})
+	}
+
+	return failures, nil
+}
+
+// isPodReady returns whether a pod is running and has the Ready condition.
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
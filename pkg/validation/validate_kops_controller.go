@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops/model"
+)
+
+func init() {
+	RegisterValidator("kops-controller", ValidatorFunc(validateKopsController))
+}
+
+// validateKopsController checks that kops-controller is running on every master,
+// if new nodes depend on it to bootstrap.
+func validateKopsController(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	if !model.UseKopsControllerForNodeBootstrap(c.Cluster) {
+		return nil, nil
+	}
+
+	ds, err := c.K8sClient.AppsV1().DaemonSets("kube-system").Get(ctx, "kops-controller", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []*ValidationError{{
+			Kind:    "DaemonSet",
+			Name:    "kube-system/kops-controller",
+			Message: "kops-controller is not installed, so new nodes cannot bootstrap",
+		}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting kops-controller DaemonSet: %v", err)
+	}
+
+	desired := ds.Status.DesiredNumberScheduled
+	if desired == 0 || ds.Status.NumberReady < desired {
+		return []*ValidationError{{
+			Kind:    "DaemonSet",
+			Name:    "kube-system/kops-controller",
+			Message: fmt.Sprintf("kops-controller is not serving node bootstrap on all masters: %d of %d pods ready", ds.Status.NumberReady, desired),
+		}}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// Validator is a check run as part of validating a cluster, in addition to the checks of nodes and pods.
+type Validator interface {
+	// Validate returns the failures found by the check.
+	// An error is returned if the check could not be completed.
+	Validate(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error)
+}
+
+// ValidatorFunc adapts a function to the Validator interface.
+type ValidatorFunc func(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error)
+
+// Validate implements Validator.
+func (f ValidatorFunc) Validate(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+	return f(ctx, c)
+}
+
+// ValidatorContext holds the state of the cluster passed to each Validator.
+type ValidatorContext struct {
+	Cluster   *kops.Cluster
+	Cloud     fi.Cloud
+	K8sClient kubernetes.Interface
+	// Clientset is the clientset of the state store, or nil if it is not available.
+	Clientset simple.Clientset
+
+	// ReadyNodes are the nodes that are ready.
+	ReadyNodes []v1.Node
+	// NodeInstanceGroups maps the name of each node to its instance group.
+	NodeInstanceGroups map[string]*kops.InstanceGroup
+}
+
+// readyMasters returns the ready nodes that belong to a master instance group.
+func (c *ValidatorContext) readyMasters() []v1.Node {
+	var masters []v1.Node
+	for _, node := range c.ReadyNodes {
+		if ig := c.NodeInstanceGroups[node.Name]; ig != nil && ig.IsMaster() {
+			masters = append(masters, node)
+		}
+	}
+	return masters
+}
+
+// All registered validators.
+var validatorsMutex sync.Mutex
+var validators = make(map[string]Validator)
+
+// RegisterValidator registers a Validator by name. This is expected to happen during startup.
+// Registered validators are only run when enabled in the cluster spec, as they can fail for reasons
+// that should not block rolling updates, like DNS names that do not resolve from the machine running kOps.
+func RegisterValidator(name string, validator Validator) {
+	validatorsMutex.Lock()
+	defer validatorsMutex.Unlock()
+	if _, found := validators[name]; found {
+		klog.Fatalf("validator %q was registered twice", name)
+	}
+	validators[name] = validator
+}
+
+// RegisteredValidators returns the names of the registered validators.
+func RegisteredValidators() []string {
+	validatorsMutex.Lock()
+	defer validatorsMutex.Unlock()
+	var names []string
+	for name := range validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runValidators runs the registered validators that are enabled in the cluster spec, in order of name,
+// followed by the checks declared in the cluster spec.
+// A validator that cannot complete is reported as a failure, so that it does not hide the results of the others.
+func (v *ValidationCluster) runValidators(ctx context.Context, c *ValidatorContext) {
+	enabled := make(map[string]bool)
+	if c.Cluster.Spec.Validation != nil {
+		for _, name := range c.Cluster.Spec.Validation.Validators {
+			enabled[name] = true
+		}
+	}
+
+	for _, name := range RegisteredValidators() {
+		if !enabled[name] {
+			continue
+		}
+		delete(enabled, name)
+
+		validatorsMutex.Lock()
+		validator := validators[name]
+		validatorsMutex.Unlock()
+
+		failures, err := validator.Validate(ctx, c)
+		v.addValidatorResult(name, failures, err)
+	}
+
+	for name := range enabled {
+		klog.Warningf("ignoring unknown validator %q in validators", name)
+	}
+
+	failures, err := validateChecks(ctx, c)
+	v.addValidatorResult("checks", failures, err)
+}
+
+// addValidatorResult adds the failures found by a validator, or a failure if it could not complete.
+func (v *ValidationCluster) addValidatorResult(name string, failures []*ValidationError, err error) {
+	if err != nil {
+		v.addError(&ValidationError{
+			Kind:    "Validator",
+			Name:    name,
+			Message: fmt.Sprintf("validator %q failed: %v", name, err),
+		})
+		return
+	}
+	for _, failure := range failures {
+		v.addError(failure)
+	}
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// testValidatorContext builds a ValidatorContext with one ready master, master-1a, at 10.0.0.1.
+func testValidatorContext(t *testing.T, cluster *kopsapi.Cluster, objects ...runtime.Object) *ValidatorContext {
+	master := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-1a"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}},
+		},
+	}
+	ig := &kopsapi.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-1"},
+		Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleMaster},
+	}
+	if cluster.Name == "" {
+		cluster.Name = "testcluster.k8s.local"
+	}
+	return &ValidatorContext{
+		Cluster:            cluster,
+		K8sClient:          fake.NewSimpleClientset(objects...),
+		ReadyNodes:         []v1.Node{master},
+		NodeInstanceGroups: map[string]*kopsapi.InstanceGroup{master.Name: ig},
+	}
+}
+
+// testClientset returns a VFS clientset backed by memfs, with the cluster's config base set within it.
+func testClientset(t *testing.T, cluster *kopsapi.Cluster) *vfsclientset.VFSClientset {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	require.NoError(t, err)
+	cluster.Spec.ConfigBase = basePath.Join(cluster.Name).Path()
+	return vfsclientset.NewVFSClientset(basePath).(*vfsclientset.VFSClientset)
+}
+
+func failureNames(failures []*ValidationError) []string {
+	var names []string
+	for _, failure := range failures {
+		names = append(names, failure.Kind+"/"+failure.Name)
+	}
+	return names
+}
+
+func etcdManagerPod(name string, nodeName string, ready v1.ConditionStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-manager-" + name + "-" + nodeName,
+			Namespace: "kube-system",
+			Labels:    map[string]string{"k8s-app": "etcd-manager-" + name},
+		},
+		Spec: v1.PodSpec{NodeName: nodeName},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+		},
+	}
+}
+
+func TestRunValidators(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			Validation: &kopsapi.ClusterValidationSpec{
+				Validators: []string{"testing-failure", "testing-error", "testing-unknown"},
+				Checks: []kopsapi.ClusterValidationCheck{
+					{Name: "missing", Workload: &kopsapi.ValidationWorkloadCheck{Kind: "Deployment", Name: "missing"}},
+				},
+			},
+		},
+	}
+	c := testValidatorContext(t, cluster)
+
+	RegisterValidator("testing-failure", ValidatorFunc(func(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+		return []*ValidationError{{Kind: "Testing", Name: "failure", Message: "testing failure"}}, nil
+	}))
+	RegisterValidator("testing-error", ValidatorFunc(func(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+		return nil, errors.New("testing error")
+	}))
+	RegisterValidator("testing-not-enabled", ValidatorFunc(func(ctx context.Context, c *ValidatorContext) ([]*ValidationError, error) {
+		t.Errorf("validator that is not enabled was run")
+		return nil, nil
+	}))
+	defer func() {
+		validatorsMutex.Lock()
+		defer validatorsMutex.Unlock()
+		delete(validators, "testing-failure")
+		delete(validators, "testing-error")
+		delete(validators, "testing-not-enabled")
+	}()
+
+	v := &ValidationCluster{}
+	v.runValidators(context.Background(), c)
+	assert.Equal(t, []string{"Validator/testing-error", "Testing/failure", "Check/missing"}, failureNames(v.Failures))
+}
+
+func TestRunValidatorsNotEnabled(t *testing.T) {
+	c := testValidatorContext(t, &kopsapi.Cluster{})
+
+	v := &ValidationCluster{}
+	v.runValidators(context.Background(), c)
+	assert.Empty(t, v.Failures, "built-in validators are not run unless enabled")
+}
+
+func TestValidateEtcd(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			EtcdClusters: []kopsapi.EtcdClusterSpec{
+				{Name: "main", Members: []kopsapi.EtcdMemberSpec{{Name: "a"}}},
+				{Name: "events", Members: []kopsapi.EtcdMemberSpec{{Name: "a"}}},
+				{Name: "cilium", Members: []kopsapi.EtcdMemberSpec{{Name: "a"}}},
+			},
+		},
+	}
+	c := testValidatorContext(t, cluster,
+		etcdManagerPod("main", "master-1a", v1.ConditionTrue),
+		etcdManagerPod("events", "master-1a", v1.ConditionFalse),
+		// Pods on nodes that are not ready masters are not healthy members.
+		etcdManagerPod("cilium", "master-1b", v1.ConditionTrue),
+	)
+
+	failures, err := validateEtcd(context.Background(), c)
+	require.NoError(t, err)
+	if assert.Equal(t, []string{"EtcdCluster/events", "EtcdCluster/cilium"}, failureNames(failures)) {
+		assert.Equal(t, `etcd cluster "events" has 0 of 1 members healthy and has lost quorum`, failures[0].Message)
+	}
+}
+
+func TestValidateAddons(t *testing.T) {
+	cluster := &kopsapi.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "testcluster.k8s.local"}}
+	clientset := testClientset(t, cluster)
+
+	channel := `kind: Addons
+metadata:
+  name: bootstrap
+spec:
+  addons:
+  - name: current.addons.k8s.io
+    manifest: current.addons.k8s.io/k8s-1.12.yaml
+    manifestHash: "aaaa"
+    id: k8s-1.12
+  - name: outdated.addons.k8s.io
+    manifest: outdated.addons.k8s.io/k8s-1.12.yaml
+    manifestHash: "bbbb"
+    id: k8s-1.12
+  - name: missing.addons.k8s.io
+    manifest: missing.addons.k8s.io/k8s-1.12.yaml
+    manifestHash: "cccc"
+  - name: other-version.addons.k8s.io
+    manifest: other-version.addons.k8s.io/k8s-1.12.yaml
+    manifestHash: "dddd"
+    kubernetesVersion: "<1.20.0"
+`
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	require.NoError(t, err)
+	require.NoError(t, configBase.Join("addons", "bootstrap-channel.yaml").WriteFile(strings.NewReader(channel), nil))
+
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+			Annotations: map[string]string{
+				"addons.k8s.io/current.addons.k8s.io":  `{"id":"k8s-1.12","manifestHash":"aaaa"}`,
+				"addons.k8s.io/outdated.addons.k8s.io": `{"id":"k8s-1.12","manifestHash":"0000"}`,
+			},
+		},
+	}
+	c := testValidatorContext(t, cluster, namespace)
+	c.Clientset = clientset
+	c.K8sClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.21.0"}
+
+	failures, err := validateAddons(context.Background(), c)
+	require.NoError(t, err)
+	if assert.Equal(t, []string{"Addon/missing.addons.k8s.io", "Addon/outdated.addons.k8s.io"}, failureNames(failures)) {
+		assert.Equal(t, `addon "outdated.addons.k8s.io" is applied with manifest hash "0000", expected "bbbb"`, failures[1].Message)
+	}
+}
+
+func TestValidateDNS(t *testing.T) {
+	defer func(f func(string) ([]string, error)) { lookupHost = f }(lookupHost)
+	records := map[string][]string{
+		"api.testcluster.example.com":          {"10.0.0.1"},
+		"api.internal.testcluster.example.com": {"10.0.0.1", "10.0.0.2"},
+	}
+	lookupHost = func(host string) ([]string, error) {
+		if addresses, found := records[host]; found {
+			return addresses, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	cluster := &kopsapi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster.example.com"},
+		Spec: kopsapi.ClusterSpec{
+			MasterPublicName:   "api.testcluster.example.com",
+			MasterInternalName: "api.internal.testcluster.example.com",
+		},
+	}
+	failures, err := validateDNS(context.Background(), testValidatorContext(t, cluster))
+	require.NoError(t, err)
+	if assert.Equal(t, []string{"DNS/api.internal.testcluster.example.com"}, failureNames(failures)) {
+		assert.Contains(t, failures[0].Message, "10.0.0.2")
+	}
+
+	// The public name points at the load balancer, so is not checked.
+	cluster.Spec.MasterPublicName = "api.other.example.com"
+	cluster.Spec.API = &kopsapi.AccessSpec{LoadBalancer: &kopsapi.LoadBalancerAccessSpec{}}
+	records["api.internal.testcluster.example.com"] = []string{"10.0.0.1"}
+	failures, err = validateDNS(context.Background(), testValidatorContext(t, cluster))
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
+
+// keysetsClientset serves a fixed set of keysets from its keystore.
+type keysetsClientset struct {
+	*vfsclientset.VFSClientset
+	keysets map[string]*fi.Keyset
+}
+
+func (c *keysetsClientset) KeyStore(cluster *kopsapi.Cluster) (fi.CAStore, error) {
+	return &keysetsCAStore{keysets: c.keysets}, nil
+}
+
+type keysetsCAStore struct {
+	fi.CAStore
+	keysets map[string]*fi.Keyset
+}
+
+func (s *keysetsCAStore) ListKeysets() (map[string]*fi.Keyset, error) {
+	return s.keysets, nil
+}
+
+func TestValidateCertificates(t *testing.T) {
+	cluster := &kopsapi.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "testcluster.k8s.local"}}
+	clientset := &keysetsClientset{
+		VFSClientset: testClientset(t, cluster),
+		keysets:      make(map[string]*fi.Keyset),
+	}
+
+	for name, validity := range map[string]time.Duration{
+		"kubernetes-ca":   10 * 365 * 24 * time.Hour,
+		"etcd-clients-ca": 10 * 24 * time.Hour,
+	} {
+		cert, key, _, err := pki.IssueCert(&pki.IssueCertRequest{
+			Type:     "ca",
+			Subject:  pkix.Name{CommonName: name},
+			Validity: validity,
+		}, nil)
+		require.NoError(t, err)
+		keyset, err := fi.NewKeyset(cert, key)
+		require.NoError(t, err)
+		clientset.keysets[name] = keyset
+	}
+	// Keysets without a primary certificate are ignored.
+	clientset.keysets["empty"] = &fi.Keyset{}
+
+	c := testValidatorContext(t, cluster)
+	c.Clientset = clientset
+	failures, err := validateCertificates(context.Background(), c)
+	require.NoError(t, err)
+	if assert.Equal(t, []string{"Keyset/etcd-clients-ca"}, failureNames(failures)) {
+		assert.Contains(t, failures[0].Message, "in less than 30 days")
+	}
+}
+
+func TestValidateKopsController(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.21.0",
+		},
+	}
+
+	failures, err := validateKopsController(context.Background(), testValidatorContext(t, cluster))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DaemonSet/kube-system/kops-controller"}, failureNames(failures))
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "kops-controller", Namespace: "kube-system"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+	}
+	failures, err = validateKopsController(context.Background(), testValidatorContext(t, cluster, ds))
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	ds.Status.NumberReady = 2
+	failures, err = validateKopsController(context.Background(), testValidatorContext(t, cluster, ds))
+	require.NoError(t, err)
+	if assert.Len(t, failures, 1) {
+		assert.Contains(t, failures[0].Message, "2 of 3 pods ready")
+	}
+}
+
+func TestValidateChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			Validation: &kopsapi.ClusterValidationSpec{
+				Checks: []kopsapi.ClusterValidationCheck{
+					{Name: "web", Workload: &kopsapi.ValidationWorkloadCheck{Kind: "Deployment", Name: "web"}},
+					{Name: "db", Workload: &kopsapi.ValidationWorkloadCheck{Kind: "StatefulSet", Namespace: "db", Name: "db"}},
+					{Name: "missing", Workload: &kopsapi.ValidationWorkloadCheck{Kind: "DaemonSet", Name: "missing"}},
+					{Name: "healthz", HTTPGet: &kopsapi.ValidationHTTPGetCheck{URL: server.URL + "/healthz"}},
+					{Name: "unavailable", HTTPGet: &kopsapi.ValidationHTTPGetCheck{URL: server.URL + "/unavailable"}},
+				},
+			},
+		},
+	}
+	replicas := int32(3)
+	c := testValidatorContext(t, cluster,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 3},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "db"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+		},
+	)
+
+	failures, err := validateChecks(context.Background(), c)
+	require.NoError(t, err)
+	if assert.Equal(t, []string{"Check/db", "Check/missing", "Check/unavailable"}, failureNames(failures)) {
+		assert.Equal(t, `check "db" failed: StatefulSet db/db has 1 of 3 replicas ready`, failures[0].Message)
+		assert.Equal(t, `check "missing" failed: DaemonSet default/missing not found`, failures[1].Message)
+		assert.Contains(t, failures[2].Message, "returned status 503")
+	}
+}
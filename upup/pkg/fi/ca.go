@@ -33,6 +33,9 @@ import (
 
 const CertificateIDCA = "kubernetes-ca"
 
+// CertificateIDUsersCA is the keyset signing the client certificates issued to users.
+const CertificateIDUsersCA = "users-ca"
+
 const (
 	// SecretNameSSHPrimary is the Name for the primary SSH key
 	SecretNameSSHPrimary = "admin"
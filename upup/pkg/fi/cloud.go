@@ -54,6 +54,13 @@ type Cloud interface {
 	GetApiIngressStatus(cluster *kops.Cluster) ([]ApiIngressStatus, error)
 }
 
+// InstanceGroupRollbacker is implemented by clouds that can roll back the instance specification of a group.
+type InstanceGroupRollbacker interface {
+	// RollbackGroup causes the group to create new instances from the specification
+	// (such as launch template version or instance template) that its NeedUpdate instances were created from.
+	RollbackGroup(group *cloudinstances.CloudInstanceGroup) error
+}
+
 type VPCInfo struct {
 	// CIDR is the IP address range for the VPC
 	CIDR string
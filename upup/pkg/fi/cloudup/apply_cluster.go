@@ -67,6 +67,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
@@ -120,6 +121,9 @@ type ApplyClusterCmd struct {
 	// DryRun is true if this is only a dry run
 	DryRun bool
 
+	// DryRunOut is where the report of a dry run is printed, if not os.Stdout
+	DryRunOut io.Writer
+
 	// AllowKopsDowngrade permits applying with a kops version older than what was last used to apply to the cluster.
 	AllowKopsDowngrade bool
 
@@ -137,9 +141,21 @@ type ApplyClusterCmd struct {
 	// that is re-mapped.
 	LifecycleOverrides map[string]fi.Lifecycle
 
+	// PhaseLifecycles overrides the lifecycle of all the tasks of a phase, after Phase has been applied.
+	// It is used to apply some phases only, e.g. by the auto-apply controller. If set, the tasks
+	// of the phases that are only checked do not delete the extra objects they find.
+	PhaseLifecycles map[Phase]fi.Lifecycle
+
 	// GetAssets is whether this is called just to obtain the list of assets.
 	GetAssets bool
 
+	// EmitImports is whether the terraform target writes a script importing the existing cloud resources.
+	EmitImports bool
+
+	// Plan is a plan made by an earlier dry run. If set, the tasks are first run against a
+	// dry-run target and are only applied if they would make the changes of the plan.
+	Plan *fi.Plan
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
 
@@ -162,6 +178,25 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		c.InstanceGroups = instanceGroups
 	}
 
+	// Hash the specs before they are populated, so the hash matches the specs in the state store
+	specHash, err := SpecHash(c.Cluster, c.InstanceGroups)
+	if err != nil {
+		return err
+	}
+
+	err = c.run(ctx)
+
+	// Only a direct apply changes the cluster; the other targets leave that to the user
+	if c.TargetName == TargetDirect && !c.GetAssets {
+		if recordErr := c.recordStatus(ctx, specHash, err); recordErr != nil {
+			klog.Warningf("unable to record the cluster status: %v", recordErr)
+		}
+	}
+
+	return err
+}
+
+func (c *ApplyClusterCmd) run(ctx context.Context) error {
 	for _, ig := range c.InstanceGroups {
 		// Try to guess the path for additional third party volume plugins in Flatcar
 		image := strings.ToLower(ig.Spec.Image)
@@ -209,6 +244,15 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	default:
 		return fmt.Errorf("unknown phase %q", c.Phase)
 	}
+	if lifecycle, found := c.PhaseLifecycles[PhaseNetwork]; found {
+		networkLifecycle = lifecycle
+	}
+	if lifecycle, found := c.PhaseLifecycles[PhaseSecurity]; found {
+		securityLifecycle = lifecycle
+	}
+	if lifecycle, found := c.PhaseLifecycles[PhaseCluster]; found {
+		clusterLifecycle = lifecycle
+	}
 	if c.GetAssets {
 		networkLifecycle = fi.LifecycleIgnore
 		securityLifecycle = fi.LifecycleIgnore
@@ -695,6 +739,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		checkExisting = false
 		outDir := c.OutDir
 		tf := terraform.NewTerraformTarget(cloud, project, outDir, cluster.Spec.Target)
+		tf.EmitImports = c.EmitImports
 
 		// We include a few "util" variables in the TF output
 		if err := tf.AddOutputVariable("region", terraformWriter.LiteralFromStringValue(cloud.Region())); err != nil {
@@ -711,6 +756,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			return err
 		}
 
+		if tf.IsModule() {
+			addTerraformModuleVariables(tf, modelContext)
+		}
+
 		target = tf
 
 		// Can cause conflicts with terraform management
@@ -732,8 +781,22 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		fmt.Printf("%s\n", starline)
 		fmt.Printf("\n")
 
+	case TargetPulumi:
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+			return fmt.Errorf("pulumi target is only supported with CloudProvider:%q", kops.CloudProviderAWS)
+		}
+		checkExisting = false
+		outDir := c.OutDir
+		target = pulumi.NewPulumiTarget(cloud, cluster.ObjectMeta.Name, outDir)
+
+		// Can cause conflicts with pulumi management
+		shouldPrecreateDNS = false
+
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOut != nil {
+			out = c.DryRunOut
+		}
 		if c.GetAssets {
 			out = io.Discard
 		}
@@ -754,12 +817,6 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 	}
 
-	context, err := fi.NewContext(target, cluster, cloud, keyStore, secretStore, configBase, checkExisting, c.TaskMap)
-	if err != nil {
-		return fmt.Errorf("error building context: %v", err)
-	}
-	defer context.Close()
-
 	var options fi.RunTasksOptions
 	if c.RunTasksOptions != nil {
 		options = *c.RunTasksOptions
@@ -767,6 +824,33 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		options.InitDefaults()
 	}
 
+	if _, isDryRun := target.(*fi.DryRunTarget); c.Plan != nil && !isDryRun {
+		// Check the very tasks we are about to apply, so the changes made are the planned ones
+		klog.Infof("Checking that the cluster has not drifted since the plan was made")
+		dryRunTarget := fi.NewDryRunTarget(assetBuilder, io.Discard)
+		dryRunContext, err := fi.NewContext(dryRunTarget, cluster, cloud, keyStore, secretStore, configBase, true, c.TaskMap)
+		if err != nil {
+			return fmt.Errorf("error building context: %v", err)
+		}
+		defer dryRunContext.Close()
+		dryRunContext.KeepCheckedDeletions = len(c.PhaseLifecycles) != 0
+
+		if err := dryRunContext.RunTasks(options); err != nil {
+			return fmt.Errorf("error running tasks: %v", err)
+		}
+		if err := dryRunTarget.VerifyPlan(c.Plan, cluster.ObjectMeta.Name, c.TaskMap); err != nil {
+			return err
+		}
+	}
+
+	context, err := fi.NewContext(target, cluster, cloud, keyStore, secretStore, configBase, checkExisting, c.TaskMap)
+	if err != nil {
+		return fmt.Errorf("error building context: %v", err)
+	}
+	defer context.Close()
+	// The phases that are only checked must not delete the extra objects of their tasks
+	context.KeepCheckedDeletions = len(c.PhaseLifecycles) != 0
+
 	err = context.RunTasks(options)
 	if err != nil {
 		return fmt.Errorf("error running tasks: %v", err)
@@ -1324,6 +1408,11 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 				return nil, nil, err
 			}
 		}
+		if caTasks[fi.CertificateIDUsersCA] != nil {
+			if err := getTasksCertificate(caTasks, fi.CertificateIDUsersCA, config); err != nil {
+				return nil, nil, err
+			}
+		}
 
 		config.APIServerConfig.EncryptionConfigSecretHash = n.encryptionConfigSecretHash
 		var err error
@@ -1490,3 +1579,25 @@ func (n *nodeUpConfigBuilder) buildWarmPoolImages(ig *kops.InstanceGroup) []stri
 
 	return unique
 }
+
+// addTerraformModuleVariables makes the values a cluster shares with other terraform configurations input variables
+// of the terraform module written for the cluster, along with the instance types of the instance groups.
+func addTerraformModuleVariables(tf *terraform.TerraformTarget, modelContext *model.KopsModelContext) {
+	cluster := modelContext.Cluster
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return
+	}
+
+	if cluster.SharedVPC() {
+		tf.AddModuleVariable(terraform.ModuleVariableVPCID, "", cluster.Spec.NetworkID)
+	}
+	for _, subnet := range cluster.Spec.Subnets {
+		if subnet.ProviderID != "" {
+			tf.AddModuleVariable(terraform.ModuleVariableSubnetIDs, subnet.Name, subnet.ProviderID)
+		}
+	}
+	for _, ig := range modelContext.InstanceGroups {
+		launchTemplate := terraformWriter.LiteralProperty("aws_launch_template", modelContext.AutoscalingGroupName(ig), "instance_type")
+		tf.AddModuleAttributeVariable(terraform.ModuleVariableInstanceTypes, ig.ObjectMeta.Name, launchTemplate)
+	}
+}
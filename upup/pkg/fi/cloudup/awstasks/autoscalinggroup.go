@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/util/pkg/maps"
@@ -986,6 +987,14 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 	return t.RenderResource("aws_autoscaling_group", *e.Name, tf)
 }
 
+func (e *AutoscalingGroup) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*AutoscalingGroup)
+	if a.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_autoscaling_group", ResourceName: *e.Name, ID: *a.Name}
+}
+
 // TerraformLink fills in the property
 func (e *AutoscalingGroup) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_autoscaling_group", fi.StringValue(e.Name), "id")
@@ -1129,3 +1138,149 @@ func (_ *AutoscalingGroup) RenderCloudformation(t *cloudformation.Cloudformation
 func (e *AutoscalingGroup) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::AutoScaling::AutoScalingGroup", fi.StringValue(e.Name))
 }
+
+type pulumiASGTag struct {
+	Key               *string `json:"key"`
+	Value             *string `json:"value"`
+	PropagateAtLaunch *bool   `json:"propagateAtLaunch"`
+}
+
+type pulumiAutoscalingLaunchTemplateSpecification struct {
+	// LaunchTemplateID is the ID of the template to use.
+	LaunchTemplateID *pulumi.Literal `json:"id,omitempty"`
+	// Version is the version of the Launch Template to use.
+	Version *pulumi.Literal `json:"version,omitempty"`
+}
+
+type pulumiAutoscalingMixedInstancesPolicyLaunchTemplateSpecification struct {
+	// LaunchTemplateID is the ID of the template to use
+	LaunchTemplateID *pulumi.Literal `json:"launchTemplateId,omitempty"`
+	// Version is the version of the Launch Template to use
+	Version *pulumi.Literal `json:"version,omitempty"`
+}
+
+type pulumiAutoscalingMixedInstancesPolicyLaunchTemplateOverride struct {
+	// InstanceType is the instance to use
+	InstanceType *string `json:"instanceType,omitempty"`
+}
+
+type pulumiAutoscalingMixedInstancesPolicyLaunchTemplate struct {
+	// LaunchTemplateSpecification is the definition for a LT
+	LaunchTemplateSpecification *pulumiAutoscalingMixedInstancesPolicyLaunchTemplateSpecification `json:"launchTemplateSpecification,omitempty"`
+	// Overrides the is machine type override
+	Overrides []*pulumiAutoscalingMixedInstancesPolicyLaunchTemplateOverride `json:"overrides,omitempty"`
+}
+
+type pulumiAutoscalingInstanceDistribution struct {
+	// OnDemandAllocationStrategy
+	OnDemandAllocationStrategy *string `json:"onDemandAllocationStrategy,omitempty"`
+	// OnDemandBaseCapacity is the base ondemand requirement
+	OnDemandBaseCapacity *int64 `json:"onDemandBaseCapacity,omitempty"`
+	// OnDemandPercentageAboveBaseCapacity is the percentage above base for on-demand instances
+	OnDemandPercentageAboveBaseCapacity *int64 `json:"onDemandPercentageAboveBaseCapacity,omitempty"`
+	// SpotAllocationStrategy is the spot allocation stratergy
+	SpotAllocationStrategy *string `json:"spotAllocationStrategy,omitempty"`
+	// SpotInstancePools is the number of pools
+	SpotInstancePools *int64 `json:"spotInstancePools,omitempty"`
+	// SpotMaxPrice is the max bid on spot instance, defaults to demand value
+	SpotMaxPrice *string `json:"spotMaxPrice,omitempty"`
+}
+
+type pulumiMixedInstancesPolicy struct {
+	// LaunchTemplate is the launch template spec
+	LaunchTemplate *pulumiAutoscalingMixedInstancesPolicyLaunchTemplate `json:"launchTemplate,omitempty"`
+	// InstanceDistribution is the distribution strategy
+	InstanceDistribution *pulumiAutoscalingInstanceDistribution `json:"instancesDistribution,omitempty"`
+}
+
+type pulumiAutoscalingGroup struct {
+	Name                 *string                                       `json:"name,omitempty"`
+	LaunchTemplate       *pulumiAutoscalingLaunchTemplateSpecification `json:"launchTemplate,omitempty"`
+	MaxSize              *int64                                        `json:"maxSize,omitempty"`
+	MinSize              *int64                                        `json:"minSize,omitempty"`
+	MixedInstancesPolicy *pulumiMixedInstancesPolicy                   `json:"mixedInstancesPolicy,omitempty"`
+	VPCZoneIdentifiers   []*pulumi.Literal                             `json:"vpcZoneIdentifiers,omitempty"`
+	Tags                 []*pulumiASGTag                               `json:"tags,omitempty"`
+	MetricsGranularity   *string                                       `json:"metricsGranularity,omitempty"`
+	EnabledMetrics       []*string                                     `json:"enabledMetrics,omitempty"`
+	SuspendedProcesses   []*string                                     `json:"suspendedProcesses,omitempty"`
+	InstanceProtection   *bool                                         `json:"protectFromScaleIn,omitempty"`
+	LoadBalancers        []*pulumi.Literal                             `json:"loadBalancers,omitempty"`
+	TargetGroupARNs      []*pulumi.Literal                             `json:"targetGroupArns,omitempty"`
+}
+
+// RenderPulumi is responsible for rendering the pulumi resource
+func (_ *AutoscalingGroup) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *AutoscalingGroup) error {
+	p := &pulumiAutoscalingGroup{
+		Name:               e.Name,
+		MinSize:            e.MinSize,
+		MaxSize:            e.MaxSize,
+		MetricsGranularity: e.Granularity,
+		EnabledMetrics:     aws.StringSlice(e.Metrics),
+		InstanceProtection: e.InstanceProtection,
+	}
+
+	for _, s := range e.Subnets {
+		p.VPCZoneIdentifiers = append(p.VPCZoneIdentifiers, s.PulumiLink())
+	}
+
+	for _, k := range maps.SortedKeys(e.Tags) {
+		v := e.Tags[k]
+		p.Tags = append(p.Tags, &pulumiASGTag{
+			Key:               fi.String(k),
+			Value:             fi.String(v),
+			PropagateAtLaunch: fi.Bool(true),
+		})
+	}
+
+	for _, k := range e.LoadBalancers {
+		p.LoadBalancers = append(p.LoadBalancers, k.PulumiLink())
+	}
+
+	for _, tg := range e.TargetGroups {
+		p.TargetGroupARNs = append(p.TargetGroupARNs, tg.PulumiLink())
+	}
+
+	if e.UseMixedInstancesPolicy() {
+		p.MixedInstancesPolicy = &pulumiMixedInstancesPolicy{
+			LaunchTemplate: &pulumiAutoscalingMixedInstancesPolicyLaunchTemplate{
+				LaunchTemplateSpecification: &pulumiAutoscalingMixedInstancesPolicyLaunchTemplateSpecification{
+					LaunchTemplateID: e.LaunchTemplate.PulumiLink(),
+					Version:          e.LaunchTemplate.PulumiVersionLink(),
+				},
+			},
+			InstanceDistribution: &pulumiAutoscalingInstanceDistribution{
+				OnDemandAllocationStrategy:          e.MixedOnDemandAllocationStrategy,
+				OnDemandBaseCapacity:                e.MixedOnDemandBase,
+				OnDemandPercentageAboveBaseCapacity: e.MixedOnDemandAboveBase,
+				SpotAllocationStrategy:              e.MixedSpotAllocationStrategy,
+				SpotInstancePools:                   e.MixedSpotInstancePools,
+				SpotMaxPrice:                        e.MixedSpotMaxPrice,
+			},
+		}
+
+		for _, x := range e.MixedInstanceOverrides {
+			p.MixedInstancesPolicy.LaunchTemplate.Overrides = append(p.MixedInstancesPolicy.LaunchTemplate.Overrides, &pulumiAutoscalingMixedInstancesPolicyLaunchTemplateOverride{InstanceType: fi.String(x)})
+		}
+	} else if e.LaunchTemplate != nil {
+		p.LaunchTemplate = &pulumiAutoscalingLaunchTemplateSpecification{
+			LaunchTemplateID: e.LaunchTemplate.PulumiLink(),
+			Version:          e.LaunchTemplate.PulumiVersionLink(),
+		}
+	} else {
+		return fmt.Errorf("could not find one of launch configuration, mixed instances policy, or launch template")
+	}
+
+	if e.SuspendProcesses != nil {
+		for _, process := range *e.SuspendProcesses {
+			p.SuspendedProcesses = append(p.SuspendedProcesses, fi.String(process))
+		}
+	}
+
+	return t.RenderResource("aws:autoscaling:Group", fi.StringValue(e.Name), p)
+}
+
+// PulumiLink fills in the property
+func (e *AutoscalingGroup) PulumiLink() *pulumi.Literal {
+	return pulumi.LiteralID("aws:autoscaling:Group", fi.StringValue(e.Name))
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -140,6 +141,14 @@ func (_ *AutoscalingLifecycleHook) RenderTerraform(t *terraform.TerraformTarget,
 	return t.RenderResource("aws_autoscaling_lifecycle_hook", *e.Name, tf)
 }
 
+func (e *AutoscalingLifecycleHook) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*AutoscalingLifecycleHook)
+	if a.AutoscalingGroup == nil || a.AutoscalingGroup.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_autoscaling_lifecycle_hook", ResourceName: *e.Name, ID: *a.AutoscalingGroup.Name + "/" + fi.StringValue(a.GetHookName())}
+}
+
 type cloudformationASGLifecycleHook struct {
 	LifecycleHookName    *string
 	AutoScalingGroupName *cloudformation.Literal
@@ -160,6 +169,26 @@ func (_ *AutoscalingLifecycleHook) RenderCloudformation(t *cloudformation.Cloudf
 	return t.RenderResource("AWS::AutoScaling::LifecycleHook", *e.Name, tf)
 }
 
+type pulumiASGLifecycleHook struct {
+	Name                 *string         `json:"name"`
+	AutoScalingGroupName *pulumi.Literal `json:"autoscalingGroupName"`
+	DefaultResult        *string         `json:"defaultResult,omitempty"`
+	HeartbeatTimeout     *int64          `json:"heartbeatTimeout,omitempty"`
+	LifecycleTransition  *string         `json:"lifecycleTransition"`
+}
+
+func (_ *AutoscalingLifecycleHook) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *AutoscalingLifecycleHook) error {
+	p := &pulumiASGLifecycleHook{
+		Name:                 e.GetHookName(),
+		AutoScalingGroupName: e.AutoscalingGroup.PulumiLink(),
+		DefaultResult:        e.DefaultResult,
+		HeartbeatTimeout:     e.HeartbeatTimeout,
+		LifecycleTransition:  e.LifecycleTransition,
+	}
+
+	return t.RenderResource("aws:autoscaling:LifecycleHook", *e.Name, p)
+}
+
 func (h *AutoscalingLifecycleHook) GetHookName() *string {
 	if h.HookName != nil {
 		return h.HookName
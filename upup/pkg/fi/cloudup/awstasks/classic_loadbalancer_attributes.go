@@ -51,6 +51,13 @@ type cloudformationClassicLoadBalancerAccessLog struct {
 	S3BucketPrefix *string `json:"S3BucketPrefix,omitempty"`
 }
 
+type pulumiLoadBalancerAccessLog struct {
+	EmitInterval   *int64  `json:"interval,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
+	S3BucketName   *string `json:"bucket,omitempty"`
+	S3BucketPrefix *string `json:"bucketPrefix,omitempty"`
+}
+
 //type LoadBalancerAdditionalAttribute struct {
 //	Key   *string
 //	Value *string
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -51,6 +52,7 @@ type DNSTarget interface {
 	CloudformationAttrDNSName() *cloudformation.Literal
 	CloudformationAttrCanonicalHostedZoneNameID() *cloudformation.Literal
 	TerraformLink(...string) *terraformWriter.Literal
+	PulumiLink(...string) *pulumi.Literal
 }
 
 func (e *DNSName) Find(c *fi.Context) (*DNSName, error) {
@@ -287,6 +289,16 @@ func (_ *DNSName) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *D
 	return t.RenderResource("aws_route53_record", *e.Name, tf)
 }
 
+func (e *DNSName) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*DNSName)
+	if a.Zone == nil || a.Zone.ZoneID == nil {
+		return nil
+	}
+	zoneID := strings.TrimPrefix(*a.Zone.ZoneID, "/hostedzone/")
+	name := strings.TrimSuffix(fi.StringValue(a.ResourceName), ".")
+	return &terraformWriter.Import{ResourceType: "aws_route53_record", ResourceName: *e.Name, ID: zoneID + "_" + name + "_" + fi.StringValue(a.ResourceType)}
+}
+
 func (e *DNSName) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_route53_record", *e.Name)
 }
@@ -328,3 +340,39 @@ func (_ *DNSName) RenderCloudformation(t *cloudformation.CloudformationTarget, a
 func (e *DNSName) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::Route53::RecordSet", *e.Name)
 }
+
+type pulumiRoute53Record struct {
+	Name    *string  `json:"name"`
+	Type    *string  `json:"type"`
+	TTL     *string  `json:"ttl,omitempty"`
+	Records []string `json:"records,omitempty"`
+
+	Aliases []*pulumiAlias  `json:"aliases,omitempty"`
+	ZoneID  *pulumi.Literal `json:"zoneId"`
+}
+
+type pulumiAlias struct {
+	Name                 *pulumi.Literal `json:"name"`
+	ZoneID               *pulumi.Literal `json:"zoneId"`
+	EvaluateTargetHealth *bool           `json:"evaluateTargetHealth"`
+}
+
+func (_ *DNSName) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *DNSName) error {
+	p := &pulumiRoute53Record{
+		Name:   e.ResourceName,
+		ZoneID: e.Zone.PulumiLink(),
+		Type:   e.ResourceType,
+	}
+
+	if e.TargetLoadBalancer != nil {
+		p.Aliases = []*pulumiAlias{
+			{
+				Name:                 e.TargetLoadBalancer.PulumiLink("dnsName"),
+				EvaluateTargetHealth: aws.Bool(false),
+				ZoneID:               e.TargetLoadBalancer.PulumiLink("zoneId"),
+			},
+		}
+	}
+
+	return t.RenderResource("aws:route53:Record", *e.Name, p)
+}
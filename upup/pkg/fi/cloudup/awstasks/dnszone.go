@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -351,3 +352,68 @@ func (e *DNSZone) CloudformationLink() *cloudformation.Literal {
 
 	return cloudformation.Ref("AWS::Route53::HostedZone", *e.Name)
 }
+
+type pulumiRoute53ZoneAssociation struct {
+	ZoneID *pulumi.Literal `json:"zoneId"`
+	VPCID  *pulumi.Literal `json:"vpcId"`
+}
+
+func (_ *DNSZone) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *DNSZone) error {
+	cloud := t.Cloud.(awsup.AWSCloud)
+
+	dnsName := fi.StringValue(e.DNSName)
+
+	// As with terraform, we expect the zone to exist already
+	klog.Infof("Check for existing route53 zone to re-use with name %q", dnsName)
+	z, err := e.findExisting(cloud)
+	if err != nil {
+		return err
+	}
+
+	if z != nil {
+		klog.Infof("Existing zone %q found; will configure pulumi to reuse", aws.StringValue(z.HostedZone.Name))
+
+		e.ZoneID = z.HostedZone.Id
+
+		// If the user specifies dns=private we'll have a non-nil PrivateVPC that specifies the VPC
+		// that should used with the private Route53 zone. If the zone doesn't already know about the
+		// VPC, we add that association.
+		if e.PrivateVPC != nil {
+			assocNeeded := true
+			var vpcName string
+			if e.PrivateVPC.ID != nil {
+				vpcName = *e.PrivateVPC.ID
+				for _, vpc := range z.VPCs {
+					if *vpc.VPCId == vpcName {
+						klog.Infof("VPC %q already associated with zone %q", vpcName, aws.StringValue(z.HostedZone.Name))
+						assocNeeded = false
+					}
+				}
+			} else {
+				vpcName = *e.PrivateVPC.Name
+			}
+
+			if assocNeeded {
+				klog.Infof("No association between VPC %q and zone %q; adding", vpcName, aws.StringValue(z.HostedZone.Name))
+				p := &pulumiRoute53ZoneAssociation{
+					ZoneID: pulumi.LiteralString(*e.ZoneID),
+					VPCID:  e.PrivateVPC.PulumiLink(),
+				}
+				return t.RenderResource("aws:route53:ZoneAssociation", *e.Name, p)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Creation of Route53 hosted zones is not supported for pulumi")
+}
+
+func (e *DNSZone) PulumiLink() *pulumi.Literal {
+	if e.ZoneID != nil {
+		klog.V(4).Infof("reusing existing route53 zone with id %q", *e.ZoneID)
+		return pulumi.LiteralString(*e.ZoneID)
+	}
+
+	return pulumi.LiteralID("aws:route53:Zone", *e.Name)
+}
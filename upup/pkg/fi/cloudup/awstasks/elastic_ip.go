@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -293,6 +294,14 @@ func (_ *ElasticIP) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_eip", *e.Name, tf)
 }
 
+func (e *ElasticIP) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*ElasticIP)
+	if fi.BoolValue(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_eip", ResourceName: *e.Name, ID: *a.ID}
+}
+
 func (e *ElasticIP) TerraformLink() *terraformWriter.Literal {
 	if fi.BoolValue(e.Shared) {
 		if e.ID == nil {
@@ -341,3 +350,37 @@ func (e *ElasticIP) CloudformationAllocationID() *cloudformation.Literal {
 
 	return cloudformation.GetAtt("AWS::EC2::EIP", *e.Name, "AllocationId")
 }
+
+type pulumiElasticIP struct {
+	Vpc  *bool             `json:"vpc"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *ElasticIP) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *ElasticIP) error {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			return fmt.Errorf("ID must be set, if ElasticIP is shared: %v", e)
+		}
+		klog.V(4).Infof("reusing existing ElasticIP with id %q", aws.StringValue(e.ID))
+		return nil
+	}
+
+	p := &pulumiElasticIP{
+		Vpc:  aws.Bool(true),
+		Tags: e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:Eip", *e.Name, p)
+}
+
+// PulumiLink returns the allocation ID of the elastic IP
+func (e *ElasticIP) PulumiLink() *pulumi.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if ElasticIP is shared: %v", e)
+		}
+		return pulumi.LiteralString(*e.ID)
+	}
+
+	return pulumi.LiteralID("aws:ec2:Eip", *e.Name)
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -147,6 +148,14 @@ func (_ *EventBridgeRule) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_cloudwatch_event_rule", *e.Name, tf)
 }
 
+func (e *EventBridgeRule) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*EventBridgeRule)
+	if a.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_cloudwatch_event_rule", ResourceName: *e.Name, ID: *a.Name}
+}
+
 func (eb *EventBridgeRule) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_cloudwatch_event_rule", fi.StringValue(eb.Name), "id")
 }
@@ -187,3 +196,23 @@ func (_ *EventBridgeRule) RenderCloudformation(t *cloudformation.CloudformationT
 
 	return t.RenderResource("AWS::Events::Rule", *e.Name, cf)
 }
+
+type pulumiEventBridgeRule struct {
+	Name         *string           `json:"name"`
+	EventPattern *pulumi.Literal   `json:"eventPattern"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *EventBridgeRule) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *EventBridgeRule) error {
+	p := &pulumiEventBridgeRule{
+		Name:         e.Name,
+		EventPattern: pulumi.LiteralString(*e.EventPattern),
+		Tags:         e.Tags,
+	}
+
+	return t.RenderResource("aws:cloudwatch:EventRule", *e.Name, p)
+}
+
+func (eb *EventBridgeRule) PulumiLink() *pulumi.Literal {
+	return pulumi.LiteralProperty("aws:cloudwatch:EventRule", fi.StringValue(eb.Name), "name")
+}
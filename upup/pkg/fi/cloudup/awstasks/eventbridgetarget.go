@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -141,7 +142,29 @@ func (_ *EventBridgeTarget) RenderTerraform(t *terraform.TerraformTarget, a, e,
 	return t.RenderResource("aws_cloudwatch_event_target", *e.Name, tf)
 }
 
+func (e *EventBridgeTarget) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*EventBridgeTarget)
+	if a.ID == nil || a.Rule == nil || a.Rule.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_cloudwatch_event_target", ResourceName: *e.Name, ID: *a.Rule.Name + "/" + *a.ID}
+}
+
 func (_ *EventBridgeTarget) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *EventBridgeTarget) error {
 	// There is no Cloudformation EventBridge Target resource. Instead it's included in Cloudformation's EventBridge Rule resource
 	return nil
 }
+
+type pulumiEventBridgeTarget struct {
+	RuleName  *pulumi.Literal `json:"rule"`
+	TargetArn *string         `json:"arn"`
+}
+
+func (_ *EventBridgeTarget) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *EventBridgeTarget) error {
+	p := &pulumiEventBridgeTarget{
+		RuleName:  e.Rule.PulumiLink(),
+		TargetArn: e.TargetArn,
+	}
+
+	return t.RenderResource("aws:cloudwatch:EventTarget", *e.Name, p)
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -126,6 +127,14 @@ func (_ *IAMInstanceProfileRole) RenderTerraform(t *terraform.TerraformTarget, a
 	return t.RenderResource("aws_iam_instance_profile", *e.InstanceProfile.Name, tf)
 }
 
+func (e *IAMInstanceProfileRole) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*IAMInstanceProfileRole)
+	if fi.BoolValue(e.InstanceProfile.Shared) || a.InstanceProfile == nil || a.InstanceProfile.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_iam_instance_profile", ResourceName: *e.InstanceProfile.Name, ID: *a.InstanceProfile.Name}
+}
+
 type cloudformationIAMInstanceProfile struct {
 	InstanceProfileName *string                   `json:"InstanceProfileName"`
 	Roles               []*cloudformation.Literal `json:"Roles"`
@@ -140,3 +149,19 @@ func (_ *IAMInstanceProfileRole) RenderCloudformation(t *cloudformation.Cloudfor
 
 	return t.RenderResource("AWS::IAM::InstanceProfile", *e.InstanceProfile.Name, cf)
 }
+
+type pulumiIAMInstanceProfile struct {
+	Name *string           `json:"name"`
+	Role *pulumi.Literal   `json:"role"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *IAMInstanceProfileRole) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *IAMInstanceProfileRole) error {
+	p := &pulumiIAMInstanceProfile{
+		Name: e.InstanceProfile.Name,
+		Role: e.Role.PulumiLink(),
+		Tags: e.InstanceProfile.Tags,
+	}
+
+	return t.RenderResource("aws:iam:InstanceProfile", *e.InstanceProfile.Name, p)
+}
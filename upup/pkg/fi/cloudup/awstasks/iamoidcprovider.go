@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -203,6 +204,14 @@ func (p *IAMOIDCProvider) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_iam_openid_connect_provider", *e.Name, tf)
 }
 
+func (e *IAMOIDCProvider) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*IAMOIDCProvider)
+	if a.arn == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_iam_openid_connect_provider", ResourceName: *e.Name, ID: *a.arn}
+}
+
 func (e *IAMOIDCProvider) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_iam_openid_connect_provider", *e.Name, "arn")
 }
@@ -210,3 +219,25 @@ func (e *IAMOIDCProvider) TerraformLink() *terraformWriter.Literal {
 func (_ *IAMOIDCProvider) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMOIDCProvider) error {
 	return errors.New("cloudformation does not support IAM OIDC Provider")
 }
+
+type pulumiIAMOIDCProvider struct {
+	URL            *string           `json:"url"`
+	ClientIDList   []*string         `json:"clientIdLists"`
+	ThumbprintList []*string         `json:"thumbprintLists"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *IAMOIDCProvider) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *IAMOIDCProvider) error {
+	p := &pulumiIAMOIDCProvider{
+		URL:            e.URL,
+		ClientIDList:   e.ClientIDs,
+		ThumbprintList: e.Thumbprints,
+		Tags:           e.Tags,
+	}
+
+	return t.RenderResource("aws:iam:OpenIdConnectProvider", *e.Name, p)
+}
+
+func (e *IAMOIDCProvider) PulumiLink() *pulumi.Literal {
+	return pulumi.LiteralProperty("aws:iam:OpenIdConnectProvider", *e.Name, "arn")
+}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -357,6 +358,14 @@ func (_ *IAMRole) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *I
 	return t.RenderResource("aws_iam_role", *e.Name, tf)
 }
 
+func (e *IAMRole) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*IAMRole)
+	if a.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_iam_role", ResourceName: *e.Name, ID: *a.Name}
+}
+
 func (e *IAMRole) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_iam_role", *e.Name, "name")
 }
@@ -396,3 +405,33 @@ func (_ *IAMRole) RenderCloudformation(t *cloudformation.CloudformationTarget, a
 func (e *IAMRole) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::IAM::Role", *e.Name)
 }
+
+type pulumiIAMRole struct {
+	Name                *string           `json:"name"`
+	AssumeRolePolicy    *pulumi.Literal   `json:"assumeRolePolicy"`
+	PermissionsBoundary *string           `json:"permissionsBoundary,omitempty"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *IAMRole) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *IAMRole) error {
+	policy, err := fi.ResourceAsString(e.RolePolicyDocument)
+	if err != nil {
+		return fmt.Errorf("error rendering RolePolicyDocument: %v", err)
+	}
+
+	p := &pulumiIAMRole{
+		Name:             e.Name,
+		AssumeRolePolicy: pulumi.LiteralString(policy),
+		Tags:             e.Tags,
+	}
+
+	if e.PermissionsBoundary != nil {
+		p.PermissionsBoundary = e.PermissionsBoundary
+	}
+
+	return t.RenderResource("aws:iam:Role", *e.Name, p)
+}
+
+func (e *IAMRole) PulumiLink() *pulumi.Literal {
+	return pulumi.LiteralProperty("aws:iam:Role", *e.Name, "name")
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -355,6 +356,14 @@ func (_ *IAMRolePolicy) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_iam_role_policy", *e.Name, tf)
 }
 
+// TerraformImport imports the inline policy of the role; attachments of external policies are not imported
+func (e *IAMRolePolicy) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	if e.Role == nil || e.Role.Name == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_iam_role_policy", ResourceName: *e.Name, ID: *e.Role.Name + ":" + *e.Name}
+}
+
 func (e *IAMRolePolicy) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_iam_role_policy", *e.Name)
 }
@@ -402,3 +411,50 @@ func (_ *IAMRolePolicy) RenderCloudformation(t *cloudformation.CloudformationTar
 func (e *IAMRolePolicy) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::IAM::Policy", *e.Name)
 }
+
+type pulumiIAMRolePolicy struct {
+	Name      *string         `json:"name,omitempty"`
+	Role      *pulumi.Literal `json:"role"`
+	Policy    *pulumi.Literal `json:"policy,omitempty"`
+	PolicyArn *string         `json:"policyArn,omitempty"`
+}
+
+func (_ *IAMRolePolicy) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *IAMRolePolicy) error {
+	if e.ExternalPolicies != nil && len(*e.ExternalPolicies) > 0 {
+		for _, policy := range *e.ExternalPolicies {
+			// create a hash of the arn
+			h := fnv.New32a()
+			h.Write([]byte(policy))
+
+			name := fmt.Sprintf("%s-%d", *e.Name, h.Sum32())
+
+			p := &pulumiIAMRolePolicy{
+				Role:      e.Role.PulumiLink(),
+				PolicyArn: s(policy),
+			}
+
+			err := t.RenderResource("aws:iam:RolePolicyAttachment", name, p)
+			if err != nil {
+				return fmt.Errorf("error rendering RolePolicyAttachment: %v", err)
+			}
+		}
+	}
+
+	policyString, err := e.policyDocumentString()
+	if err != nil {
+		return fmt.Errorf("error rendering PolicyDocument: %v", err)
+	}
+
+	if policyString == "" {
+		// A deletion; we simply don't render; pulumi will observe the removal
+		return nil
+	}
+
+	p := &pulumiIAMRolePolicy{
+		Name:   e.Name,
+		Role:   e.Role.PulumiLink(),
+		Policy: pulumi.LiteralString(policyString),
+	}
+
+	return t.RenderResource("aws:iam:RolePolicy", *e.Name, p)
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
+)
+
+type pulumiLaunchTemplateNetworkInterface struct {
+	// AssociatePublicIPAddress associates a public ip address with the network interface. Boolean value, as a string.
+	AssociatePublicIPAddress *string `json:"associatePublicIpAddress,omitempty"`
+	// DeleteOnTermination indicates whether the network interface should be destroyed on instance termination. Boolean value, as a string.
+	DeleteOnTermination *string `json:"deleteOnTermination,omitempty"`
+	// Ipv6AddressCount is the number of IPv6 addresses to assign with the primary network interface.
+	Ipv6AddressCount *int64 `json:"ipv6AddressCount,omitempty"`
+	// SecurityGroups is a list of security group ids.
+	SecurityGroups []*pulumi.Literal `json:"securityGroups,omitempty"`
+}
+
+type pulumiLaunchTemplateMonitoring struct {
+	// Enabled indicates that monitoring is enabled
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type pulumiLaunchTemplatePlacement struct {
+	// Tenancy ist he tenancy of the instance. Can be default, dedicated, or host.
+	Tenancy *string `json:"tenancy,omitempty"`
+}
+
+type pulumiLaunchTemplateIAMProfile struct {
+	// Name is the name of the profile
+	Name *pulumi.Literal `json:"name,omitempty"`
+}
+
+type pulumiLaunchTemplateMarketOptionsSpotOptions struct {
+	// BlockDurationMinutes is required duration in minutes. This value must be a multiple of 60.
+	BlockDurationMinutes *int64 `json:"blockDurationMinutes,omitempty"`
+	// InstanceInterruptionBehavior is the behavior when a Spot Instance is interrupted. Can be hibernate, stop, or terminate
+	InstanceInterruptionBehavior *string `json:"instanceInterruptionBehavior,omitempty"`
+	// MaxPrice is the maximum hourly price you're willing to pay for the Spot Instances
+	MaxPrice *string `json:"maxPrice,omitempty"`
+}
+
+type pulumiLaunchTemplateMarketOptions struct {
+	// MarketType is the option type
+	MarketType *string `json:"marketType,omitempty"`
+	// SpotOptions are the set of options
+	SpotOptions *pulumiLaunchTemplateMarketOptionsSpotOptions `json:"spotOptions,omitempty"`
+}
+
+type pulumiLaunchTemplateBlockDeviceEBS struct {
+	// VolumeType is the ebs type to use
+	VolumeType *string `json:"volumeType,omitempty"`
+	// VolumeSize is the volume size
+	VolumeSize *int64 `json:"volumeSize,omitempty"`
+	// IOPS is the provisioned IOPS
+	IOPS *int64 `json:"iops,omitempty"`
+	// Throughput is the gp3 volume throughput
+	Throughput *int64 `json:"throughput,omitempty"`
+	// DeleteOnTermination indicates the volume should die with the instance. Boolean value, as a string.
+	DeleteOnTermination *string `json:"deleteOnTermination,omitempty"`
+	// Encrypted indicates the device should be encrypted. Boolean value, as a string.
+	Encrypted *string `json:"encrypted,omitempty"`
+	// KmsKeyID is the encryption key identifier for the volume
+	KmsKeyID *string `json:"kmsKeyId,omitempty"`
+}
+
+type pulumiLaunchTemplateBlockDevice struct {
+	// DeviceName is the name of the device
+	DeviceName *string `json:"deviceName,omitempty"`
+	// VirtualName is used for the ephemeral devices
+	VirtualName *string `json:"virtualName,omitempty"`
+	// EBS defines the ebs spec
+	EBS *pulumiLaunchTemplateBlockDeviceEBS `json:"ebs,omitempty"`
+}
+
+type pulumiLaunchTemplateCreditSpecification struct {
+	// CPUCredits The credit option for CPU usage on some instance types
+	CPUCredits *string `json:"cpuCredits,omitempty"`
+}
+
+type pulumiLaunchTemplateTagSpecification struct {
+	// ResourceType is the type of resource to tag.
+	ResourceType *string `json:"resourceType,omitempty"`
+	// Tags are the tags to apply to the resource.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+type pulumiLaunchTemplateInstanceMetadata struct {
+	// HTTPEndpoint enables or disables the HTTP metadata endpoint on instances.
+	HTTPEndpoint *string `json:"httpEndpoint,omitempty"`
+	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
+	HTTPPutResponseHopLimit *int64 `json:"httpPutResponseHopLimit,omitempty"`
+	// HTTPTokens is the state of token usage for your instance metadata requests.
+	HTTPTokens *string `json:"httpTokens,omitempty"`
+}
+
+type pulumiLaunchTemplate struct {
+	// Name is the name of the launch template
+	Name *string `json:"name,omitempty"`
+
+	// BlockDeviceMappings is the device mappings
+	BlockDeviceMappings []*pulumiLaunchTemplateBlockDevice `json:"blockDeviceMappings,omitempty"`
+	// CreditSpecification is the credit option for CPU Usage on some instance types
+	CreditSpecification *pulumiLaunchTemplateCreditSpecification `json:"creditSpecification,omitempty"`
+	// EBSOptimized indicates if the root device is ebs optimized. Boolean value, as a string.
+	EBSOptimized *string `json:"ebsOptimized,omitempty"`
+	// IAMInstanceProfile is the IAM profile to assign to the nodes
+	IAMInstanceProfile *pulumiLaunchTemplateIAMProfile `json:"iamInstanceProfile,omitempty"`
+	// ImageID is the ami to use for the instances
+	ImageID *string `json:"imageId,omitempty"`
+	// InstanceType is the type of instance
+	InstanceType *string `json:"instanceType,omitempty"`
+	// KeyName is the ssh key to use
+	KeyName *pulumi.Literal `json:"keyName,omitempty"`
+	// MarketOptions are the spot pricing options
+	MarketOptions *pulumiLaunchTemplateMarketOptions `json:"instanceMarketOptions,omitempty"`
+	// MetadataOptions are the instance metadata options.
+	MetadataOptions *pulumiLaunchTemplateInstanceMetadata `json:"metadataOptions,omitempty"`
+	// Monitoring are the instance monitoring options
+	Monitoring *pulumiLaunchTemplateMonitoring `json:"monitoring,omitempty"`
+	// NetworkInterfaces are the networking options
+	NetworkInterfaces []*pulumiLaunchTemplateNetworkInterface `json:"networkInterfaces,omitempty"`
+	// Placement are the tenancy options
+	Placement *pulumiLaunchTemplatePlacement `json:"placement,omitempty"`
+	// Tags is a map of tags applied to the launch template itself
+	Tags map[string]string `json:"tags,omitempty"`
+	// TagSpecifications are the tags to apply to a resource when it is created.
+	TagSpecifications []*pulumiLaunchTemplateTagSpecification `json:"tagSpecifications,omitempty"`
+	// UserData is the base64 encoded user data for the instances
+	UserData *string `json:"userData,omitempty"`
+}
+
+// PulumiLink returns the pulumi reference
+func (t *LaunchTemplate) PulumiLink() *pulumi.Literal {
+	return pulumi.LiteralID("aws:ec2:LaunchTemplate", fi.StringValue(t.Name))
+}
+
+// PulumiVersionLink returns the pulumi version reference
+func (t *LaunchTemplate) PulumiVersionLink() *pulumi.Literal {
+	return pulumi.LiteralProperty("aws:ec2:LaunchTemplate", fi.StringValue(t.Name), "latestVersion")
+}
+
+// pulumiBool returns the boolean as a string, as some of the launch template properties are strings in the aws provider
+func pulumiBool(v *bool) *string {
+	if v == nil {
+		return nil
+	}
+	return fi.String(strconv.FormatBool(*v))
+}
+
+// RenderPulumi is responsible for rendering the pulumi resource
+func (t *LaunchTemplate) RenderPulumi(target *pulumi.PulumiTarget, a, e, changes *LaunchTemplate) error {
+	var err error
+
+	cloud := target.Cloud.(awsup.AWSCloud)
+
+	var image *string
+	if e.ImageID != nil {
+		im, err := cloud.ResolveImage(fi.StringValue(e.ImageID))
+		if err != nil {
+			return err
+		}
+		image = im.ImageId
+	}
+
+	p := pulumiLaunchTemplate{
+		Name:         e.Name,
+		EBSOptimized: pulumiBool(e.RootVolumeOptimization),
+		ImageID:      image,
+		InstanceType: e.InstanceType,
+		MetadataOptions: &pulumiLaunchTemplateInstanceMetadata{
+			HTTPEndpoint:            fi.String("enabled"),
+			HTTPTokens:              e.HTTPTokens,
+			HTTPPutResponseHopLimit: e.HTTPPutResponseHopLimit,
+		},
+		NetworkInterfaces: []*pulumiLaunchTemplateNetworkInterface{
+			{
+				AssociatePublicIPAddress: pulumiBool(e.AssociatePublicIP),
+				DeleteOnTermination:      fi.String("true"),
+				Ipv6AddressCount:         e.IPv6AddressCount,
+			},
+		},
+	}
+
+	if fi.StringValue(e.SpotPrice) != "" {
+		marketSpotOptions := pulumiLaunchTemplateMarketOptionsSpotOptions{MaxPrice: e.SpotPrice}
+		if e.SpotDurationInMinutes != nil {
+			marketSpotOptions.BlockDurationMinutes = e.SpotDurationInMinutes
+		}
+		if e.InstanceInterruptionBehavior != nil {
+			marketSpotOptions.InstanceInterruptionBehavior = e.InstanceInterruptionBehavior
+		}
+		p.MarketOptions = &pulumiLaunchTemplateMarketOptions{
+			MarketType:  fi.String("spot"),
+			SpotOptions: &marketSpotOptions,
+		}
+	}
+	if fi.StringValue(e.CPUCredits) != "" {
+		p.CreditSpecification = &pulumiLaunchTemplateCreditSpecification{
+			CPUCredits: e.CPUCredits,
+		}
+	}
+	for _, x := range e.SecurityGroups {
+		p.NetworkInterfaces[0].SecurityGroups = append(p.NetworkInterfaces[0].SecurityGroups, x.PulumiLink())
+	}
+	if e.SSHKey != nil {
+		p.KeyName = e.SSHKey.PulumiLink()
+	}
+	if e.Tenancy != nil {
+		p.Placement = &pulumiLaunchTemplatePlacement{Tenancy: e.Tenancy}
+	}
+	if e.InstanceMonitoring != nil {
+		p.Monitoring = &pulumiLaunchTemplateMonitoring{Enabled: e.InstanceMonitoring}
+	}
+	if e.IAMInstanceProfile != nil {
+		p.IAMInstanceProfile = &pulumiLaunchTemplateIAMProfile{
+			Name: e.IAMInstanceProfile.PulumiLink(),
+		}
+	}
+	if e.UserData != nil {
+		d, err := fi.ResourceAsBytes(e.UserData)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			p.UserData = fi.String(base64.StdEncoding.EncodeToString(d))
+		}
+	}
+	devices, err := e.buildRootDevice(cloud)
+	if err != nil {
+		return err
+	}
+	for n, x := range devices {
+		p.BlockDeviceMappings = append(p.BlockDeviceMappings, &pulumiLaunchTemplateBlockDevice{
+			DeviceName: fi.String(n),
+			EBS: &pulumiLaunchTemplateBlockDeviceEBS{
+				DeleteOnTermination: fi.String("true"),
+				Encrypted:           pulumiBool(x.EbsEncrypted),
+				KmsKeyID:            x.EbsKmsKey,
+				IOPS:                x.EbsVolumeIops,
+				Throughput:          x.EbsVolumeThroughput,
+				VolumeSize:          x.EbsVolumeSize,
+				VolumeType:          x.EbsVolumeType,
+			},
+		})
+	}
+	additionals, err := buildAdditionalDevices(e.BlockDeviceMappings)
+	if err != nil {
+		return err
+	}
+	for n, x := range additionals {
+		p.BlockDeviceMappings = append(p.BlockDeviceMappings, &pulumiLaunchTemplateBlockDevice{
+			DeviceName: fi.String(n),
+			EBS: &pulumiLaunchTemplateBlockDeviceEBS{
+				DeleteOnTermination: fi.String("true"),
+				Encrypted:           pulumiBool(x.EbsEncrypted),
+				IOPS:                x.EbsVolumeIops,
+				Throughput:          x.EbsVolumeThroughput,
+				KmsKeyID:            x.EbsKmsKey,
+				VolumeSize:          x.EbsVolumeSize,
+				VolumeType:          x.EbsVolumeType,
+			},
+		})
+	}
+
+	devices, err = buildEphemeralDevices(cloud, fi.StringValue(e.InstanceType))
+	if err != nil {
+		return err
+	}
+	for n, x := range devices {
+		p.BlockDeviceMappings = append(p.BlockDeviceMappings, &pulumiLaunchTemplateBlockDevice{
+			VirtualName: x.VirtualName,
+			DeviceName:  fi.String(n),
+		})
+	}
+
+	if e.Tags != nil {
+		p.TagSpecifications = append(p.TagSpecifications, &pulumiLaunchTemplateTagSpecification{
+			ResourceType: fi.String("instance"),
+			Tags:         e.Tags,
+		})
+		p.TagSpecifications = append(p.TagSpecifications, &pulumiLaunchTemplateTagSpecification{
+			ResourceType: fi.String("volume"),
+			Tags:         e.Tags,
+		})
+		p.Tags = e.Tags
+	}
+
+	return target.RenderResource("aws:ec2:LaunchTemplate", fi.StringValue(e.Name), p)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestLaunchTemplatePulumiRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &LaunchTemplate{
+				Name:              fi.String("test"),
+				AssociatePublicIP: fi.Bool(true),
+				IAMInstanceProfile: &IAMInstanceProfile{
+					Name: fi.String("nodes"),
+				},
+				ID:                           fi.String("test-11"),
+				InstanceMonitoring:           fi.Bool(true),
+				InstanceType:                 fi.String("t2.medium"),
+				RootVolumeOptimization:       fi.Bool(true),
+				RootVolumeIops:               fi.Int64(100),
+				RootVolumeSize:               fi.Int64(64),
+				SpotPrice:                    fi.String("10"),
+				SpotDurationInMinutes:        fi.Int64(120),
+				InstanceInterruptionBehavior: fi.String("hibernate"),
+				SSHKey: &SSHKey{
+					Name: fi.String("mykey"),
+				},
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.String("nodes-1"), ID: fi.String("1111")},
+					{Name: fi.String("nodes-2"), ID: fi.String("2222")},
+				},
+				Tenancy:                 fi.String("dedicated"),
+				HTTPTokens:              fi.String("required"),
+				HTTPPutResponseHopLimit: fi.Int64(1),
+			},
+			Expected: `description: kOps cluster test
+name: test
+resources:
+  ec2-launchtemplate-test:
+    properties:
+      ebsOptimized: "true"
+      iamInstanceProfile:
+        name: ${iam-instanceprofile-nodes.id}
+      instanceMarketOptions:
+        marketType: spot
+        spotOptions:
+          blockDurationMinutes: 120
+          instanceInterruptionBehavior: hibernate
+          maxPrice: "10"
+      instanceType: t2.medium
+      keyName: mykey
+      metadataOptions:
+        httpEndpoint: enabled
+        httpPutResponseHopLimit: 1
+        httpTokens: required
+      monitoring:
+        enabled: true
+      name: test
+      networkInterfaces:
+      - associatePublicIpAddress: "true"
+        deleteOnTermination: "true"
+        securityGroups:
+        - ${ec2-securitygroup-nodes-1.id}
+        - ${ec2-securitygroup-nodes-2.id}
+      placement:
+        tenancy: dedicated
+    type: aws:ec2:LaunchTemplate
+runtime: yaml
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:              fi.String("test"),
+				AssociatePublicIP: fi.Bool(true),
+				BlockDeviceMappings: []*BlockDeviceMapping{
+					{
+						DeviceName:             fi.String("/dev/xvdd"),
+						EbsVolumeType:          fi.String("gp2"),
+						EbsVolumeSize:          fi.Int64(100),
+						EbsDeleteOnTermination: fi.Bool(true),
+						EbsEncrypted:           fi.Bool(true),
+					},
+				},
+				IAMInstanceProfile: &IAMInstanceProfile{
+					Name: fi.String("nodes"),
+				},
+				ID:                     fi.String("test-11"),
+				InstanceMonitoring:     fi.Bool(true),
+				InstanceType:           fi.String("t2.medium"),
+				RootVolumeOptimization: fi.Bool(true),
+				RootVolumeIops:         fi.Int64(100),
+				RootVolumeSize:         fi.Int64(64),
+				SSHKey: &SSHKey{
+					Name:      fi.String("mykey"),
+					PublicKey: fi.NewStringResource("ssh-rsa AAAA"),
+				},
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.String("nodes-1"), ID: fi.String("1111")},
+					{Name: fi.String("nodes-2"), ID: fi.String("2222")},
+				},
+				Tags: map[string]string{
+					"KubernetesCluster": "test",
+				},
+				HTTPTokens:              fi.String("optional"),
+				HTTPPutResponseHopLimit: fi.Int64(1),
+			},
+			Expected: `description: kOps cluster test
+name: test
+resources:
+  ec2-launchtemplate-test:
+    properties:
+      blockDeviceMappings:
+      - deviceName: /dev/xvdd
+        ebs:
+          deleteOnTermination: "true"
+          encrypted: "true"
+          volumeSize: 100
+          volumeType: gp2
+      ebsOptimized: "true"
+      iamInstanceProfile:
+        name: ${iam-instanceprofile-nodes.id}
+      instanceType: t2.medium
+      keyName: ${ec2-keypair-mykey.keyName}
+      metadataOptions:
+        httpEndpoint: enabled
+        httpPutResponseHopLimit: 1
+        httpTokens: optional
+      monitoring:
+        enabled: true
+      name: test
+      networkInterfaces:
+      - associatePublicIpAddress: "true"
+        deleteOnTermination: "true"
+        securityGroups:
+        - ${ec2-securitygroup-nodes-1.id}
+        - ${ec2-securitygroup-nodes-2.id}
+      tagSpecifications:
+      - resourceType: instance
+        tags:
+          KubernetesCluster: test
+      - resourceType: volume
+        tags:
+          KubernetesCluster: test
+      tags:
+        KubernetesCluster: test
+    type: aws:ec2:LaunchTemplate
+runtime: yaml
+`,
+		},
+	}
+	doRenderTests(t, "RenderPulumi", cases)
+}
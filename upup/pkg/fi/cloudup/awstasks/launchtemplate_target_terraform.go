@@ -326,3 +326,11 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 
 	return target.RenderResource("aws_launch_template", fi.StringValue(e.Name), tf)
 }
+
+func (e *LaunchTemplate) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*LaunchTemplate)
+	if a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_launch_template", ResourceName: *e.Name, ID: *a.ID}
+}
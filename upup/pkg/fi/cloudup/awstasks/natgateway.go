@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -386,6 +387,14 @@ func (_ *NatGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_nat_gateway", *e.Name, tf)
 }
 
+func (e *NatGateway) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*NatGateway)
+	if fi.BoolValue(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_nat_gateway", ResourceName: *e.Name, ID: *a.ID}
+}
+
 func (e *NatGateway) TerraformLink() *terraformWriter.Literal {
 	if fi.BoolValue(e.Shared) {
 		if e.ID == nil {
@@ -434,3 +443,40 @@ func (e *NatGateway) CloudformationLink() *cloudformation.Literal {
 
 	return cloudformation.Ref("AWS::EC2::NatGateway", *e.Name)
 }
+
+type pulumiNATGateway struct {
+	AllocationId *pulumi.Literal   `json:"allocationId,omitempty"`
+	SubnetId     *pulumi.Literal   `json:"subnetId,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *NatGateway) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *NatGateway) error {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			return fmt.Errorf("ID must be set, if NatGateway is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing NatGateway with id %q", *e.ID)
+		return nil
+	}
+
+	p := &pulumiNATGateway{
+		AllocationId: e.ElasticIP.PulumiLink(),
+		SubnetId:     e.Subnet.PulumiLink(),
+		Tags:         e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:NatGateway", *e.Name, p)
+}
+
+func (e *NatGateway) PulumiLink() *pulumi.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if NatGateway is shared: %s", e)
+		}
+
+		return pulumi.LiteralString(*e.ID)
+	}
+
+	return pulumi.LiteralID("aws:ec2:NatGateway", *e.Name)
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -69,6 +70,11 @@ type NetworkLoadBalancer struct {
 
 	VPC          *VPC
 	TargetGroups []*TargetGroup
+
+	// arn is the ARN of the existing NLB
+	arn *string
+	// listenerARNs are the ARNs of the existing listeners, by port
+	listenerARNs map[int]string
 }
 
 var _ fi.CompareWithID = &NetworkLoadBalancer{}
@@ -262,6 +268,7 @@ func (e *NetworkLoadBalancer) Find(c *fi.Context) (*NetworkLoadBalancer, error)
 	actual.VPC = &VPC{ID: lb.VpcId}
 	actual.Type = lb.Type
 	actual.IpAddressType = lb.IpAddressType
+	actual.arn = loadBalancerArn
 
 	tagMap, err := cloud.DescribeELBV2Tags([]string{*loadBalancerArn})
 	if err != nil {
@@ -307,9 +314,11 @@ func (e *NetworkLoadBalancer) Find(c *fi.Context) (*NetworkLoadBalancer, error)
 
 		actual.Listeners = []*NetworkLoadBalancerListener{}
 		actual.TargetGroups = []*TargetGroup{}
+		actual.listenerARNs = make(map[int]string)
 		for _, l := range response.Listeners {
 			actualListener := &NetworkLoadBalancerListener{}
 			actualListener.Port = int(aws.Int64Value(l.Port))
+			actual.listenerARNs[actualListener.Port] = aws.StringValue(l.ListenerArn)
 			if len(l.Certificates) != 0 {
 				actualListener.SSLCertificateID = aws.StringValue(l.Certificates[0].CertificateArn) // What if there is more then one certificate, can we just grab the default certificate? we don't set it as default, we only set the one.
 				if l.SslPolicy != nil {
@@ -751,6 +760,23 @@ func (_ *NetworkLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 	return nil
 }
 
+// TerraformImports imports the NLB and those of its listeners that exist
+func (e *NetworkLoadBalancer) TerraformImports(existing fi.Task) []*terraformWriter.Import {
+	a := existing.(*NetworkLoadBalancer)
+	if a.arn == nil {
+		return nil
+	}
+	imports := []*terraformWriter.Import{
+		{ResourceType: "aws_lb", ResourceName: *e.Name, ID: *a.arn},
+	}
+	for _, listener := range e.Listeners {
+		if arn := a.listenerARNs[listener.Port]; arn != "" {
+			imports = append(imports, &terraformWriter.Import{ResourceType: "aws_lb_listener", ResourceName: fmt.Sprintf("%v-%v", *e.Name, listener.Port), ID: arn})
+		}
+	}
+	return imports
+}
+
 func (e *NetworkLoadBalancer) TerraformLink(params ...string) *terraformWriter.Literal {
 	prop := "id"
 	if len(params) > 0 {
@@ -867,3 +893,102 @@ func (e *NetworkLoadBalancer) CloudformationAttrCanonicalHostedZoneNameID() *clo
 func (e *NetworkLoadBalancer) CloudformationAttrDNSName() *cloudformation.Literal {
 	return cloudformation.GetAtt("AWS::ElasticLoadBalancingV2::LoadBalancer", *e.Name, "DNSName")
 }
+
+type pulumiNetworkLoadBalancer struct {
+	Name                   string                                   `json:"name"`
+	Internal               bool                                     `json:"internal"`
+	Type                   string                                   `json:"loadBalancerType"`
+	SubnetMappings         []pulumiNetworkLoadBalancerSubnetMapping `json:"subnetMappings"`
+	CrossZoneLoadBalancing bool                                     `json:"enableCrossZoneLoadBalancing"`
+
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+type pulumiNetworkLoadBalancerSubnetMapping struct {
+	Subnet             *pulumi.Literal `json:"subnetId"`
+	AllocationID       *string         `json:"allocationId,omitempty"`
+	PrivateIPv4Address *string         `json:"privateIpv4Address,omitempty"`
+}
+
+type pulumiNetworkLoadBalancerListener struct {
+	LoadBalancer   *pulumi.Literal                           `json:"loadBalancerArn"`
+	Port           int64                                     `json:"port"`
+	Protocol       string                                    `json:"protocol"`
+	CertificateARN *string                                   `json:"certificateArn,omitempty"`
+	SSLPolicy      *string                                   `json:"sslPolicy,omitempty"`
+	DefaultActions []pulumiNetworkLoadBalancerListenerAction `json:"defaultActions"`
+}
+
+type pulumiNetworkLoadBalancerListenerAction struct {
+	Type           string          `json:"type"`
+	TargetGroupARN *pulumi.Literal `json:"targetGroupArn,omitempty"`
+}
+
+func (_ *NetworkLoadBalancer) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *NetworkLoadBalancer) error {
+	nlb := &pulumiNetworkLoadBalancer{
+		Name:                   *e.LoadBalancerName,
+		Internal:               fi.StringValue(e.Scheme) == elbv2.LoadBalancerSchemeEnumInternal,
+		Type:                   elbv2.LoadBalancerTypeEnumNetwork,
+		Tags:                   e.Tags,
+		CrossZoneLoadBalancing: fi.BoolValue(e.CrossZoneLoadBalancing),
+	}
+
+	for _, subnetMapping := range e.SubnetMappings {
+		nlb.SubnetMappings = append(nlb.SubnetMappings, pulumiNetworkLoadBalancerSubnetMapping{
+			Subnet:             subnetMapping.Subnet.PulumiLink(),
+			AllocationID:       subnetMapping.AllocationID,
+			PrivateIPv4Address: subnetMapping.PrivateIPv4Address,
+		})
+	}
+
+	err := t.RenderResource("aws:lb:LoadBalancer", *e.Name, nlb)
+	if err != nil {
+		return err
+	}
+
+	for _, listener := range e.Listeners {
+		var listenerTG *TargetGroup
+		for _, tg := range e.TargetGroups {
+			if aws.StringValue(tg.Name) == listener.TargetGroupName {
+				listenerTG = tg
+				break
+			}
+		}
+		if listenerTG == nil {
+			return fmt.Errorf("target group not found for NLB listener %+v", e)
+		}
+		p := &pulumiNetworkLoadBalancerListener{
+			LoadBalancer: e.PulumiLink("arn"),
+			Port:         int64(listener.Port),
+			DefaultActions: []pulumiNetworkLoadBalancerListenerAction{
+				{
+					Type:           elbv2.ActionTypeEnumForward,
+					TargetGroupARN: listenerTG.PulumiLink(),
+				},
+			},
+		}
+		if listener.SSLCertificateID != "" {
+			p.CertificateARN = &listener.SSLCertificateID
+			p.Protocol = elbv2.ProtocolEnumTls
+			if listener.SSLPolicy != "" {
+				p.SSLPolicy = &listener.SSLPolicy
+			}
+		} else {
+			p.Protocol = elbv2.ProtocolEnumTcp
+		}
+
+		err = t.RenderResource("aws:lb:Listener", fmt.Sprintf("%v-%v", *e.Name, listener.Port), p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *NetworkLoadBalancer) PulumiLink(params ...string) *pulumi.Literal {
+	prop := "id"
+	if len(params) > 0 {
+		prop = params[0]
+	}
+	return pulumi.LiteralProperty("aws:lb:LoadBalancer", *e.Name, prop)
+}
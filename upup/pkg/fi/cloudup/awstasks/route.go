@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -295,6 +296,18 @@ func (_ *Route) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Rou
 	return t.RenderResource("aws_route", name, tf)
 }
 
+func (e *Route) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*Route)
+	if a.RouteTable == nil || a.RouteTable.ID == nil {
+		return nil
+	}
+	destination := fi.StringValue(a.CIDR)
+	if destination == "" {
+		destination = fi.StringValue(a.IPv6CIDR)
+	}
+	return &terraformWriter.Import{ResourceType: "aws_route", ResourceName: fmt.Sprintf("route-%v", *e.Name), ID: *a.RouteTable.ID + "_" + destination}
+}
+
 type cloudformationRoute struct {
 	RouteTableID      *cloudformation.Literal `json:"RouteTableId"`
 	CIDR              *string                 `json:"DestinationCidrBlock,omitempty"`
@@ -329,3 +342,36 @@ func (_ *Route) RenderCloudformation(t *cloudformation.CloudformationTarget, a,
 
 	return t.RenderResource("AWS::EC2::Route", *e.Name, tf)
 }
+
+type pulumiRoute struct {
+	RouteTableId             *pulumi.Literal `json:"routeTableId"`
+	DestinationCidrBlock     *string         `json:"destinationCidrBlock,omitempty"`
+	DestinationIpv6CidrBlock *string         `json:"destinationIpv6CidrBlock,omitempty"`
+	GatewayId                *pulumi.Literal `json:"gatewayId,omitempty"`
+	NatGatewayId             *pulumi.Literal `json:"natGatewayId,omitempty"`
+	TransitGatewayId         *string         `json:"transitGatewayId,omitempty"`
+}
+
+func (_ *Route) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *Route) error {
+	p := &pulumiRoute{
+		RouteTableId:             e.RouteTable.PulumiLink(),
+		DestinationCidrBlock:     e.CIDR,
+		DestinationIpv6CidrBlock: e.IPv6CIDR,
+	}
+
+	if e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil {
+		return fmt.Errorf("missing target for route")
+	} else if e.InternetGateway != nil {
+		p.GatewayId = e.InternetGateway.PulumiLink()
+	} else if e.NatGateway != nil {
+		p.NatGatewayId = e.NatGateway.PulumiLink()
+	} else if e.TransitGatewayID != nil {
+		p.TransitGatewayId = e.TransitGatewayID
+	}
+
+	if e.Instance != nil {
+		return fmt.Errorf("instance pulumi routes not yet implemented")
+	}
+
+	return t.RenderResource("aws:ec2:Route", *e.Name, p)
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -213,6 +214,14 @@ func (_ *RouteTable) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_route_table", *e.Name, tf)
 }
 
+func (e *RouteTable) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*RouteTable)
+	if fi.BoolValue(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_route_table", ResourceName: *e.Name, ID: *a.ID}
+}
+
 func (e *RouteTable) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_route_table", *e.Name, "id")
 }
@@ -234,3 +243,21 @@ func (_ *RouteTable) RenderCloudformation(t *cloudformation.CloudformationTarget
 func (e *RouteTable) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::RouteTable", *e.Name)
 }
+
+type pulumiRouteTable struct {
+	VpcId *pulumi.Literal   `json:"vpcId"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *RouteTable) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *RouteTable) error {
+	p := &pulumiRouteTable{
+		VpcId: e.VPC.PulumiLink(),
+		Tags:  e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:RouteTable", *e.Name, p)
+}
+
+func (e *RouteTable) PulumiLink() *pulumi.Literal {
+	return pulumi.LiteralID("aws:ec2:RouteTable", *e.Name)
+}
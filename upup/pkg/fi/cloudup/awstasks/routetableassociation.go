@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -201,6 +202,14 @@ func (_ *RouteTableAssociation) RenderTerraform(t *terraform.TerraformTarget, a,
 	return t.RenderResource("aws_route_table_association", *e.Name, tf)
 }
 
+func (e *RouteTableAssociation) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*RouteTableAssociation)
+	if a.Subnet == nil || a.Subnet.ID == nil || a.RouteTable == nil || a.RouteTable.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_route_table_association", ResourceName: *e.Name, ID: *a.Subnet.ID + "/" + *a.RouteTable.ID}
+}
+
 func (e *RouteTableAssociation) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_route_table_association", *e.Name)
 }
@@ -222,3 +231,17 @@ func (_ *RouteTableAssociation) RenderCloudformation(t *cloudformation.Cloudform
 func (e *RouteTableAssociation) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::SubnetRouteTableAssociation", *e.Name)
 }
+
+type pulumiRouteTableAssociation struct {
+	SubnetId     *pulumi.Literal `json:"subnetId"`
+	RouteTableId *pulumi.Literal `json:"routeTableId"`
+}
+
+func (_ *RouteTableAssociation) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *RouteTableAssociation) error {
+	p := &pulumiRouteTableAssociation{
+		SubnetId:     e.Subnet.PulumiLink(),
+		RouteTableId: e.RouteTable.PulumiLink(),
+	}
+
+	return t.RenderResource("aws:ec2:RouteTableAssociation", *e.Name, p)
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -208,6 +209,14 @@ func (_ *SecurityGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_security_group", *e.Name, tf)
 }
 
+func (e *SecurityGroup) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*SecurityGroup)
+	if fi.BoolValue(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_security_group", ResourceName: *e.Name, ID: *a.ID}
+}
+
 func (e *SecurityGroup) TerraformLink() *terraformWriter.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -260,6 +269,44 @@ func (e *SecurityGroup) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::SecurityGroup", *e.Name)
 }
 
+type pulumiSecurityGroup struct {
+	Name        *string           `json:"name"`
+	VpcId       *pulumi.Literal   `json:"vpcId"`
+	Description *string           `json:"description"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *SecurityGroup) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *SecurityGroup) error {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		// Not pulumi owned / managed
+		return nil
+	}
+
+	p := &pulumiSecurityGroup{
+		Name:        e.Name,
+		VpcId:       e.VPC.PulumiLink(),
+		Description: e.Description,
+		Tags:        e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:SecurityGroup", *e.Name, p)
+}
+
+func (e *SecurityGroup) PulumiLink() *pulumi.Literal {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		// Not pulumi owned / managed
+		if e.ID != nil {
+			return pulumi.LiteralString(*e.ID)
+		} else {
+			klog.Warningf("ID not set on shared subnet %v", e)
+		}
+	}
+
+	return pulumi.LiteralID("aws:ec2:SecurityGroup", *e.Name)
+}
+
 type deleteSecurityGroupRule struct {
 	groupID    *string
 	permission *ec2.IpPermission
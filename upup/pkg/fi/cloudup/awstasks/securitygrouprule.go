@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -376,6 +377,48 @@ func (_ *SecurityGroupRule) RenderTerraform(t *terraform.TerraformTarget, a, e,
 	return t.RenderResource("aws_security_group_rule", *e.Name, tf)
 }
 
+// TerraformImport imports the rule by the ID terraform builds from its properties, as rules have no ID of their own.
+// The properties are those of RenderTerraform.
+func (e *SecurityGroupRule) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*SecurityGroupRule)
+	if a.SecurityGroup == nil || a.SecurityGroup.ID == nil {
+		return nil
+	}
+
+	ruleType := "ingress"
+	if fi.BoolValue(e.Egress) {
+		ruleType = "egress"
+	}
+	protocol := "all"
+	fromPort := int64(0)
+	toPort := int64(0)
+	if e.Protocol != nil {
+		protocol = *e.Protocol
+		fromPort = fi.Int64Value(e.FromPort)
+		toPort = 65535
+		if e.ToPort != nil {
+			toPort = *e.ToPort
+		}
+	}
+
+	var sources []string
+	if a.SourceGroup != nil && a.SourceGroup.ID != nil {
+		sources = append(sources, *a.SourceGroup.ID)
+	}
+	if e.CIDR != nil {
+		sources = append(sources, *e.CIDR)
+	}
+	if e.IPv6CIDR != nil {
+		sources = append(sources, *e.IPv6CIDR)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	id := fmt.Sprintf("%s_%s_%s_%d_%d_%s", *a.SecurityGroup.ID, ruleType, protocol, fromPort, toPort, strings.Join(sources, "_"))
+	return &terraformWriter.Import{ResourceType: "aws_security_group_rule", ResourceName: *e.Name, ID: id}
+}
+
 type cloudformationSecurityGroupIngress struct {
 	SecurityGroup *cloudformation.Literal `json:"GroupId,omitempty"`
 	SourceGroup   *cloudformation.Literal `json:"SourceSecurityGroupId,omitempty"`
@@ -429,3 +472,58 @@ func (_ *SecurityGroupRule) RenderCloudformation(t *cloudformation.Cloudformatio
 
 	return t.RenderResource(cfType, *e.Name, tf)
 }
+
+type pulumiSecurityGroupRule struct {
+	Type *string `json:"type"`
+
+	SecurityGroupId       *pulumi.Literal `json:"securityGroupId"`
+	SourceSecurityGroupId *pulumi.Literal `json:"sourceSecurityGroupId,omitempty"`
+
+	FromPort *int64 `json:"fromPort"`
+	ToPort   *int64 `json:"toPort"`
+
+	Protocol       *string  `json:"protocol"`
+	CidrBlocks     []string `json:"cidrBlocks,omitempty"`
+	Ipv6CidrBlocks []string `json:"ipv6CidrBlocks,omitempty"`
+}
+
+func (_ *SecurityGroupRule) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *SecurityGroupRule) error {
+	p := &pulumiSecurityGroupRule{
+		Type:            fi.String("ingress"),
+		SecurityGroupId: e.SecurityGroup.PulumiLink(),
+		FromPort:        e.FromPort,
+		ToPort:          e.ToPort,
+		Protocol:        e.Protocol,
+	}
+	if fi.BoolValue(e.Egress) {
+		p.Type = fi.String("egress")
+	}
+
+	if e.Protocol == nil {
+		p.Protocol = fi.String("-1")
+		p.FromPort = fi.Int64(0)
+		p.ToPort = fi.Int64(0)
+	}
+
+	if p.FromPort == nil {
+		// FromPort is required by pulumi
+		p.FromPort = fi.Int64(0)
+	}
+	if p.ToPort == nil {
+		// ToPort is required by pulumi
+		p.ToPort = fi.Int64(65535)
+	}
+
+	if e.SourceGroup != nil {
+		p.SourceSecurityGroupId = e.SourceGroup.PulumiLink()
+	}
+
+	if e.CIDR != nil {
+		p.CidrBlocks = append(p.CidrBlocks, *e.CIDR)
+	}
+	if e.IPv6CIDR != nil {
+		p.Ipv6CidrBlocks = append(p.Ipv6CidrBlocks, *e.IPv6CIDR)
+	}
+
+	return t.RenderResource("aws:ec2:SecurityGroupRule", *e.Name, p)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestSecurityGroupRuleTerraformImport(t *testing.T) {
+	grid := []struct {
+		rule     *SecurityGroupRule
+		expected string
+	}{
+		{
+			rule: &SecurityGroupRule{
+				Protocol: fi.String("tcp"),
+				FromPort: fi.Int64(443),
+				ToPort:   fi.Int64(443),
+				CIDR:     fi.String("0.0.0.0/0"),
+			},
+			expected: "sg-1_ingress_tcp_443_443_0.0.0.0/0",
+		},
+		{
+			rule: &SecurityGroupRule{
+				Egress:   fi.Bool(true),
+				IPv6CIDR: fi.String("::/0"),
+			},
+			expected: "sg-1_egress_all_0_0_::/0",
+		},
+		{
+			rule: &SecurityGroupRule{
+				Protocol:    fi.String("udp"),
+				FromPort:    fi.Int64(1),
+				SourceGroup: &SecurityGroup{ID: fi.String("sg-2")},
+			},
+			expected: "sg-1_ingress_udp_1_65535_sg-2",
+		},
+	}
+	for _, g := range grid {
+		e := g.rule
+		e.Name = fi.String("rule")
+		e.SecurityGroup = &SecurityGroup{ID: fi.String("sg-1")}
+
+		i := e.TerraformImport(e)
+		if i == nil {
+			t.Errorf("expected import %q, got nil", g.expected)
+			continue
+		}
+		if i.ID != g.expected {
+			t.Errorf("expected import %q, got %q", g.expected, i.ID)
+		}
+		if i.ResourceType != "aws_security_group_rule" || i.ResourceName != "rule" {
+			t.Errorf("unexpected resource %s.%s", i.ResourceType, i.ResourceName)
+		}
+	}
+}
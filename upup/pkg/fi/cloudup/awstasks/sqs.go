@@ -31,6 +31,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -199,6 +200,14 @@ func (_ *SQS) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SQS)
 	return t.RenderResource("aws_sqs_queue", *e.Name, tf)
 }
 
+func (e *SQS) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*SQS)
+	if a.URL == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_sqs_queue", ResourceName: *e.Name, ID: *a.URL}
+}
+
 type cloudformationSQSQueue struct {
 	QueueName              *string             `json:"QueueName"`
 	MessageRetentionPeriod int                 `json:"MessageRetentionPeriod"`
@@ -243,6 +252,29 @@ func (_ *SQS) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e,
 	return t.RenderResource("AWS::SQS::QueuePolicy", *e.Name+"Policy", cfQueuePolicy)
 }
 
+type pulumiSQSQueue struct {
+	Name                    *string           `json:"name"`
+	MessageRetentionSeconds int               `json:"messageRetentionSeconds"`
+	Policy                  *pulumi.Literal   `json:"policy"`
+	Tags                    map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *SQS) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *SQS) error {
+	policy, err := fi.ResourceAsString(e.Policy)
+	if err != nil {
+		return fmt.Errorf("error rendering SQS policy: %v", err)
+	}
+
+	p := &pulumiSQSQueue{
+		Name:                    e.Name,
+		MessageRetentionSeconds: e.MessageRetentionPeriod,
+		Policy:                  pulumi.LiteralString(policy),
+		Tags:                    e.Tags,
+	}
+
+	return t.RenderResource("aws:sqs:Queue", *e.Name, p)
+}
+
 // change tags to format required by CreateQueue
 func convertTagsToPointers(tags map[string]string) map[string]*string {
 	newTags := map[string]*string{}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -208,6 +209,15 @@ func (_ *SSHKey) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SS
 	return t.RenderResource("aws_key_pair", tfName, tf)
 }
 
+func (e *SSHKey) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*SSHKey)
+	if e.IsExistingKey() || a.Name == nil {
+		return nil
+	}
+	tfName := strings.Replace(*e.Name, ":", "", -1)
+	return &terraformWriter.Import{ResourceType: "aws_key_pair", ResourceName: tfName, ID: *a.Name}
+}
+
 // IsExistingKey will be true if the task has been initialized without using a public key
 // this is when we want to use a key that is already present in AWS.
 func (e *SSHKey) IsExistingKey() bool {
@@ -249,6 +259,41 @@ func (_ *SSHKey) RenderCloudformation(t *cloudformation.CloudformationTarget, a,
 	return nil
 }
 
+type pulumiSSHKey struct {
+	KeyName   *string           `json:"keyName"`
+	PublicKey *pulumi.Literal   `json:"publicKey"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *SSHKey) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *SSHKey) error {
+	// We don't want to render a key definition when we're using one that already exists
+	if e.IsExistingKey() {
+		return nil
+	}
+	publicKey, err := fi.ResourceAsString(e.PublicKey)
+	if err != nil {
+		return fmt.Errorf("error rendering PublicKey: %v", err)
+	}
+
+	p := &pulumiSSHKey{
+		KeyName:   e.Name,
+		PublicKey: pulumi.LiteralString(publicKey),
+		Tags:      e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:KeyPair", *e.Name, p)
+}
+
+func (e *SSHKey) PulumiLink() *pulumi.Literal {
+	if e.NoSSHKey() {
+		return nil
+	}
+	if e.IsExistingKey() {
+		return pulumi.LiteralString(*e.Name)
+	}
+	return pulumi.LiteralProperty("aws:ec2:KeyPair", *e.Name, "keyName")
+}
+
 func (e *SSHKey) NoSSHKey() bool {
 	return e.ID == nil && e.Name == nil && e.PublicKey == nil && e.KeyFingerprint == nil
 }
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/upup/pkg/fi/utils"
@@ -324,6 +325,14 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 	return t.RenderResource("aws_subnet", *e.Name, tf)
 }
 
+func (e *Subnet) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*Subnet)
+	if fi.BoolValue(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_subnet", ResourceName: *e.Name, ID: *a.ID}
+}
+
 func (e *Subnet) TerraformLink() *terraformWriter.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -385,6 +394,52 @@ func (e *Subnet) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::Subnet", *e.Name)
 }
 
+type pulumiSubnet struct {
+	VpcId            *pulumi.Literal   `json:"vpcId"`
+	CidrBlock        *string           `json:"cidrBlock,omitempty"`
+	Ipv6CidrBlock    *string           `json:"ipv6CidrBlock,omitempty"`
+	AvailabilityZone *string           `json:"availabilityZone,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *Subnet) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *Subnet) error {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		// Not pulumi owned / managed
+		// We won't apply changes, but our validation (kops update) will still warn
+		return nil
+	}
+
+	if strings.HasPrefix(aws.StringValue(e.IPv6CIDR), "/") {
+		// TODO: Implement using "fn::invoke" of std:cidrsubnet
+		return fmt.Errorf("<cidrsubnet> in not supported with Pulumi target: %q", aws.StringValue(e.IPv6CIDR))
+	}
+
+	p := &pulumiSubnet{
+		VpcId:            e.VPC.PulumiLink(),
+		CidrBlock:        e.CIDR,
+		Ipv6CidrBlock:    e.IPv6CIDR,
+		AvailabilityZone: e.AvailabilityZone,
+		Tags:             e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:Subnet", *e.Name, p)
+}
+
+func (e *Subnet) PulumiLink() *pulumi.Literal {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if subnet is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing subnet with id %q", *e.ID)
+		return pulumi.LiteralString(*e.ID)
+	}
+
+	return pulumi.LiteralID("aws:ec2:Subnet", *e.Name)
+}
+
 func (e *Subnet) FindDeletions(c *fi.Context) ([]fi.Deletion, error) {
 	if e.ID == nil || aws.BoolValue(e.Shared) {
 		return nil, nil
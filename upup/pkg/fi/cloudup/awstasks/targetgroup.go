@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -248,6 +249,14 @@ func (_ *TargetGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 	return t.RenderResource("aws_lb_target_group", *e.Name, tf)
 }
 
+func (e *TargetGroup) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*TargetGroup)
+	if fi.BoolValue(e.Shared) || a.ARN == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_lb_target_group", ResourceName: *e.Name, ID: *a.ARN}
+}
+
 func (e *TargetGroup) TerraformLink(params ...string) *terraformWriter.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -303,3 +312,57 @@ func (e *TargetGroup) CloudformationLink() *cloudformation.Literal {
 
 	return cloudformation.Ref("AWS::ElasticLoadBalancingV2::TargetGroup", *e.Name)
 }
+
+type pulumiTargetGroup struct {
+	Name        string                       `json:"name"`
+	Port        int64                        `json:"port"`
+	Protocol    string                       `json:"protocol"`
+	VPCID       *pulumi.Literal              `json:"vpcId"`
+	Tags        map[string]string            `json:"tags,omitempty"`
+	HealthCheck pulumiTargetGroupHealthCheck `json:"healthCheck"`
+}
+
+type pulumiTargetGroupHealthCheck struct {
+	HealthyThreshold   int64  `json:"healthyThreshold"`
+	UnhealthyThreshold int64  `json:"unhealthyThreshold"`
+	Protocol           string `json:"protocol"`
+}
+
+func (_ *TargetGroup) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *TargetGroup) error {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		return nil
+	}
+
+	if e.VPC == nil {
+		return fmt.Errorf("Missing VPC task from target group:\n%v\n%v", e, e.VPC)
+	}
+
+	p := &pulumiTargetGroup{
+		Name:     *e.Name,
+		Port:     *e.Port,
+		Protocol: *e.Protocol,
+		VPCID:    e.VPC.PulumiLink(),
+		Tags:     e.Tags,
+		HealthCheck: pulumiTargetGroupHealthCheck{
+			HealthyThreshold:   *e.HealthyThreshold,
+			UnhealthyThreshold: *e.UnhealthyThreshold,
+			Protocol:           elbv2.ProtocolEnumTcp,
+		},
+	}
+
+	return t.RenderResource("aws:lb:TargetGroup", *e.Name, p)
+}
+
+func (e *TargetGroup) PulumiLink() *pulumi.Literal {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		if e.ARN != nil {
+			return pulumi.LiteralString(*e.ARN)
+		} else {
+			klog.Warningf("ID not set on shared Target Group: %v", e)
+		}
+	}
+
+	return pulumi.LiteralProperty("aws:lb:TargetGroup", *e.Name, "arn")
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -311,6 +312,14 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 	return t.RenderResource("aws_vpc", *e.Name, tf)
 }
 
+func (e *VPC) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*VPC)
+	if fi.BoolValue(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_vpc", ResourceName: *e.Name, ID: *a.ID}
+}
+
 func (e *VPC) TerraformLink() *terraformWriter.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -364,6 +373,47 @@ func (e *VPC) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::VPC", *e.Name)
 }
 
+type pulumiVPC struct {
+	CidrBlock                    *string           `json:"cidrBlock,omitempty"`
+	EnableDnsHostnames           *bool             `json:"enableDnsHostnames,omitempty"`
+	EnableDnsSupport             *bool             `json:"enableDnsSupport,omitempty"`
+	AssignGeneratedIpv6CidrBlock *bool             `json:"assignGeneratedIpv6CidrBlock,omitempty"`
+	Tags                         map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *VPC) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *VPC) error {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		// Not pulumi owned / managed
+		// We won't apply changes, but our validation (kops update) will still warn
+		return nil
+	}
+
+	p := &pulumiVPC{
+		CidrBlock:                    e.CIDR,
+		EnableDnsHostnames:           e.EnableDNSHostnames,
+		EnableDnsSupport:             e.EnableDNSSupport,
+		AssignGeneratedIpv6CidrBlock: e.AmazonIPv6,
+		Tags:                         e.Tags,
+	}
+
+	return t.RenderResource("aws:ec2:Vpc", *e.Name, p)
+}
+
+func (e *VPC) PulumiLink() *pulumi.Literal {
+	shared := fi.BoolValue(e.Shared)
+	if shared {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if VPC is shared: %s", e)
+		}
+
+		klog.V(4).Infof("reusing existing VPC with id %q", *e.ID)
+		return pulumi.LiteralString(*e.ID)
+	}
+
+	return pulumi.LiteralID("aws:ec2:Vpc", *e.Name)
+}
+
 type deleteVPCCIDRBlock struct {
 	vpcID         *string
 	cidrBlock     *string
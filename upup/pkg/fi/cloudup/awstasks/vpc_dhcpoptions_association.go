@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -116,6 +117,14 @@ func (_ *VPCDHCPOptionsAssociation) RenderTerraform(t *terraform.TerraformTarget
 	return t.RenderResource("aws_vpc_dhcp_options_association", *e.Name, tf)
 }
 
+func (e *VPCDHCPOptionsAssociation) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*VPCDHCPOptionsAssociation)
+	if a.VPC == nil || a.VPC.ID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_vpc_dhcp_options_association", ResourceName: *e.Name, ID: *a.VPC.ID}
+}
+
 type cloudformationVPCDHCPOptionsAssociation struct {
 	VpcId         *cloudformation.Literal `json:"VpcId"`
 	DhcpOptionsId *cloudformation.Literal `json:"DhcpOptionsId"`
@@ -129,3 +138,17 @@ func (_ *VPCDHCPOptionsAssociation) RenderCloudformation(t *cloudformation.Cloud
 
 	return t.RenderResource("AWS::EC2::VPCDHCPOptionsAssociation", *e.Name, tf)
 }
+
+type pulumiVPCDHCPOptionsAssociation struct {
+	VpcId         *pulumi.Literal `json:"vpcId"`
+	DhcpOptionsId *pulumi.Literal `json:"dhcpOptionsId"`
+}
+
+func (_ *VPCDHCPOptionsAssociation) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *VPCDHCPOptionsAssociation) error {
+	p := &pulumiVPCDHCPOptionsAssociation{
+		VpcId:         e.VPC.PulumiLink(),
+		DhcpOptionsId: e.DHCPOptions.PulumiLink(),
+	}
+
+	return t.RenderResource("aws:ec2:VpcDhcpOptionsAssociation", *e.Name, p)
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -132,6 +133,11 @@ func (_ *VPCAmazonIPv6CIDRBlock) RenderCloudformation(t *cloudformation.Cloudfor
 	return t.RenderResource("AWS::EC2::VPCCidrBlock", *e.Name, cf)
 }
 
+func (_ *VPCAmazonIPv6CIDRBlock) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *VPCAmazonIPv6CIDRBlock) error {
+	// At the moment, this can only be done via the aws:ec2:Vpc resource
+	return nil
+}
+
 func findVPCIPv6CIDR(cloud awsup.AWSCloud, vpcID *string) (*string, error) {
 	vpc, err := cloud.DescribeVPC(aws.StringValue(vpcID))
 	if err != nil {
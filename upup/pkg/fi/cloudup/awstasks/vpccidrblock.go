@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -38,6 +39,9 @@ type VPCCIDRBlock struct {
 
 	// Shared is set if this is a shared VPC
 	Shared *bool
+
+	// associationID is the ID of the existing association of the CIDR block with the VPC
+	associationID *string
 }
 
 func (e *VPCCIDRBlock) Find(c *fi.Context) (*VPCCIDRBlock, error) {
@@ -49,7 +53,7 @@ func (e *VPCCIDRBlock) Find(c *fi.Context) (*VPCCIDRBlock, error) {
 		return nil, err
 	}
 
-	found := false
+	var associationID *string
 	if e.CIDRBlock != nil {
 		for _, cba := range vpc.CidrBlockAssociationSet {
 			if cba == nil || cba.CidrBlockState == nil {
@@ -62,18 +66,19 @@ func (e *VPCCIDRBlock) Find(c *fi.Context) (*VPCCIDRBlock, error) {
 			}
 
 			if aws.StringValue(cba.CidrBlock) == aws.StringValue(e.CIDRBlock) {
-				found = true
+				associationID = cba.AssociationId
 				break
 			}
 		}
 	}
-	if !found {
+	if associationID == nil {
 		return nil, nil
 	}
 
 	actual := &VPCCIDRBlock{
-		VPC:       &VPC{ID: vpc.VpcId},
-		CIDRBlock: e.CIDRBlock,
+		VPC:           &VPC{ID: vpc.VpcId},
+		CIDRBlock:     e.CIDRBlock,
+		associationID: associationID,
 	}
 
 	// Prevent spurious changes
@@ -158,6 +163,14 @@ func (_ *VPCCIDRBlock) RenderTerraform(t *terraform.TerraformTarget, a, e, chang
 	return t.RenderResource("aws_vpc_ipv4_cidr_block_association", name, tf)
 }
 
+func (e *VPCCIDRBlock) TerraformImport(existing fi.Task) *terraformWriter.Import {
+	a := existing.(*VPCCIDRBlock)
+	if fi.BoolValue(e.Shared) || a.associationID == nil {
+		return nil
+	}
+	return &terraformWriter.Import{ResourceType: "aws_vpc_ipv4_cidr_block_association", ResourceName: fmt.Sprintf("cidr-%v", *e.Name), ID: *a.associationID}
+}
+
 type cloudformationVPCCIDRBlock struct {
 	VPCID     *cloudformation.Literal `json:"VpcId"`
 	CIDRBlock *string                 `json:"CidrBlock"`
@@ -178,3 +191,24 @@ func (_ *VPCCIDRBlock) RenderCloudformation(t *cloudformation.CloudformationTarg
 
 	return t.RenderResource("AWS::EC2::VPCCidrBlock", *e.Name, cf)
 }
+
+type pulumiVPCCIDRBlock struct {
+	VpcId     *pulumi.Literal `json:"vpcId"`
+	CidrBlock *string         `json:"cidrBlock"`
+}
+
+func (_ *VPCCIDRBlock) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *VPCCIDRBlock) error {
+	shared := aws.BoolValue(e.Shared)
+	if shared && a == nil {
+		// VPC not owned by kOps, no changes will be applied
+		// Verify that the CIDR block was found.
+		return fmt.Errorf("CIDR block %q not found", aws.StringValue(e.CIDRBlock))
+	}
+
+	p := &pulumiVPCCIDRBlock{
+		VpcId:     e.VPC.PulumiLink(),
+		CidrBlock: e.CIDRBlock,
+	}
+
+	return t.RenderResource("aws:ec2:VpcIpv4CidrBlockAssociation", *e.Name, p)
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/pulumi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -133,3 +134,10 @@ func (_ *WarmPool) RenderCloudformation(t *cloudformation.CloudformationTarget,
 	}
 	return nil
 }
+
+func (_ *WarmPool) RenderPulumi(t *pulumi.PulumiTarget, a, e, changes *WarmPool) error {
+	if changes != nil {
+		klog.Warning("ASG warm pool is not supported by the pulumi target")
+	}
+	return nil
+}
@@ -595,6 +595,64 @@ func detachInstance(c AWSCloud, i *cloudinstances.CloudInstance) error {
 	return nil
 }
 
+// RollbackGroup causes an autoscaling group to launch new instances from the launch template version
+// or launch configuration that its outdated instances were launched from.
+func (c *awsCloudImplementation) RollbackGroup(g *cloudinstances.CloudInstanceGroup) error {
+	if _, ok := g.Raw.(*autoscaling.Group); !ok {
+		return fmt.Errorf("rolling back instance group %q is not supported", g.HumanName)
+	}
+
+	return rollbackGroup(c, g)
+}
+
+func rollbackGroup(c AWSCloud, g *cloudinstances.CloudInstanceGroup) error {
+	asg := g.Raw.(*autoscaling.Group)
+	name := aws.StringValue(asg.AutoScalingGroupName)
+
+	needUpdate := make(map[string]bool)
+	for _, i := range g.NeedUpdate {
+		needUpdate[i.ID] = true
+	}
+
+	for _, i := range asg.Instances {
+		if !needUpdate[aws.StringValue(i.InstanceId)] {
+			continue
+		}
+
+		request := &autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: asg.AutoScalingGroupName,
+		}
+		if i.LaunchTemplate != nil {
+			launchTemplate := &autoscaling.LaunchTemplateSpecification{
+				LaunchTemplateId: i.LaunchTemplate.LaunchTemplateId,
+				Version:          i.LaunchTemplate.Version,
+			}
+			if asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil {
+				request.MixedInstancesPolicy = &autoscaling.MixedInstancesPolicy{
+					LaunchTemplate: &autoscaling.LaunchTemplate{
+						LaunchTemplateSpecification: launchTemplate,
+						Overrides:                   asg.MixedInstancesPolicy.LaunchTemplate.Overrides,
+					},
+				}
+			} else {
+				request.LaunchTemplate = launchTemplate
+			}
+		} else if i.LaunchConfigurationName != nil {
+			request.LaunchConfigurationName = i.LaunchConfigurationName
+		} else {
+			continue
+		}
+
+		klog.Infof("Rolling back autoscaling group %q to %q", name, findInstanceLaunchConfiguration(i))
+		if _, err := c.Autoscaling().UpdateAutoScalingGroup(request); err != nil {
+			return fmt.Errorf("error rolling back autoscaling group %q: %v", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no outdated instances found in autoscaling group %q to roll back to", name)
+}
+
 // GetCloudGroups returns a groups of instances that back a kops instance groups
 func (c *awsCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	if c.spotinst != nil {
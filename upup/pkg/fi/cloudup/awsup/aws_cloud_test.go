@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func TestRollbackGroup(t *testing.T) {
+	cloud := BuildMockAWSCloud("us-east-1", "a")
+	cloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+
+	launchTemplate := func(version string) *autoscaling.LaunchTemplateSpecification {
+		return &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateId: aws.String("lt-1234"),
+			Version:          aws.String(version),
+		}
+	}
+
+	_, err := cloud.Autoscaling().CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("nodes"),
+		LaunchTemplate:       launchTemplate("$Latest"),
+	})
+	if err != nil {
+		t.Fatalf("error creating autoscaling group: %v", err)
+	}
+
+	group := &cloudinstances.CloudInstanceGroup{
+		HumanName: "nodes",
+		Raw: &autoscaling.Group{
+			AutoScalingGroupName: aws.String("nodes"),
+			LaunchTemplate:       launchTemplate("$Latest"),
+			Instances: []*autoscaling.Instance{
+				{InstanceId: aws.String("i-canary"), LaunchTemplate: launchTemplate("3")},
+				{InstanceId: aws.String("i-old"), LaunchTemplate: launchTemplate("2")},
+			},
+		},
+	}
+	if _, err := group.NewCloudInstance("i-canary", cloudinstances.CloudInstanceStatusUpToDate, nil); err != nil {
+		t.Fatalf("error creating cloud instance: %v", err)
+	}
+	if _, err := group.NewCloudInstance("i-old", cloudinstances.CloudInstanceStatusNeedsUpdate, nil); err != nil {
+		t.Fatalf("error creating cloud instance: %v", err)
+	}
+
+	if err := cloud.RollbackGroup(group); err != nil {
+		t.Fatalf("unexpected error rolling back: %v", err)
+	}
+
+	asg := cloud.MockAutoscaling.(*mockautoscaling.MockAutoscaling).Groups["nodes"]
+	if actual := aws.StringValue(asg.LaunchTemplate.Version); actual != "2" {
+		t.Errorf("expected launch template version %q, got %q", "2", actual)
+	}
+
+	group.NeedUpdate = nil
+	if err := cloud.RollbackGroup(group); err == nil {
+		t.Errorf("expected error rolling back group without outdated instances")
+	}
+}
@@ -836,6 +836,22 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 				})
 			}
 		}
+		// The addon is kept when there are no group roles, so that kops-controller deletes the bindings of removed group roles
+		if b.Cluster.Spec.Authentication.Users != nil {
+			key := "user-roles.rbac.addons.k8s.io"
+
+			{
+				location := key + "/k8s-1.16.yaml"
+				id := "k8s-1.16"
+
+				addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+					Name:     fi.String(key),
+					Selector: map[string]string{"k8s-addon": key},
+					Manifest: fi.String(location),
+					Id:       id,
+				})
+			}
+		}
 	}
 
 	if kops.CloudProviderID(b.Cluster.Spec.CloudProvider) == kops.CloudProviderOpenstack {
@@ -80,6 +80,47 @@ func recreateCloudInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
 	return c.WaitForOp(op)
 }
 
+// RollbackGroup causes an InstanceGroupManager to create new instances from the InstanceTemplate
+// that its outdated instances were created from.
+func (c *gceCloudImplementation) RollbackGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return rollbackCloudInstanceGroup(c, g)
+}
+
+// rollbackCloudInstanceGroup sets the InstanceTemplate of the InstanceGroupManager to that of its outdated instances
+func rollbackCloudInstanceGroup(c GCECloud, g *cloudinstances.CloudInstanceGroup) error {
+	mig := g.Raw.(*compute.InstanceGroupManager)
+
+	needUpdate := make(map[string]bool)
+	for _, i := range g.NeedUpdate {
+		needUpdate[i.ID] = true
+	}
+
+	instances, err := ListManagedInstances(c, mig)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range instances {
+		if !needUpdate[i.Instance] || i.Version == nil || i.Version.InstanceTemplate == "" || i.Version.InstanceTemplate == mig.InstanceTemplate {
+			continue
+		}
+
+		migURL, err := ParseGoogleCloudURL(mig.SelfLink)
+		if err != nil {
+			return err
+		}
+
+		klog.Infof("Rolling back MIG %s to InstanceTemplate %s", mig.Name, i.Version.InstanceTemplate)
+		op, err := c.Compute().InstanceGroupManagers().SetInstanceTemplate(migURL.Project, migURL.Zone, migURL.Name, i.Version.InstanceTemplate)
+		if err != nil {
+			return fmt.Errorf("error rolling back InstanceTemplate for MIG %s: %v", mig.Name, err)
+		}
+		return c.WaitForOp(op)
+	}
+
+	return fmt.Errorf("no outdated instances found in MIG %s to roll back to", mig.Name)
+}
+
 // GetCloudGroups returns a map of CloudGroup that backs a list of instance groups
 func (c *gceCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	return getCloudGroups(c, cluster, instancegroups, warnUnmatched, nodes)
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pulumi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Literal is a value in a Pulumi YAML program, either a string or an interpolation of the output of another resource
+type Literal struct {
+	value string
+	// interpolation is set if value is an interpolation, which must not be escaped
+	interpolation bool
+}
+
+var _ json.Marshaler = &Literal{}
+
+func (l *Literal) MarshalJSON() ([]byte, error) {
+	if l.interpolation {
+		return json.Marshal(l.value)
+	}
+	// Pulumi YAML interpolates ${...} in every string, e.g. IAM policy variables must be escaped
+	return json.Marshal(strings.ReplaceAll(l.value, "${", "$${"))
+}
+
+// LiteralString returns a Literal for the string v
+func LiteralString(v string) *Literal {
+	return &Literal{value: v}
+}
+
+// LiteralProperty returns a Literal for the output prop of the resource
+func LiteralProperty(resourceType, resourceName, prop string) *Literal {
+	return &Literal{
+		value:         "${" + ResourceKey(resourceType, resourceName) + "." + prop + "}",
+		interpolation: true,
+	}
+}
+
+// LiteralID returns a Literal for the ID of the resource
+func LiteralID(resourceType, resourceName string) *Literal {
+	return LiteralProperty(resourceType, resourceName, "id")
+}
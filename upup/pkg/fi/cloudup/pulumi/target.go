@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pulumi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"sigs.k8s.io/yaml"
+)
+
+// ProgramFile is the name of the Pulumi YAML program
+const ProgramFile = "Pulumi.yaml"
+
+// PulumiTarget renders the tasks as the resources of a Pulumi YAML program
+type PulumiTarget struct {
+	Cloud       fi.Cloud
+	ClusterName string
+
+	outDir string
+
+	// mutex protects the following items (resources)
+	mutex     sync.Mutex
+	resources map[string]*pulumiResource
+}
+
+func NewPulumiTarget(cloud fi.Cloud, clusterName string, outDir string) *PulumiTarget {
+	return &PulumiTarget{
+		Cloud:       cloud,
+		ClusterName: clusterName,
+		outDir:      outDir,
+		resources:   make(map[string]*pulumiResource),
+	}
+}
+
+var _ fi.Target = &PulumiTarget{}
+
+type pulumiResource struct {
+	Type       string      `json:"type"`
+	Properties interface{} `json:"properties,omitempty"`
+}
+
+type pulumiProgram struct {
+	Name        string                     `json:"name"`
+	Runtime     string                     `json:"runtime"`
+	Description string                     `json:"description,omitempty"`
+	Resources   map[string]*pulumiResource `json:"resources"`
+}
+
+// ResourceKey returns the logical name of a resource in the program.
+// Logical names must be unique across resource types, so they are prefixed with the module and type of the resource,
+// e.g. ec2-securitygroup-nodes-example-com for the aws:ec2:SecurityGroup nodes.example.com
+func ResourceKey(resourceType, resourceName string) string {
+	name := strings.TrimPrefix(resourceType, "aws:")
+	name = strings.ToLower(strings.Replace(name, ":", "-", -1)) + "-" + resourceName
+	return sanitizeName(name)
+}
+
+// sanitizeName replaces the characters which can not be used in an interpolation like ${name.id}
+func sanitizeName(name string) string {
+	name = strings.Replace(name, ".", "-", -1)
+	name = strings.Replace(name, "/", "-", -1)
+	name = strings.Replace(name, ":", "-", -1)
+	return name
+}
+
+func (t *PulumiTarget) ProcessDeletions() bool {
+	// Pulumi tracks & performs deletions itself
+	return false
+}
+
+// RenderResource adds a resource of the type, e.g. aws:ec2:Vpc, to the program
+func (t *PulumiTarget) RenderResource(resourceType string, resourceName string, properties interface{}) error {
+	res := &pulumiResource{
+		Type:       resourceType,
+		Properties: properties,
+	}
+
+	key := ResourceKey(resourceType, resourceName)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.resources[key] != nil {
+		return fmt.Errorf("resource %q already exists in pulumi program", key)
+	}
+	t.resources[key] = res
+
+	return nil
+}
+
+func (t *PulumiTarget) Finish(taskMap map[string]fi.Task) error {
+	program := &pulumiProgram{
+		Name:        sanitizeName(t.ClusterName),
+		Runtime:     "yaml",
+		Description: "kOps cluster " + t.ClusterName,
+		Resources:   t.resources,
+	}
+
+	yamlBytes, err := yaml.Marshal(program)
+	if err != nil {
+		return fmt.Errorf("error marshaling pulumi program to yaml: %v", err)
+	}
+
+	p := path.Join(t.outDir, ProgramFile)
+
+	err = os.MkdirAll(path.Dir(p), os.FileMode(0755))
+	if err != nil {
+		return fmt.Errorf("error creating output directory %q: %v", path.Dir(p), err)
+	}
+
+	err = ioutil.WriteFile(p, yamlBytes, os.FileMode(0644))
+	if err != nil {
+		return fmt.Errorf("error writing pulumi program to output file %q: %v", p, err)
+	}
+
+	klog.Infof("Pulumi output is in %s", t.outDir)
+
+	return nil
+}
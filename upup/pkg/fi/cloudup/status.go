@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// SpecHash returns a hash of the cluster and instance group specs, as stored in the state store.
+func SpecHash(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) (string, error) {
+	specs := map[string]interface{}{
+		"cluster": cluster.Spec,
+	}
+	for _, ig := range instanceGroups {
+		specs["instancegroup/"+ig.Name] = ig.Spec
+	}
+
+	// encoding/json sorts the keys of maps, so the hash is stable
+	b, err := json.Marshal(specs)
+	if err != nil {
+		return "", fmt.Errorf("error hashing specs: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// appliesEverything returns true if the command applies all the tasks, rather than some phases only.
+func (c *ApplyClusterCmd) appliesEverything() bool {
+	if c.Phase != "" {
+		return false
+	}
+	for _, lifecycle := range c.PhaseLifecycles {
+		if lifecycle != fi.LifecycleSync {
+			return false
+		}
+	}
+	return true
+}
+
+// recordStatus records in the state store the outcome of applying the specs hashing to specHash.
+// The hash is only recorded once everything was applied, so it always identifies a spec the cluster is up to date with.
+func (c *ApplyClusterCmd) recordStatus(ctx context.Context, specHash string, applyErr error) error {
+	status, err := c.Clientset.GetRecordedStatus(ctx, c.Cluster)
+	if err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:    kops.ClusterConditionApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Applied",
+		Message: "The cluster spec was applied",
+	}
+	switch {
+	case applyErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ApplyFailed"
+		condition.Message = applyErr.Error()
+	case !c.appliesEverything():
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PartiallyApplied"
+		condition.Message = "Only some phases of the cluster spec were applied"
+		status.Cluster.AppliedKopsVersion = kopsbase.Version
+	default:
+		status.Cluster.AppliedSpecHash = specHash
+		status.Cluster.AppliedKopsVersion = kopsbase.Version
+	}
+	meta.SetStatusCondition(&status.Cluster.Conditions, condition)
+
+	return c.Clientset.UpdateRecordedStatus(ctx, c.Cluster, status)
+}
@@ -20,3 +20,4 @@ const TargetDirect = "direct"
 const TargetDryRun = "dryrun"
 const TargetTerraform = "terraform"
 const TargetCloudformation = "cloudformation"
+const TargetPulumi = "pulumi"
@@ -37,9 +37,11 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	kopscontrollerconfig "k8s.io/kops/cmd/kops-controller/pkg/config"
@@ -135,6 +137,9 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	dest["UseKopsControllerForNodeBootstrap"] = func() bool {
 		return tf.UseKopsControllerForNodeBootstrap()
 	}
+	dest["UseVaultPKI"] = func() bool {
+		return apiModel.UseVaultPKI(cluster)
+	}
 
 	dest["DO_TOKEN"] = func() string {
 		return os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
@@ -482,6 +487,33 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		config.CacheNodeidentityInfo = true
 	}
 
+	if spec := cluster.Spec.AutoApply; spec != nil {
+		autoApply := &kopscontrollerconfig.AutoApplyOptions{
+			ClusterName: cluster.ObjectMeta.Name,
+			Interval:    metav1.Duration{Duration: 10 * time.Minute},
+			Network:     kops.AutoApplyPolicyNever,
+			Security:    kops.AutoApplyPolicyNever,
+			Cluster:     kops.AutoApplyPolicyAuto,
+		}
+		if spec.Interval != nil {
+			autoApply.Interval = *spec.Interval
+		}
+		if spec.Network != "" {
+			autoApply.Network = spec.Network
+		}
+		if spec.Security != "" {
+			autoApply.Security = spec.Security
+		}
+		if spec.Cluster != "" {
+			autoApply.Cluster = spec.Cluster
+		}
+		config.AutoApply = autoApply
+	}
+
+	if cluster.Spec.Authentication != nil && cluster.Spec.Authentication.Users != nil {
+		config.PruneUserRoles = true
+	}
+
 	if tf.UseKopsControllerForNodeBootstrap() {
 		certNames := []string{"kubelet", "kubelet-server"}
 		signingCAs := []string{fi.CertificateIDCA}
@@ -505,6 +537,29 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 			SigningCAs:            signingCAs,
 			CertNames:             certNames,
 		}
+		if apiModel.UseVaultPKI(cluster) {
+			// kops-controller has the certificates of the CAs held by Vault issued by Vault
+			config.Server.KeyStore = cluster.Spec.KeyStore
+		}
+
+		if spec := cluster.Spec.NodeBootstrapAdmission; spec != nil {
+			admission := &kopscontrollerconfig.AdmissionOptions{
+				InstanceGroups:    spec.InstanceGroups,
+				RequestsPerMinute: int(spec.RequestsPerMinute),
+				Burst:             int(spec.Burst),
+				WebhookURL:        spec.WebhookURL,
+				WebhookTimeout:    spec.WebhookTimeout,
+			}
+			if spec.EnforceMaxSize {
+				admission.MaxNodes = make(map[string]int)
+				for _, ig := range tf.InstanceGroups {
+					if ig.Spec.MaxSize != nil && (ig.Spec.Role == kops.InstanceGroupRoleNode || ig.Spec.Role == kops.InstanceGroupRoleAPIServer) {
+						admission.MaxNodes[ig.Name] = int(*ig.Spec.MaxSize + spec.MaxSizeSurge)
+					}
+				}
+			}
+			config.Server.Admission = admission
+		}
 
 		switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
 		case kops.CloudProviderAWS:
@@ -103,6 +103,7 @@ func writeValue(body *hclwrite.Body, key string, value cty.Value) {
 // key = "value1"
 // key = res_type.res_name.res_prop
 // key = file("${module.path}/foo")
+// key = var.name["key"]
 func writeLiteral(body *hclwrite.Body, key string, literal *terraformWriter.Literal) {
 	if literal.FnName != "" {
 		tokens := hclwrite.Tokens{
@@ -112,6 +113,8 @@ func writeLiteral(body *hclwrite.Body, key string, literal *terraformWriter.Lite
 			},
 		}
 		body.SetAttributeRaw(key, tokens)
+	} else if literal.VariableName != "" {
+		body.SetAttributeTraversal(key, variableTraversal(literal.VariableName, literal.VariableKey))
 	} else if literal.ResourceType == "" || literal.ResourceName == "" || literal.ResourceProp == "" {
 		body.SetAttributeValue(key, cty.StringVal(literal.Value))
 	} else {
@@ -134,7 +137,11 @@ func writeLiteralList(body *hclwrite.Body, key string, literals []*terraformWrit
 		{Type: hclsyntax.TokenOBrack, Bytes: []byte("["), SpacesBefore: 1},
 	}
 	for i, literal := range literals {
-		if literal.ResourceType == "" || literal.ResourceName == "" || literal.ResourceProp == "" {
+		if literal.VariableName != "" {
+			variableTokens := hclwrite.TokensForTraversal(variableTraversal(literal.VariableName, literal.VariableKey))
+			variableTokens[0].SpacesBefore = 1
+			tokens = append(tokens, variableTokens...)
+		} else if literal.ResourceType == "" || literal.ResourceName == "" || literal.ResourceProp == "" {
 			tokens = append(tokens, []*hclwrite.Token{
 				{Type: hclsyntax.TokenOQuote, Bytes: []byte{'"'}, SpacesBefore: 1},
 				{Type: hclsyntax.TokenQuotedLit, Bytes: []byte(literal.Value)},
@@ -169,6 +176,11 @@ func writeMap(body *hclwrite.Body, key string, values map[string]cty.Value) {
 	if len(values) == 0 {
 		return
 	}
+	body.SetAttributeRaw(key, mapTokens(values))
+}
+
+// mapTokens returns the tokens of a map's key-value pairs spread across multiple lines, as written by writeMap.
+func mapTokens(values map[string]cty.Value) hclwrite.Tokens {
 	tokens := hclwrite.Tokens{
 		{Type: hclsyntax.TokenOBrace, Bytes: []byte("{"), SpacesBefore: 1},
 		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
@@ -218,5 +230,20 @@ func writeMap(body *hclwrite.Body, key string, values map[string]cty.Value) {
 	tokens = append(tokens,
 		&hclwrite.Token{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")},
 	)
-	body.SetAttributeRaw(key, tokens)
+	return tokens
+}
+
+// variableTraversal returns the traversal referring to an input variable, or to an element of a map input variable if key is not empty
+// Examples:
+// var.name
+// var.name["key"]
+func variableTraversal(name, key string) hcl.Traversal {
+	traversal := hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: name},
+	}
+	if key != "" {
+		traversal = append(traversal, hcl.TraverseIndex{Key: cty.StringVal(key)})
+	}
+	return traversal
 }
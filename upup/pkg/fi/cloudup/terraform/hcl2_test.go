@@ -136,6 +136,16 @@ func TestWriteLiteral(t *testing.T) {
 			literal:  terraformWriter.LiteralFunctionExpression("file", []string{"\"${path.module}/foo\""}),
 			expected: `foo = file("${path.module}/foo")`,
 		},
+		{
+			name:     "variable",
+			literal:  terraformWriter.LiteralVariableReference("vpc_id", ""),
+			expected: "foo = var.vpc_id",
+		},
+		{
+			name:     "map variable element",
+			literal:  terraformWriter.LiteralVariableReference("subnet_ids", "us-test-1a"),
+			expected: `foo = var.subnet_ids["us-test-1a"]`,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -201,6 +211,14 @@ func TestWriteLiteralList(t *testing.T) {
 			},
 			expected: `foo = [type.name.prop, "foobar"]`,
 		},
+		{
+			name: "variable literals",
+			literals: []*terraformWriter.Literal{
+				terraformWriter.LiteralVariableReference("subnet_ids", "us-test-1a"),
+				terraformWriter.LiteralVariableReference("subnet_id", ""),
+			},
+			expected: `foo = [var.subnet_ids["us-test-1a"], var.subnet_id]`,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// importScript is the name of the script importing the existing cloud resources
+const importScript = "import.sh"
+
+// Importable is implemented by tasks whose terraform resource can be imported from the existing cloud resource
+type Importable interface {
+	// TerraformImport returns the terraform resource of the task and the ID it is imported by,
+	// given the existing object found for the task, or nil if it can not be imported
+	TerraformImport(existing fi.Task) *terraformWriter.Import
+}
+
+// MultiImportable is implemented by tasks rendered as several terraform resources, all imported from the existing cloud resources
+type MultiImportable interface {
+	// TerraformImports returns the terraform resources of the task and the IDs they are imported by,
+	// given the existing object found for the task
+	TerraformImports(existing fi.Task) []*terraformWriter.Import
+}
+
+var _ fi.ObservingTarget = &TerraformTarget{}
+
+func (t *TerraformTarget) ObservesExisting() bool {
+	return t.EmitImports
+}
+
+func (t *TerraformTarget) ObserveExisting(a, e fi.Task) {
+	var imports []*terraformWriter.Import
+	switch importable := e.(type) {
+	case Importable:
+		imports = append(imports, importable.TerraformImport(a))
+	case MultiImportable:
+		imports = importable.TerraformImports(a)
+	default:
+		return
+	}
+	for _, i := range imports {
+		if i == nil || i.ID == "" {
+			continue
+		}
+		t.AddImport(i.ResourceType, i.ResourceName, i.ID)
+	}
+}
+
+// finishImports writes a script importing the existing cloud resources into the terraform state
+func (t *TerraformTarget) finishImports() error {
+	imports, err := t.GetImports()
+	if err != nil {
+		return err
+	}
+	t.Files[importScript] = buildImportScript(imports)
+	return nil
+}
+
+// buildImportScript returns a shell script running terraform import for every import
+func buildImportScript(imports []*terraformWriter.Import) []byte {
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Imports the existing cloud resources of the cluster into the terraform state, so that terraform does not recreate them.\n")
+	b.WriteString("# If the configuration is used as a module, set MODULE to the address of the module, e.g. MODULE=module.cluster\n")
+	b.WriteString("set -e\n")
+	if len(imports) > 0 {
+		b.WriteString("\n")
+	}
+	for _, i := range imports {
+		fmt.Fprintf(&b, "terraform import \"${MODULE:+${MODULE}.}%s.%s\" %s\n", i.ResourceType, i.ResourceName, shellQuote(i.ID))
+	}
+	return b.Bytes()
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// multiImportTask is a task rendered as a load balancer and its listener
+type multiImportTask struct{}
+
+func (*multiImportTask) Run(*fi.Context) error {
+	return nil
+}
+
+func (*multiImportTask) TerraformImports(existing fi.Task) []*terraformWriter.Import {
+	return []*terraformWriter.Import{
+		{ResourceType: "aws_lb", ResourceName: "api.example.com", ID: "arn:lb"},
+		{ResourceType: "aws_lb_listener", ResourceName: "api.example.com-443", ID: "arn:listener"},
+	}
+}
+
+type testResource struct {
+	Name *string `cty:"name"`
+}
+
+func TestObserveExistingMultiImportable(t *testing.T) {
+	target := NewTerraformTarget(nil, "", "", nil)
+	if err := target.RenderResource("aws_lb", "api.example.com", &testResource{Name: fi.String("api")}); err != nil {
+		t.Fatalf("RenderResource: %v", err)
+	}
+	if err := target.RenderResource("aws_lb_listener", "api.example.com-443", &testResource{}); err != nil {
+		t.Fatalf("RenderResource: %v", err)
+	}
+
+	task := &multiImportTask{}
+	target.ObserveExisting(task, task)
+
+	imports, err := target.GetImports()
+	if err != nil {
+		t.Fatalf("GetImports: %v", err)
+	}
+	var actual []string
+	for _, i := range imports {
+		actual = append(actual, i.ResourceType+"."+i.ResourceName+"="+i.ID)
+	}
+	expected := "aws_lb.api-example-com=arn:lb aws_lb_listener.api-example-com-443=arn:listener"
+	if strings.Join(actual, " ") != expected {
+		t.Errorf("expected imports %q, got %q", expected, strings.Join(actual, " "))
+	}
+}
+
+func TestBuildImportScript(t *testing.T) {
+	cases := []struct {
+		name     string
+		imports  []*terraformWriter.Import
+		expected string
+	}{
+		{
+			name: "no imports",
+			expected: `#!/bin/sh
+# Imports the existing cloud resources of the cluster into the terraform state, so that terraform does not recreate them.
+# If the configuration is used as a module, set MODULE to the address of the module, e.g. MODULE=module.cluster
+set -e
+`,
+		},
+		{
+			name: "imports",
+			imports: []*terraformWriter.Import{
+				{ResourceType: "aws_iam_role_policy", ResourceName: "nodes-minimal-example-com", ID: "nodes.minimal.example.com:nodes.minimal.example.com"},
+				{ResourceType: "aws_vpc", ResourceName: "minimal-example-com", ID: "vpc-12345678"},
+				{ResourceType: "aws_sqs_queue", ResourceName: "quoted", ID: "it's"},
+			},
+			expected: `#!/bin/sh
+# Imports the existing cloud resources of the cluster into the terraform state, so that terraform does not recreate them.
+# If the configuration is used as a module, set MODULE to the address of the module, e.g. MODULE=module.cluster
+set -e
+
+terraform import "${MODULE:+${MODULE}.}aws_iam_role_policy.nodes-minimal-example-com" 'nodes.minimal.example.com:nodes.minimal.example.com'
+terraform import "${MODULE:+${MODULE}.}aws_vpc.minimal-example-com" 'vpc-12345678'
+terraform import "${MODULE:+${MODULE}.}aws_sqs_queue.quoted" 'it'\''s'
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := string(buildImportScript(tc.imports))
+			if actual != tc.expected {
+				diffString := diff.FormatDiff(tc.expected, actual)
+				t.Logf("diff:\n%s\n", diffString)
+				t.Errorf("expected: '%s', got: '%s'\n", strings.TrimSpace(tc.expected), strings.TrimSpace(actual))
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+const (
+	// ModuleVariableVPCID is the input variable holding the ID of the shared VPC
+	ModuleVariableVPCID = "vpc_id"
+	// ModuleVariableSubnetIDs is the input variable holding the IDs of the shared subnets, by subnet name
+	ModuleVariableSubnetIDs = "subnet_ids"
+	// ModuleVariableInstanceTypes is the input variable holding the instance types, by instance group name
+	ModuleVariableInstanceTypes = "instance_types"
+	// moduleVariableTags is the input variable holding the tags added to the resources
+	moduleVariableTags = "tags"
+)
+
+var moduleVariableDescriptions = map[string]string{
+	ModuleVariableVPCID:         "The ID of the shared VPC of the cluster",
+	ModuleVariableSubnetIDs:     "The IDs of the shared subnets of the cluster, by subnet name",
+	ModuleVariableInstanceTypes: "The instance types of the instance groups, by instance group name",
+	moduleVariableTags:          "Additional tags for the resources of the cluster",
+}
+
+// moduleOutputs lists the attributes of the resources that are outputs of the module, by resource type
+var moduleOutputs = map[string][]string{
+	"aws_elb":            {"id", "dns_name"},
+	"aws_iam_role":       {"arn", "name"},
+	"aws_lb":             {"arn", "dns_name"},
+	"aws_security_group": {"id"},
+}
+
+// moduleReference refers to an input variable of the module, or to an element of a map input variable
+type moduleReference struct {
+	name string
+	key  string
+}
+
+// moduleAttribute identifies an attribute of a resource
+type moduleAttribute struct {
+	resourceType string
+	resourceName string
+	attribute    string
+}
+
+// moduleVariable is an input variable of the module
+type moduleVariable struct {
+	isMap bool
+	// defaults holds the default value of the variable, by key for map variables
+	defaults map[string]string
+}
+
+// IsModule returns true if the terraform configuration is written as a module
+func (t *TerraformTarget) IsModule() bool {
+	return tfGetModule(t.clusterSpecTarget) != nil
+}
+
+// AddModuleVariable replaces a literal value with an input variable when writing a module.
+// The value is the element key of a map variable, or the whole variable if key is empty.
+func (t *TerraformTarget) AddModuleVariable(name, key, value string) {
+	t.moduleValues[value] = moduleReference{name: name, key: key}
+}
+
+// AddModuleAttributeVariable replaces a resource attribute, given as a property literal, with an input variable when writing a module.
+// The default value of the variable is the value of the attribute.
+func (t *TerraformTarget) AddModuleAttributeVariable(name, key string, attribute *terraformWriter.Literal) {
+	t.moduleAttributes[moduleAttribute{
+		resourceType: attribute.ResourceType,
+		resourceName: attribute.ResourceName,
+		attribute:    attribute.ResourceProp,
+	}] = moduleReference{name: name, key: key}
+}
+
+// moduleVariables returns the input variables of the module, by name
+func (t *TerraformTarget) moduleVariables(resourcesByType map[string]map[string]interface{}) (map[string]*moduleVariable, error) {
+	variables := map[string]*moduleVariable{
+		moduleVariableTags: {isMap: true, defaults: map[string]string{}},
+	}
+	add := func(ref moduleReference, value string) error {
+		v := variables[ref.name]
+		if v == nil {
+			v = &moduleVariable{isMap: ref.key != "", defaults: map[string]string{}}
+			variables[ref.name] = v
+		}
+		if v.isMap != (ref.key != "") {
+			return fmt.Errorf("module variable %q is used both as a map and as a string", ref.name)
+		}
+		v.defaults[ref.key] = value
+		return nil
+	}
+
+	for value, ref := range t.moduleValues {
+		if err := add(ref, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for attribute, ref := range t.moduleAttributes {
+		item := resourcesByType[attribute.resourceType][attribute.resourceName]
+		if item == nil {
+			continue
+		}
+		resType, err := gocty.ImpliedType(item)
+		if err != nil {
+			return nil, err
+		}
+		resVal, err := gocty.ToCtyValue(item, resType)
+		if err != nil {
+			return nil, err
+		}
+		if resVal.IsNull() || !resType.IsObjectType() || !resType.HasAttribute(attribute.attribute) {
+			continue
+		}
+		value := resVal.GetAttr(attribute.attribute)
+		if value.IsNull() || !value.Type().Equals(cty.String) {
+			continue
+		}
+		if err := add(ref, value.AsString()); err != nil {
+			return nil, err
+		}
+	}
+
+	return variables, nil
+}
+
+// writeModuleVariables writes the variable blocks of the input variables of the module
+// Example:
+// variable "name" {
+//   type        = map(string)
+//   description = "description"
+//   default = {
+//     "key1" = "value1"
+//   }
+// }
+func writeModuleVariables(body *hclwrite.Body, variables map[string]*moduleVariable) {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := variables[name]
+		variableBody := body.AppendNewBlock("variable", []string{name}).Body()
+		variableType := "string"
+		if v.isMap {
+			variableType = "map(string)"
+		}
+		variableBody.SetAttributeRaw("type", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(variableType), SpacesBefore: 1},
+		})
+		if description := moduleVariableDescriptions[name]; description != "" {
+			variableBody.SetAttributeValue("description", cty.StringVal(description))
+		}
+		if !v.isMap {
+			variableBody.SetAttributeValue("default", cty.StringVal(v.defaults[""]))
+		} else if len(v.defaults) == 0 {
+			variableBody.SetAttributeValue("default", cty.MapValEmpty(cty.String))
+		} else {
+			defaults := make(map[string]cty.Value, len(v.defaults))
+			for k, value := range v.defaults {
+				defaults[k] = cty.StringVal(value)
+			}
+			writeMap(variableBody, "default", defaults)
+		}
+		body.AppendNewline()
+	}
+}
+
+// moduleLiteral returns the literal to write in place of a literal value replaced by an input variable of the module
+func (t *TerraformTarget) moduleLiteral(literal *terraformWriter.Literal) *terraformWriter.Literal {
+	if literal == nil || literal.FnName != "" || literal.ResourceType != "" || literal.VariableName != "" {
+		return literal
+	}
+	if ref, found := t.moduleValues[literal.Value]; found {
+		return terraformWriter.LiteralVariableReference(ref.name, ref.key)
+	}
+	return literal
+}
+
+// moduleValue replaces the literal values of a resource that are replaced by input variables of the module
+func (t *TerraformTarget) moduleValue(value cty.Value) (cty.Value, error) {
+	return cty.Transform(value, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if v.IsNull() || !v.Type().IsObjectType() {
+			return v, nil
+		}
+		literal := &terraformWriter.Literal{}
+		if err := gocty.FromCtyValue(v, literal); err != nil {
+			// Not a literal
+			return v, nil
+		}
+		replaced := t.moduleLiteral(literal)
+		if replaced == literal {
+			return v, nil
+		}
+		return gocty.ToCtyValue(replaced, v.Type())
+	})
+}
+
+// addModuleOutputs replaces the literal values of the outputs that are replaced by input variables of the module,
+// and adds outputs for the attributes of the resources listed in moduleOutputs
+func (t *TerraformTarget) addModuleOutputs(outputs map[string]terraformWriter.OutputValue, resourcesByType map[string]map[string]interface{}) error {
+	for name, output := range outputs {
+		output.Value = t.moduleLiteral(output.Value)
+		if output.ValueArray != nil {
+			values := make([]*terraformWriter.Literal, 0, len(output.ValueArray))
+			for _, literal := range output.ValueArray {
+				values = append(values, t.moduleLiteral(literal))
+			}
+			output.ValueArray = values
+		}
+		outputs[name] = output
+	}
+
+	for resourceType, attributes := range moduleOutputs {
+		for resourceName := range resourcesByType[resourceType] {
+			for _, attribute := range attributes {
+				name := resourceType + "_" + resourceName + "_" + attribute
+				if _, found := outputs[name]; found {
+					return fmt.Errorf("duplicate variable found: %s", name)
+				}
+				outputs[name] = terraformWriter.OutputValue{
+					Value: terraformWriter.LiteralProperty(resourceType, resourceName, attribute),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeModuleAttribute writes a resource attribute that is replaced by an input variable of the module,
+// returning false if the attribute is written as usual
+func (t *TerraformTarget) writeModuleAttribute(body *hclwrite.Body, resourceType, resourceName, key string, value cty.Value) bool {
+	if value.IsNull() {
+		return false
+	}
+	if ref, found := t.moduleAttributes[moduleAttribute{resourceType: resourceType, resourceName: resourceName, attribute: key}]; found {
+		body.SetAttributeTraversal(key, variableTraversal(ref.name, ref.key))
+		return true
+	}
+	if key == "tags" && value.Type().IsMapType() {
+		writeModuleTags(body, key, value)
+		return true
+	}
+	return false
+}
+
+// writeModuleTags writes the tags of a resource merged with the tags input variable of the module
+// Example:
+// tags = merge(var.tags, {
+//   "key1" = "value1"
+// })
+func writeModuleTags(body *hclwrite.Body, key string, value cty.Value) {
+	if value.LengthInt() == 0 {
+		body.SetAttributeTraversal(key, variableTraversal(moduleVariableTags, ""))
+		return
+	}
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte("merge"), SpacesBefore: 1},
+		{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+	}
+	tokens = append(tokens, hclwrite.TokensForTraversal(variableTraversal(moduleVariableTags, ""))...)
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+	tokens = append(tokens, mapTokens(value.AsValueMap())...)
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte(")")})
+	body.SetAttributeRaw(key, tokens)
+}
+
+// writeRequiredProviderAlias writes the requirements of a provider, including the alias of the provider configuration the module expects
+// Example:
+// aws = {
+//   "configuration_aliases" = [aws.alias]
+//   "source"                = "hashicorp/aws"
+//   "version"               = ">= 3.34.0"
+// }
+func writeRequiredProviderAlias(body *hclwrite.Body, key string, values map[string]cty.Value, alias hcl.Traversal) {
+	tokens := mapTokens(values)
+	aliasTokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenOQuote, Bytes: []byte{'"'}, SpacesBefore: 1},
+		{Type: hclsyntax.TokenQuotedLit, Bytes: []byte("configuration_aliases")},
+		{Type: hclsyntax.TokenCQuote, Bytes: []byte{'"'}, SpacesBefore: 1},
+		{Type: hclsyntax.TokenEqual, Bytes: []byte("="), SpacesBefore: 1},
+		{Type: hclsyntax.TokenOBrack, Bytes: []byte("["), SpacesBefore: 1},
+	}
+	aliasTokens = append(aliasTokens, hclwrite.TokensForTraversal(alias)...)
+	aliasTokens = append(aliasTokens,
+		&hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")},
+		&hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+	)
+
+	// The entries of the map follow its opening brace and newline
+	withAlias := append(hclwrite.Tokens{}, tokens[:2]...)
+	withAlias = append(withAlias, aliasTokens...)
+	withAlias = append(withAlias, tokens[2:]...)
+	body.SetAttributeRaw(key, withAlias)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+type testModuleResource struct {
+	InstanceType *string                    `cty:"instance_type"`
+	Subnets      []*terraformWriter.Literal `cty:"subnets"`
+	Tags         map[string]string          `cty:"tags"`
+	VPCID        *terraformWriter.Literal   `cty:"vpc_id"`
+}
+
+func newTestModuleTarget() *TerraformTarget {
+	target := NewTerraformTarget(nil, "", "", nil)
+	target.AddModuleVariable(ModuleVariableVPCID, "", "vpc-12345678")
+	target.AddModuleVariable(ModuleVariableSubnetIDs, "us-test-1a", "subnet-12345678")
+	target.AddModuleAttributeVariable(ModuleVariableInstanceTypes, "nodes", terraformWriter.LiteralProperty("aws_launch_template", "nodes.example.com", "instance_type"))
+	return target
+}
+
+func TestWriteModuleResource(t *testing.T) {
+	target := newTestModuleTarget()
+	resource := &testModuleResource{
+		InstanceType: fi.String("t3.medium"),
+		Subnets: []*terraformWriter.Literal{
+			terraformWriter.LiteralFromStringValue("subnet-12345678"),
+			terraformWriter.LiteralProperty("aws_subnet", "us-test-1b.example.com", "id"),
+		},
+		Tags: map[string]string{
+			"KubernetesCluster": "example.com",
+		},
+		VPCID: terraformWriter.LiteralFromStringValue("vpc-12345678"),
+	}
+	resourcesByType := map[string]map[string]interface{}{
+		"aws_launch_template": {
+			"nodes-example-com": resource,
+		},
+	}
+
+	variables, err := target.moduleVariables(resourcesByType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+	writeModuleVariables(root, variables)
+
+	resType, err := gocty.ImpliedType(resource)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resVal, err := gocty.ToCtyValue(resource, resType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resVal, err = target.moduleValue(resVal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resBody := root.AppendNewBlock("resource", []string{"aws_launch_template", "nodes-example-com"}).Body()
+	resVal.ForEachElement(func(key cty.Value, value cty.Value) bool {
+		if !target.writeModuleAttribute(resBody, "aws_launch_template", "nodes-example-com", key.AsString(), value) {
+			writeValue(resBody, key.AsString(), value)
+		}
+		return false
+	})
+
+	expected := `
+variable "instance_types" {
+  type        = map(string)
+  description = "The instance types of the instance groups, by instance group name"
+  default = {
+    "nodes" = "t3.medium"
+  }
+}
+
+variable "subnet_ids" {
+  type        = map(string)
+  description = "The IDs of the shared subnets of the cluster, by subnet name"
+  default = {
+    "us-test-1a" = "subnet-12345678"
+  }
+}
+
+variable "tags" {
+  type        = map(string)
+  description = "Additional tags for the resources of the cluster"
+  default     = {}
+}
+
+variable "vpc_id" {
+  type        = string
+  description = "The ID of the shared VPC of the cluster"
+  default     = "vpc-12345678"
+}
+
+resource "aws_launch_template" "nodes-example-com" {
+  instance_type = var.instance_types["nodes"]
+  subnets       = [var.subnet_ids["us-test-1a"], aws_subnet.us-test-1b-example-com.id]
+  tags = merge(var.tags, {
+    "KubernetesCluster" = "example.com"
+  })
+  vpc_id = var.vpc_id
+}`
+	actual := strings.TrimSpace(string(hclwrite.Format(f.Bytes())))
+	expected = strings.TrimSpace(expected)
+	if actual != expected {
+		diffString := diff.FormatDiff(expected, actual)
+		t.Logf("diff:\n%s\n", diffString)
+		t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+	}
+}
+
+func TestAddModuleOutputs(t *testing.T) {
+	target := newTestModuleTarget()
+	outputs := map[string]terraformWriter.OutputValue{
+		"vpc_id": {
+			Value: terraformWriter.LiteralFromStringValue("vpc-12345678"),
+		},
+		"subnet_ids": {
+			ValueArray: []*terraformWriter.Literal{
+				terraformWriter.LiteralFromStringValue("subnet-12345678"),
+			},
+		},
+	}
+	resourcesByType := map[string]map[string]interface{}{
+		"aws_security_group": {
+			"nodes-example-com": struct{}{},
+		},
+		"aws_subnet": {
+			"us-test-1b-example-com": struct{}{},
+		},
+	}
+
+	if err := target.addModuleOutputs(outputs, resourcesByType); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+	if err := writeLocalsOutputs(root, outputs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+locals {
+  aws_security_group_nodes-example-com_id = aws_security_group.nodes-example-com.id
+  subnet_ids                              = [var.subnet_ids["us-test-1a"]]
+  vpc_id                                  = var.vpc_id
+}
+
+output "aws_security_group_nodes-example-com_id" {
+  value = aws_security_group.nodes-example-com.id
+}
+
+output "subnet_ids" {
+  value = [var.subnet_ids["us-test-1a"]]
+}
+
+output "vpc_id" {
+  value = var.vpc_id
+}`
+	actual := strings.TrimSpace(string(hclwrite.Format(f.Bytes())))
+	expected = strings.TrimSpace(expected)
+	if actual != expected {
+		diffString := diff.FormatDiff(expected, actual)
+		t.Logf("diff:\n%s\n", diffString)
+		t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+	}
+}
+
+func TestWriteRequiredProviderAlias(t *testing.T) {
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+	alias := hcl.Traversal{
+		hcl.TraverseRoot{Name: "aws"},
+		hcl.TraverseAttr{Name: "cluster"},
+	}
+	writeRequiredProviderAlias(root, "aws", map[string]cty.Value{
+		"source":  cty.StringVal("hashicorp/aws"),
+		"version": cty.StringVal(">= 3.34.0"),
+	}, alias)
+
+	expected := `
+aws = {
+  "configuration_aliases" = [aws.cluster]
+  "source"                = "hashicorp/aws"
+  "version"               = ">= 3.34.0"
+}`
+	actual := strings.TrimSpace(string(hclwrite.Format(f.Bytes())))
+	expected = strings.TrimSpace(expected)
+	if actual != expected {
+		diffString := diff.FormatDiff(expected, actual)
+		t.Logf("diff:\n%s\n", diffString)
+		t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+	}
+}
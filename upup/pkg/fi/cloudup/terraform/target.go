@@ -36,9 +36,17 @@ type TerraformTarget struct {
 
 	ClusterName string
 
+	// EmitImports writes a script importing the existing cloud resources into the terraform state
+	EmitImports bool
+
 	outDir string
 	// extra config to add to the provider block
 	clusterSpecTarget *kops.TargetSpec
+
+	// moduleValues maps the literal values replaced by input variables, when writing a module
+	moduleValues map[string]moduleReference
+	// moduleAttributes maps the resource attributes replaced by input variables, when writing a module
+	moduleAttributes map[moduleAttribute]moduleReference
 }
 
 func NewTerraformTarget(cloud fi.Cloud, project string, outDir string, clusterSpecTarget *kops.TargetSpec) *TerraformTarget {
@@ -48,6 +56,9 @@ func NewTerraformTarget(cloud fi.Cloud, project string, outDir string, clusterSp
 
 		outDir:            outDir,
 		clusterSpecTarget: clusterSpecTarget,
+
+		moduleValues:     make(map[string]moduleReference),
+		moduleAttributes: make(map[moduleAttribute]moduleReference),
 	}
 	target.InitTerraformWriter()
 	return &target
@@ -80,9 +91,20 @@ func tfGetProviderExtraConfig(c *kops.TargetSpec) map[string]string {
 	return nil
 }
 
+// tfGetModule is a helper function to get the module config with safety checks on the pointers.
+func tfGetModule(c *kops.TargetSpec) *kops.TerraformModuleSpec {
+	if c != nil && c.Terraform != nil {
+		return c.Terraform.Module
+	}
+	return nil
+}
+
 func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 	var err error
 	if featureflag.TerraformJSON.Enabled() {
+		if t.IsModule() {
+			return fmt.Errorf("terraform modules are not supported with the TerraformJSON feature flag")
+		}
 		err = t.finishJSON()
 	} else {
 		err = t.finishHCL2()
@@ -91,6 +113,12 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 		return err
 	}
 
+	if t.EmitImports {
+		if err := t.finishImports(); err != nil {
+			return err
+		}
+	}
+
 	for relativePath, contents := range t.Files {
 		p := path.Join(t.outDir, relativePath)
 
@@ -19,7 +19,9 @@ package terraform
 import (
 	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
@@ -32,30 +34,51 @@ func (t *TerraformTarget) finishHCL2() error {
 	f := hclwrite.NewEmptyFile()
 	rootBody := f.Body()
 
+	module := tfGetModule(t.clusterSpecTarget)
+
+	resourcesByType, err := t.GetResourcesByType()
+	if err != nil {
+		return err
+	}
+
 	outputs, err := t.GetOutputs()
 	if err != nil {
 		return err
 	}
+	if module != nil {
+		variables, err := t.moduleVariables(resourcesByType)
+		if err != nil {
+			return err
+		}
+		writeModuleVariables(rootBody, variables)
+		if err := t.addModuleOutputs(outputs, resourcesByType); err != nil {
+			return err
+		}
+	}
 	writeLocalsOutputs(rootBody, outputs)
 
 	providerName := string(t.Cloud.ProviderID())
 	if t.Cloud.ProviderID() == kops.CloudProviderGCE {
 		providerName = "google"
 	}
-	providerBlock := rootBody.AppendNewBlock("provider", []string{providerName})
-	providerBody := providerBlock.Body()
-	if t.Cloud.ProviderID() == kops.CloudProviderGCE {
-		providerBody.SetAttributeValue("project", cty.StringVal(t.Project))
-	}
-	providerBody.SetAttributeValue("region", cty.StringVal(t.Cloud.Region()))
-	for k, v := range tfGetProviderExtraConfig(t.clusterSpecTarget) {
-		providerBody.SetAttributeValue(k, cty.StringVal(v))
-	}
-	rootBody.AppendNewline()
-
-	resourcesByType, err := t.GetResourcesByType()
-	if err != nil {
-		return err
+	// A module is passed its provider configurations by the configuration using it
+	var providerAlias hcl.Traversal
+	if module == nil {
+		providerBlock := rootBody.AppendNewBlock("provider", []string{providerName})
+		providerBody := providerBlock.Body()
+		if t.Cloud.ProviderID() == kops.CloudProviderGCE {
+			providerBody.SetAttributeValue("project", cty.StringVal(t.Project))
+		}
+		providerBody.SetAttributeValue("region", cty.StringVal(t.Cloud.Region()))
+		for k, v := range tfGetProviderExtraConfig(t.clusterSpecTarget) {
+			providerBody.SetAttributeValue(k, cty.StringVal(v))
+		}
+		rootBody.AppendNewline()
+	} else if module.ProviderAlias != "" {
+		providerAlias = hcl.Traversal{
+			hcl.TraverseRoot{Name: providerName},
+			hcl.TraverseAttr{Name: module.ProviderAlias},
+		}
 	}
 
 	resourceTypes := make([]string, 0, len(resourcesByType))
@@ -86,7 +109,19 @@ func (t *TerraformTarget) finishHCL2() error {
 			if resVal.IsNull() {
 				continue
 			}
+			if module != nil {
+				resVal, err = t.moduleValue(resVal)
+				if err != nil {
+					return err
+				}
+				if providerAlias != nil && strings.HasPrefix(resourceType, providerName+"_") {
+					resBody.SetAttributeTraversal("provider", providerAlias)
+				}
+			}
 			resVal.ForEachElement(func(key cty.Value, value cty.Value) bool {
+				if module != nil && t.writeModuleAttribute(resBody, resourceType, resourceName, key.AsString(), value) {
+					return false
+				}
 				writeValue(resBody, key.AsString(), value)
 				return false
 			})
@@ -96,26 +131,44 @@ func (t *TerraformTarget) finishHCL2() error {
 
 	terraformBlock := rootBody.AppendNewBlock("terraform", []string{})
 	terraformBody := terraformBlock.Body()
-	terraformBody.SetAttributeValue("required_version", cty.StringVal(">= 0.12.26"))
+	if providerAlias != nil {
+		// configuration_aliases was introduced in terraform 0.15
+		terraformBody.SetAttributeValue("required_version", cty.StringVal(">= 0.15.0"))
+	} else {
+		terraformBody.SetAttributeValue("required_version", cty.StringVal(">= 0.12.26"))
+	}
 
 	requiredProvidersBlock := terraformBody.AppendNewBlock("required_providers", []string{})
 	requiredProvidersBody := requiredProvidersBlock.Body()
 
+	requiredProviders := make(map[string]map[string]cty.Value)
 	if t.Cloud.ProviderID() == kops.CloudProviderGCE {
-		writeMap(requiredProvidersBody, "google", map[string]cty.Value{
+		requiredProviders["google"] = map[string]cty.Value{
 			"source":  cty.StringVal("hashicorp/google"),
 			"version": cty.StringVal(">= 2.19.0"),
-		})
+		}
 	} else if t.Cloud.ProviderID() == kops.CloudProviderAWS {
-		writeMap(requiredProvidersBody, "aws", map[string]cty.Value{
+		requiredProviders["aws"] = map[string]cty.Value{
 			"source":  cty.StringVal("hashicorp/aws"),
 			"version": cty.StringVal(">= 3.34.0"),
-		})
+		}
 		if featureflag.Spotinst.Enabled() {
-			writeMap(requiredProvidersBody, "spotinst", map[string]cty.Value{
+			requiredProviders["spotinst"] = map[string]cty.Value{
 				"source":  cty.StringVal("spotinst/spotinst"),
 				"version": cty.StringVal(">= 1.33.0"),
-			})
+			}
+		}
+	}
+	requiredProviderNames := make([]string, 0, len(requiredProviders))
+	for name := range requiredProviders {
+		requiredProviderNames = append(requiredProviderNames, name)
+	}
+	sort.Strings(requiredProviderNames)
+	for _, name := range requiredProviderNames {
+		if name == providerName && providerAlias != nil {
+			writeRequiredProviderAlias(requiredProvidersBody, name, requiredProviders[name], providerAlias)
+		} else {
+			writeMap(requiredProvidersBody, name, requiredProviders[name])
 		}
 	}
 
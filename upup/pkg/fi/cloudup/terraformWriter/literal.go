@@ -43,6 +43,11 @@ type Literal struct {
 	// FnArgs contains string representations of arguments to the function call.
 	// Any string arguments must be quoted.
 	FnArgs []string `cty:"fn_arg"`
+
+	// VariableName represents the name of an input variable in a literal reference
+	VariableName string `cty:"variable_name"`
+	// VariableKey represents the key of the element of a map input variable in a literal reference
+	VariableKey string `cty:"variable_key"`
 }
 
 var _ json.Marshaler = &Literal{}
@@ -74,6 +79,19 @@ func LiteralProperty(resourceType, resourceName, prop string) *Literal {
 	}
 }
 
+// LiteralVariableReference refers to an input variable, or to an element of a map input variable if key is not empty
+func LiteralVariableReference(name, key string) *Literal {
+	expr := "var." + name
+	if key != "" {
+		expr += fmt.Sprintf("[%q]", key)
+	}
+	return &Literal{
+		Value:        "${" + expr + "}",
+		VariableName: name,
+		VariableKey:  key,
+	}
+}
+
 func LiteralFromStringValue(s string) *Literal {
 	return &Literal{Value: s}
 }
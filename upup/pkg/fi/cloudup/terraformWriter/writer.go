@@ -19,6 +19,7 @@ package terraformWriter
 import (
 	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,6 +32,8 @@ type TerraformWriter struct {
 	resources []*terraformResource
 	// outputs is a list of our TF output variables
 	outputs map[string]*terraformOutputVariable
+	// imports is a list of the existing cloud resources that TF resources are imported from
+	imports []*Import
 	// Files is a map of TF resource Files that should be created
 	Files map[string][]byte
 }
@@ -40,6 +43,14 @@ type OutputValue struct {
 	ValueArray []*Literal
 }
 
+// Import identifies the existing cloud resource a TF resource is imported from
+type Import struct {
+	ResourceType string
+	ResourceName string
+	// ID is the ID terraform imports the resource by
+	ID string
+}
+
 type terraformResource struct {
 	ResourceType string
 	ResourceName string
@@ -174,3 +185,52 @@ func (t *TerraformWriter) GetOutputs() (map[string]OutputValue, error) {
 	}
 	return values, nil
 }
+
+// AddImport records that a TF resource is to be imported from an existing cloud resource
+func (t *TerraformWriter) AddImport(resourceType string, resourceName string, id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.imports = append(t.imports, &Import{
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		ID:           id,
+	})
+}
+
+// GetImports returns the imports of the TF resources that are rendered, sorted by resource address
+func (t *TerraformWriter) GetImports() ([]*Import, error) {
+	rendered := make(map[string]bool)
+	for _, res := range t.resources {
+		rendered[res.ResourceType+"."+tfSanitize(res.ResourceName)] = true
+	}
+
+	importsByAddress := make(map[string]*Import)
+	for _, i := range t.imports {
+		tfName := tfSanitize(i.ResourceName)
+		address := i.ResourceType + "." + tfName
+		if !rendered[address] {
+			continue
+		}
+		if existing := importsByAddress[address]; existing != nil && existing.ID != i.ID {
+			return nil, fmt.Errorf("resource %s is imported from both %q and %q", address, existing.ID, i.ID)
+		}
+		importsByAddress[address] = &Import{
+			ResourceType: i.ResourceType,
+			ResourceName: tfName,
+			ID:           i.ID,
+		}
+	}
+
+	addresses := make([]string, 0, len(importsByAddress))
+	for address := range importsByAddress {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	imports := make([]*Import, 0, len(addresses))
+	for _, address := range addresses {
+		imports = append(imports, importsByAddress[address])
+	}
+	return imports, nil
+}
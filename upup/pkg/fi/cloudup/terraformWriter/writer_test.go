@@ -118,3 +118,27 @@ func TestGetOutputs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetImports(t *testing.T) {
+	target := TerraformWriter{}
+	target.InitTerraformWriter()
+	require.NoError(t, target.RenderResource("aws_vpc", "minimal.example.com", struct{}{}))
+	require.NoError(t, target.RenderResource("aws_subnet", "us-test-1a.minimal.example.com", struct{}{}))
+
+	target.AddImport("aws_vpc", "minimal.example.com", "vpc-12345678")
+	target.AddImport("aws_subnet", "us-test-1a.minimal.example.com", "subnet-12345678")
+	target.AddImport("aws_subnet", "us-test-1a.minimal.example.com", "subnet-12345678")
+	// Not rendered, e.g. because it is shared
+	target.AddImport("aws_internet_gateway", "minimal.example.com", "igw-12345678")
+
+	actual, err := target.GetImports()
+	require.NoError(t, err)
+	assert.Equal(t, []*Import{
+		{ResourceType: "aws_subnet", ResourceName: "us-test-1a-minimal-example-com", ID: "subnet-12345678"},
+		{ResourceType: "aws_vpc", ResourceName: "minimal-example-com", ID: "vpc-12345678"},
+	}, actual)
+
+	target.AddImport("aws_vpc", "minimal.example.com", "vpc-87654321")
+	_, err = target.GetImports()
+	assert.Error(t, err, "a resource can only be imported from one cloud resource")
+}
@@ -43,6 +43,10 @@ type Context struct {
 
 	CheckExisting bool
 
+	// KeepCheckedDeletions is whether the tasks that are only checked, with the ExistsAndValidates
+	// or ExistsAndWarnIfChanges lifecycles, leave the extra objects they find in place.
+	KeepCheckedDeletions bool
+
 	tasks map[string]Task
 
 	warnings []*Warning
@@ -127,11 +131,13 @@ func (c *Context) Render(a, e, changes Task) error {
 			case LifecycleExistsAndValidates:
 				return fmt.Errorf("lifecycle set to ExistsAndValidates, but object was not found")
 			case LifecycleExistsAndWarnIfChanges:
+				c.recordUnapplied(e)
 				return NewExistsAndWarnIfChangesError("Lifecycle set to ExistsAndWarnIfChanges and object was not found.")
 			}
 		} else {
 			switch lifecycle {
 			case LifecycleExistsAndValidates, LifecycleExistsAndWarnIfChanges:
+				c.recordUnapplied(e)
 
 				out := os.Stderr
 				changeList, err := buildChangeList(a, e, changes)
@@ -232,6 +238,13 @@ func (c *Context) Render(a, e, changes Task) error {
 	return rvErr
 }
 
+// recordUnapplied records the changes to a task that is only checked, when running against a DryRunTarget.
+func (c *Context) recordUnapplied(e Task) {
+	if dryRunTarget, ok := c.Target.(*DryRunTarget); ok {
+		dryRunTarget.recordUnapplied(e)
+	}
+}
+
 // AddWarning records a warning encountered during validation / creation.
 // Typically this will be an error that we choose to ignore because of Lifecycle.
 func (c *Context) AddWarning(task Task, message string) {
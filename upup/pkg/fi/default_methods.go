@@ -57,6 +57,24 @@ func DefaultDeltaRunMethod(e Task, c *Context) error {
 			}
 			return err
 		}
+
+		if dryRunTarget, ok := c.Target.(*DryRunTarget); ok {
+			dryRunTarget.observe(a, e)
+		}
+	} else if observer, ok := c.Target.(ObservingTarget); ok && observer.ObservesExisting() {
+		// The task is still rendered as if it did not exist
+		existing, err := invokeFind(e, c)
+		if err != nil {
+			if lifecycle != LifecycleWarnIfInsufficientAccess {
+				return err
+			}
+			c.AddWarning(e, fmt.Sprintf("error checking if task exists; assuming it does not exist: %v", err))
+		} else if existing != nil {
+			observer.ObserveExisting(existing, e)
+		}
+	} else if dryRunTarget, ok := c.Target.(*DryRunTarget); ok {
+		// The task reported that there is no existing object to check
+		dryRunTarget.observe(nil, e)
 	}
 
 	if a == nil {
@@ -92,6 +110,12 @@ func DefaultDeltaRunMethod(e Task, c *Context) error {
 		if err != nil {
 			return err
 		}
+		if c.KeepCheckedDeletions && (lifecycle == LifecycleExistsAndValidates || lifecycle == LifecycleExistsAndWarnIfChanges) {
+			if len(deletions) != 0 {
+				c.recordUnapplied(e)
+			}
+			return nil
+		}
 		for _, deletion := range deletions {
 			if _, ok := c.Target.(*DryRunTarget); ok {
 				err = c.Target.(*DryRunTarget).Delete(deletion)
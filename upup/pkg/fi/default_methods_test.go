@@ -0,0 +1,228 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+)
+
+// deletingTask is a task that exists as expected, and finds an extra object to delete.
+type deletingTask struct {
+	Name      *string
+	Lifecycle Lifecycle
+}
+
+var _ Task = &deletingTask{}
+var _ HasLifecycle = &deletingTask{}
+var _ HasName = &deletingTask{}
+var _ ProducesDeletions = &deletingTask{}
+
+func (t *deletingTask) Run(c *Context) error {
+	return DefaultDeltaRunMethod(t, c)
+}
+
+func (t *deletingTask) Find(_ *Context) (*deletingTask, error) {
+	actual := *t
+	return &actual, nil
+}
+
+func (t *deletingTask) FindDeletions(_ *Context) ([]Deletion, error) {
+	return []Deletion{&testDeletion{item: "extra"}}, nil
+}
+
+func (t *deletingTask) GetName() *string {
+	return t.Name
+}
+
+func (t *deletingTask) GetLifecycle() Lifecycle {
+	return t.Lifecycle
+}
+
+func (t *deletingTask) SetLifecycle(lifecycle Lifecycle) {
+	t.Lifecycle = lifecycle
+}
+
+func Test_DefaultDeltaRunMethod_Deletions(t *testing.T) {
+	grid := []struct {
+		Lifecycle            Lifecycle
+		KeepCheckedDeletions bool
+		Deleted              bool
+		Unapplied            bool
+	}{
+		{
+			Lifecycle: LifecycleSync,
+			Deleted:   true,
+		},
+		{
+			Lifecycle:            LifecycleSync,
+			KeepCheckedDeletions: true,
+			Deleted:              true,
+		},
+		{
+			Lifecycle: LifecycleExistsAndWarnIfChanges,
+			Deleted:   true,
+		},
+		{
+			Lifecycle: LifecycleExistsAndValidates,
+			Deleted:   true,
+		},
+		{
+			Lifecycle:            LifecycleExistsAndWarnIfChanges,
+			KeepCheckedDeletions: true,
+			Deleted:              false,
+			Unapplied:            true,
+		},
+		{
+			Lifecycle:            LifecycleExistsAndValidates,
+			KeepCheckedDeletions: true,
+			Deleted:              false,
+			Unapplied:            true,
+		},
+	}
+	for _, g := range grid {
+		builder := assets.NewAssetBuilder(&api.Cluster{
+			Spec: api.ClusterSpec{
+				KubernetesVersion: "1.17.3",
+			},
+		}, false)
+		target := NewDryRunTarget(builder, &bytes.Buffer{})
+
+		task := &deletingTask{
+			Name:      String("task"),
+			Lifecycle: g.Lifecycle,
+		}
+		tasks := map[string]Task{"deletingTask/task": task}
+		c, err := NewContext(target, nil, nil, nil, nil, nil, true, tasks)
+		if !assert.NoError(t, err, "NewContext()") {
+			continue
+		}
+		c.KeepCheckedDeletions = g.KeepCheckedDeletions
+
+		assert.NoError(t, task.Run(c), "%s: Run()", g.Lifecycle)
+		c.Close()
+
+		report, err := target.Report(tasks)
+		if !assert.NoError(t, err, "target.Report()") {
+			continue
+		}
+		var deleted bool
+		for _, change := range report.Changes {
+			if change.Action == DryRunActionDelete && change.Name == "extra" {
+				deleted = true
+			}
+		}
+		assert.Equal(t, g.Deleted, deleted, "%s with KeepCheckedDeletions=%t: extra object deleted", g.Lifecycle, g.KeepCheckedDeletions)
+
+		var expectedUnapplied []string
+		if g.Unapplied {
+			expectedUnapplied = []string{"deletingTask/task"}
+		}
+		assert.Equal(t, expectedUnapplied, target.UnappliedChanges(), "%s with KeepCheckedDeletions=%t: unapplied changes", g.Lifecycle, g.KeepCheckedDeletions)
+	}
+}
+
+// changingTask is a task whose object exists, but differs from the task.
+type changingTask struct {
+	Name      *string
+	Lifecycle Lifecycle
+	Value     *string
+}
+
+var _ Task = &changingTask{}
+var _ HasLifecycle = &changingTask{}
+var _ HasName = &changingTask{}
+
+func (t *changingTask) Run(c *Context) error {
+	return DefaultDeltaRunMethod(t, c)
+}
+
+func (t *changingTask) Find(_ *Context) (*changingTask, error) {
+	actual := *t
+	actual.Value = String("actual")
+	return &actual, nil
+}
+
+func (t *changingTask) CheckChanges(a, e, changes *changingTask) error {
+	return nil
+}
+
+func (t *changingTask) GetName() *string {
+	return t.Name
+}
+
+func (t *changingTask) GetLifecycle() Lifecycle {
+	return t.Lifecycle
+}
+
+func (t *changingTask) SetLifecycle(lifecycle Lifecycle) {
+	t.Lifecycle = lifecycle
+}
+
+func Test_DefaultDeltaRunMethod_UnappliedChanges(t *testing.T) {
+	grid := []struct {
+		Lifecycle Lifecycle
+		Changed   bool
+		Unapplied bool
+	}{
+		{
+			Lifecycle: LifecycleSync,
+			Changed:   true,
+		},
+		{
+			Lifecycle: LifecycleExistsAndWarnIfChanges,
+			Unapplied: true,
+		},
+		{
+			Lifecycle: LifecycleIgnore,
+		},
+	}
+	for _, g := range grid {
+		builder := assets.NewAssetBuilder(&api.Cluster{
+			Spec: api.ClusterSpec{
+				KubernetesVersion: "1.17.3",
+			},
+		}, false)
+		target := NewDryRunTarget(builder, &bytes.Buffer{})
+
+		task := &changingTask{
+			Name:      String("task"),
+			Lifecycle: g.Lifecycle,
+			Value:     String("expected"),
+		}
+		c, err := NewContext(target, nil, nil, nil, nil, nil, true, map[string]Task{"changingTask/task": task})
+		if !assert.NoError(t, err, "NewContext()") {
+			continue
+		}
+
+		assert.NoError(t, task.Run(c), "%s: Run()", g.Lifecycle)
+		c.Close()
+
+		_, updated := target.Changes()
+		assert.Equal(t, g.Changed, len(updated) != 0, "%s: changed", g.Lifecycle)
+
+		var expectedUnapplied []string
+		if g.Unapplied {
+			expectedUnapplied = []string{"changingTask/task"}
+		}
+		assert.Equal(t, expectedUnapplied, target.UnappliedChanges(), "%s: unapplied changes", g.Lifecycle)
+	}
+}
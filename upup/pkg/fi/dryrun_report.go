@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"sort"
+)
+
+// DryRunAction is what would be done to an object.
+type DryRunAction string
+
+const (
+	DryRunActionCreate DryRunAction = "create"
+	DryRunActionModify DryRunAction = "modify"
+	DryRunActionDelete DryRunAction = "delete"
+)
+
+// DryRunReport is the structured form of the report printed by a DryRunTarget,
+// suitable for checking the changes against policies.
+type DryRunReport struct {
+	// Changes are the objects that would be created, modified or deleted.
+	Changes []*DryRunReportChange `json:"changes"`
+}
+
+// DryRunReportChange is an object that would be created, modified or deleted.
+type DryRunReportChange struct {
+	// Key is the key of the task, e.g. SecurityGroup/nodes.example.com
+	Key string `json:"key"`
+	// Type is the type of the task, e.g. SecurityGroup
+	Type string `json:"type"`
+	// Name is the name of the task, or the item to be deleted
+	Name string `json:"name"`
+	// Lifecycle is the lifecycle of the task; it is not known for deletions
+	Lifecycle Lifecycle `json:"lifecycle,omitempty"`
+	// Action is what would be done to the object
+	Action DryRunAction `json:"action"`
+	// Fields are the fields that would be set
+	Fields []*DryRunReportField `json:"fields,omitempty"`
+}
+
+// DryRunReportField is a field that would be set.
+type DryRunReportField struct {
+	Name string `json:"name"`
+	// Old is the current value, empty for objects that would be created
+	Old string `json:"old,omitempty"`
+	// New is the value that would be set
+	New string `json:"new,omitempty"`
+}
+
+// Report returns the changes that would be made, in the same order as PrintReport prints them.
+func (t *DryRunTarget) Report(taskMap map[string]Task) (*DryRunReport, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var creates []*render
+	var updates []*render
+	for _, r := range t.changes {
+		if r.aIsNil {
+			creates = append(creates, r)
+		} else {
+			updates = append(updates, r)
+		}
+	}
+
+	// Give everything a consistent ordering
+	sort.Sort(ByTaskKey(creates))
+	sort.Sort(ByTaskKey(updates))
+
+	report := &DryRunReport{
+		Changes: []*DryRunReportChange{},
+	}
+
+	for _, r := range creates {
+		report.Changes = append(report.Changes, newDryRunReportChange(taskMap, r, DryRunActionCreate, buildCreateList(r.changes)))
+	}
+
+	for _, r := range updates {
+		changeList, err := buildChangeList(r.a, r.e, r.changes)
+		if err != nil {
+			return nil, err
+		}
+		report.Changes = append(report.Changes, newDryRunReportChange(taskMap, r, DryRunActionModify, changeList))
+	}
+
+	deletions := append([]Deletion(nil), t.deletions...)
+	sort.Sort(DeletionByTaskName(deletions))
+	for _, d := range deletions {
+		report.Changes = append(report.Changes, &DryRunReportChange{
+			Key:    d.TaskName() + "/" + d.Item(),
+			Type:   d.TaskName(),
+			Name:   d.Item(),
+			Action: DryRunActionDelete,
+		})
+	}
+
+	return report, nil
+}
+
+func newDryRunReportChange(taskMap map[string]Task, r *render, action DryRunAction, changeList []change) *DryRunReportChange {
+	taskType := getTaskName(r.changes)
+	name := idForTask(taskMap, r.e)
+	c := &DryRunReportChange{
+		Key:    taskType + "/" + name,
+		Type:   taskType,
+		Name:   name,
+		Action: action,
+	}
+	if hl, ok := r.e.(HasLifecycle); ok {
+		c.Lifecycle = hl.GetLifecycle()
+	}
+	for _, change := range changeList {
+		c.Fields = append(c.Fields, &DryRunReportField{
+			Name: change.FieldName,
+			Old:  change.OldValue,
+			New:  change.NewValue,
+		})
+	}
+	return c
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"sync"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/diff"
@@ -40,6 +41,12 @@ type DryRunTarget struct {
 	changes   []*render
 	deletions []Deletion
 
+	// unapplied are the keys of the tasks that are only checked, and that would be changed if they were applied
+	unapplied []string
+
+	// observed records the actual state found for each task, nil if the object does not exist
+	observed map[Task]Task
+
 	// The destination to which the final report will be printed on Finish()
 	out io.Writer
 
@@ -102,6 +109,17 @@ func (t *DryRunTarget) Render(a, e, changes Task) error {
 	return nil
 }
 
+// observe records the actual state a found for the task e.
+func (t *DryRunTarget) observe(a, e Task) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.observed == nil {
+		t.observed = make(map[Task]Task)
+	}
+	t.observed[e] = a
+}
+
 func (t *DryRunTarget) Delete(deletion Deletion) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
@@ -151,56 +169,8 @@ func (t *DryRunTarget) PrintReport(taskMap map[string]Task, out io.Writer) error
 				taskName := getTaskName(r.changes)
 				fmt.Fprintf(b, "  %s/%s\n", taskName, idForTask(taskMap, r.e))
 
-				changes := reflect.ValueOf(r.changes)
-				if changes.Kind() == reflect.Ptr && !changes.IsNil() {
-					changes = changes.Elem()
-				}
-
-				if changes.Kind() == reflect.Struct {
-					for i := 0; i < changes.NumField(); i++ {
-
-						field := changes.Field(i)
-
-						fieldName := changes.Type().Field(i).Name
-						if changes.Type().Field(i).PkgPath != "" {
-							// Not exported
-							continue
-						}
-
-						fieldValue := reflectutils.ValueAsString(field)
-
-						shouldPrint := true
-						if fieldName == "Name" {
-							// The field name is already printed above, no need to repeat it.
-							shouldPrint = false
-						}
-						if fieldName == "Lifecycle" {
-							// Lifecycle is a "system" field; no need to show it
-							shouldPrint = false
-						}
-						if fieldValue == "<nil>" || fieldValue == "<resource>" {
-							// Uninformative
-							shouldPrint = false
-						}
-						if fieldValue == "id:<nil>" {
-							// Uninformative, but we can often print the name instead
-							name := ""
-							if field.CanInterface() {
-								hasName, ok := field.Interface().(HasName)
-								if ok {
-									name = StringValue(hasName.GetName())
-								}
-							}
-							if name != "" {
-								fieldValue = "name:" + name
-							} else {
-								shouldPrint = false
-							}
-						}
-						if shouldPrint {
-							fmt.Fprintf(b, "  \t%-20s\t%s\n", fieldName, fieldValue)
-						}
-					}
+				for _, change := range buildCreateList(r.changes) {
+					fmt.Fprintf(b, "  \t%-20s\t%s\n", change.FieldName, change.Description)
 				}
 
 				fmt.Fprintf(b, "\n")
@@ -275,6 +245,69 @@ func (t *DryRunTarget) PrintReport(taskMap map[string]Task, out io.Writer) error
 type change struct {
 	FieldName   string
 	Description string
+
+	// OldValue and NewValue are the values of the field before and after the change
+	OldValue string
+	NewValue string
+}
+
+// buildCreateList returns the fields that are worth showing for an object that will be created.
+func buildCreateList(changes Task) []change {
+	var changeList []change
+
+	valC := reflect.ValueOf(changes)
+	if valC.Kind() == reflect.Ptr && !valC.IsNil() {
+		valC = valC.Elem()
+	}
+
+	if valC.Kind() == reflect.Struct {
+		for i := 0; i < valC.NumField(); i++ {
+
+			field := valC.Field(i)
+
+			fieldName := valC.Type().Field(i).Name
+			if valC.Type().Field(i).PkgPath != "" {
+				// Not exported
+				continue
+			}
+
+			fieldValue := reflectutils.ValueAsString(field)
+
+			shouldPrint := true
+			if fieldName == "Name" {
+				// The field name is already printed above, no need to repeat it.
+				shouldPrint = false
+			}
+			if fieldName == "Lifecycle" {
+				// Lifecycle is a "system" field; no need to show it
+				shouldPrint = false
+			}
+			if fieldValue == "<nil>" || fieldValue == "<resource>" {
+				// Uninformative
+				shouldPrint = false
+			}
+			if fieldValue == "id:<nil>" {
+				// Uninformative, but we can often print the name instead
+				name := ""
+				if field.CanInterface() {
+					hasName, ok := field.Interface().(HasName)
+					if ok {
+						name = StringValue(hasName.GetName())
+					}
+				}
+				if name != "" {
+					fieldValue = "name:" + name
+				} else {
+					shouldPrint = false
+				}
+			}
+			if shouldPrint {
+				changeList = append(changeList, change{FieldName: fieldName, Description: fieldValue, NewValue: fieldValue})
+			}
+		}
+	}
+
+	return changeList
 }
 
 func buildChangeList(a, e, changes Task) ([]change, error) {
@@ -319,6 +352,8 @@ func buildChangeList(a, e, changes Task) ([]change, error) {
 			}
 
 			description := ""
+			oldValue := ""
+			newValue := ""
 			ignored := false
 			if fieldValE.CanInterface() {
 
@@ -330,17 +365,21 @@ func buildChangeList(a, e, changes Task) ([]change, error) {
 					resE, okE := tryResourceAsString(fieldValE)
 					if okA && okE {
 						description = diff.FormatDiff(resA, resE)
+						oldValue = resA
+						newValue = resE
 					}
 				}
 
 				if !ignored && description == "" {
-					description = fmt.Sprintf(" %v -> %v", reflectutils.ValueAsString(fieldValA), reflectutils.ValueAsString(fieldValE))
+					oldValue = reflectutils.ValueAsString(fieldValA)
+					newValue = reflectutils.ValueAsString(fieldValE)
+					description = fmt.Sprintf(" %v -> %v", oldValue, newValue)
 				}
 			}
 			if ignored {
 				continue
 			}
-			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description})
+			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description, OldValue: oldValue, NewValue: newValue})
 		}
 	} else {
 		return nil, fmt.Errorf("unhandled change type: %v", valC.Type())
@@ -400,6 +439,26 @@ func (t *DryRunTarget) Deletions() []string {
 	return deletions
 }
 
+// recordUnapplied records that the task e, which is only checked, would be changed if it were applied.
+func (t *DryRunTarget) recordUnapplied(e Task) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.unapplied = append(t.unapplied, buildTaskKey(e))
+}
+
+// UnappliedChanges returns the keys of the tasks that are only checked, because of their lifecycle,
+// and that would be changed if they were applied.
+func (t *DryRunTarget) UnappliedChanges() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.unapplied) == 0 {
+		return nil
+	}
+	return sets.NewString(t.unapplied...).List()
+}
+
 // Changes returns tasks which is going to be created or updated
 func (t *DryRunTarget) Changes() (map[string]Task, map[string]Task) {
 	creates := make(map[string]Task)
@@ -61,11 +61,38 @@ type testTask struct {
 }
 
 var _ Task = &testTask{}
+var _ HasLifecycle = &testTask{}
 
 func (*testTask) Run(_ *Context) error {
 	panic("not implemented")
 }
 
+func (t *testTask) GetLifecycle() Lifecycle {
+	return t.Lifecycle
+}
+
+func (t *testTask) SetLifecycle(lifecycle Lifecycle) {
+	t.Lifecycle = lifecycle
+}
+
+type testDeletion struct {
+	item string
+}
+
+var _ Deletion = &testDeletion{}
+
+func (d *testDeletion) Delete(target Target) error {
+	panic("not implemented")
+}
+
+func (d *testDeletion) TaskName() string {
+	return "testTask"
+}
+
+func (d *testDeletion) Item() string {
+	return d.item
+}
+
 func Test_DryrunTarget_PrintReport(t *testing.T) {
 	builder := assets.NewAssetBuilder(&api.Cluster{
 		Spec: api.ClusterSpec{
@@ -95,3 +122,78 @@ func Test_DryrunTarget_PrintReport(t *testing.T) {
 	err = target.PrintReport(tasks, &out)
 	assert.NoError(t, err, "target.PrintReport()")
 }
+
+func Test_DryrunTarget_Report(t *testing.T) {
+	builder := assets.NewAssetBuilder(&api.Cluster{
+		Spec: api.ClusterSpec{
+			KubernetesVersion: "1.17.3",
+		},
+	}, false)
+	target := NewDryRunTarget(builder, &bytes.Buffer{})
+
+	created := &testTask{
+		Name:      String("created"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "value"},
+	}
+	modifiedA := &testTask{
+		Name:      String("modified"),
+		Lifecycle: LifecycleWarnIfInsufficientAccess,
+		Tags:      map[string]string{"key": "old"},
+	}
+	modifiedE := &testTask{
+		Name:      String("modified"),
+		Lifecycle: LifecycleWarnIfInsufficientAccess,
+		Tags:      map[string]string{"key": "new"},
+	}
+	tasks := map[string]Task{
+		"testTask/created":  created,
+		"testTask/modified": modifiedE,
+	}
+
+	var nilTask *testTask
+	changes := reflect.New(reflect.TypeOf(created).Elem()).Interface().(Task)
+	_ = BuildChanges(nilTask, created, changes)
+	assert.NoError(t, target.Render(nilTask, created, changes), "target.Render()")
+
+	changes = reflect.New(reflect.TypeOf(modifiedE).Elem()).Interface().(Task)
+	_ = BuildChanges(modifiedA, modifiedE, changes)
+	assert.NoError(t, target.Render(modifiedA, modifiedE, changes), "target.Render()")
+
+	assert.NoError(t, target.Delete(&testDeletion{item: "deleted"}), "target.Delete()")
+
+	report, err := target.Report(tasks)
+	assert.NoError(t, err, "target.Report()")
+
+	expected := &DryRunReport{
+		Changes: []*DryRunReportChange{
+			{
+				Key:       "testTask/created",
+				Type:      "testTask",
+				Name:      "created",
+				Lifecycle: LifecycleSync,
+				Action:    DryRunActionCreate,
+				Fields: []*DryRunReportField{
+					{Name: "Tags", New: "{key: value}"},
+				},
+			},
+			{
+				Key:       "testTask/modified",
+				Type:      "testTask",
+				Name:      "modified",
+				Lifecycle: LifecycleWarnIfInsufficientAccess,
+				Action:    DryRunActionModify,
+				Fields: []*DryRunReportField{
+					{Name: "Tags", Old: "{key: old}", New: "{key: new}"},
+				},
+			},
+			{
+				Key:    "testTask/deleted",
+				Type:   "testTask",
+				Name:   "deleted",
+				Action: DryRunActionDelete,
+			},
+		},
+	}
+	assert.Equal(t, expected, report)
+}
@@ -48,6 +48,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/upup/pkg/fi/vaultstore"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/distributions"
 	"k8s.io/kops/util/pkg/vfs"
@@ -243,12 +244,20 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		modelContext.KeyStore = configserver.NewKeyStore(nodeupConfig.CAs[fi.CertificateIDCA])
 	} else if c.cluster.Spec.KeyStore != "" {
 		klog.Infof("Building KeyStore at %q", c.cluster.Spec.KeyStore)
-		p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.KeyStore)
-		if err != nil {
-			return fmt.Errorf("error building key store path: %v", err)
-		}
+		if vaultstore.IsVaultLocation(c.cluster.Spec.KeyStore) {
+			vaultKeyStore, err := vaultstore.NewKeyStore(c.cluster, c.cluster.Spec.KeyStore)
+			if err != nil {
+				return fmt.Errorf("error building vault key store: %v", err)
+			}
+			modelContext.KeyStore = vaultKeyStore
+		} else {
+			p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.KeyStore)
+			if err != nil {
+				return fmt.Errorf("error building key store path: %v", err)
+			}
 
-		modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, p)
+			modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, p)
+		}
 		keyStore = modelContext.KeyStore
 	} else {
 		return fmt.Errorf("KeyStore not set")
@@ -310,6 +319,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &networking.LyftVPCBuilder{NodeupModelContext: modelContext})
 
+	nodeupPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error determining the path of nodeup: %v", err)
+	}
+	// CertificateRenewalBuilder must come after the builders requesting certificates from kops-controller
+	loader.Builders = append(loader.Builders, &model.CertificateRenewalBuilder{NodeupModelContext: modelContext, NodeupPath: nodeupPath})
 	loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
 	taskMap, err := loader.Build()
 	if err != nil {
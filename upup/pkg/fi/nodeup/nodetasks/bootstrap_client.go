@@ -30,6 +30,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"k8s.io/kops/pkg/apis/nodeup"
@@ -125,6 +126,16 @@ func (b *BootstrapClientTask) Run(c *fi.Context) error {
 			return fmt.Errorf("parsing %q certificate: %v", name, err)
 		}
 		certRequest.Cert.Resource = asBytesResource{certificate}
+
+		// kops-controller generated the key if the signer could not issue a certificate for ours
+		if key, ok := resp.Keys[name]; ok {
+			privateKey, err := pki.ParsePEMPrivateKey([]byte(key))
+			if err != nil {
+				return fmt.Errorf("parsing %q key: %v", name, err)
+			}
+			certRequest.Key.Resource = &asBytesResource{privateKey}
+			b.keys[name] = privateKey
+		}
 	}
 
 	return nil
@@ -142,7 +153,17 @@ type KopsBootstrapClient struct {
 	httpClient *http.Client
 }
 
+// QueryBootstrap requests the certificates for bootstrapping the node.
 func (b *KopsBootstrapClient) QueryBootstrap(ctx context.Context, req *nodeup.BootstrapRequest) (*nodeup.BootstrapResponse, error) {
+	return b.query(ctx, "/bootstrap", req)
+}
+
+// QueryRenew requests new certificates for a node that has registered.
+func (b *KopsBootstrapClient) QueryRenew(ctx context.Context, req *nodeup.BootstrapRequest) (*nodeup.BootstrapResponse, error) {
+	return b.query(ctx, "/renew", req)
+}
+
+func (b *KopsBootstrapClient) query(ctx context.Context, endpoint string, req *nodeup.BootstrapRequest) (*nodeup.BootstrapResponse, error) {
 	if b.httpClient == nil {
 		certPool := x509.NewCertPool()
 		certPool.AppendCertsFromPEM(b.CAs)
@@ -177,7 +198,7 @@ func (b *KopsBootstrapClient) QueryBootstrap(ctx context.Context, req *nodeup.Bo
 	}
 
 	bootstrapURL := b.BaseURL
-	bootstrapURL.Path = path.Join(bootstrapURL.Path, "/bootstrap")
+	bootstrapURL.Path = path.Join(bootstrapURL.Path, endpoint)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", bootstrapURL.String(), bytes.NewReader(reqBytes))
 	if err != nil {
 		return nil, err
@@ -206,7 +227,7 @@ func (b *KopsBootstrapClient) QueryBootstrap(ctx context.Context, req *nodeup.Bo
 				detail = scanner.Text()
 			}
 		}
-		return nil, fmt.Errorf("bootstrap returned status code %d: %s", resp.StatusCode, detail)
+		return nil, fmt.Errorf("%s returned status code %d: %s", strings.TrimPrefix(endpoint, "/"), resp.StatusCode, detail)
 	}
 
 	var bootstrapResp nodeup.BootstrapResponse
@@ -32,6 +32,13 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 )
 
+const (
+	// IssuedCertMinimumValidity is the shortest validity of the certificates issued by IssueCert.
+	IssuedCertMinimumValidity = 455 * 24 * time.Hour
+	// issuedCertValiditySkew is the range by which the validity of the issued certificates is lengthened.
+	issuedCertValiditySkew = 30 * 24 * time.Hour
+)
+
 // PKIXName is a simplified form of pkix.Name, for better golden test output
 type PKIXName struct {
 	fi.NotADependency
@@ -142,7 +149,7 @@ func (e *IssueCert) Run(c *fi.Context) error {
 	} else {
 		klog.Warningf("cannot skew certificate lifetime: failed to get interface addresses: %v", err)
 	}
-	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
+	validHours := uint32(IssuedCertMinimumValidity/time.Hour) + (hash.Sum32() % uint32(issuedCertValiditySkew/time.Hour))
 
 	req := &pki.IssueCertRequest{
 		Signer:         e.Signer,
@@ -0,0 +1,313 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// DefaultCertificateRenewBefore is how long before they expire certificates are renewed, if not configured.
+const DefaultCertificateRenewBefore = 60 * 24 * time.Hour
+
+// RenewCertificatesCommand renews the certificates kops-controller issued to the node, without running the rest of nodeup.
+type RenewCertificatesCommand struct {
+	// ConfigLocation is the path of the nodeup.CertificateRenewalConfig.
+	ConfigLocation string
+	// Force renews the certificates even if they are not due, for example after the CA was rotated.
+	Force bool
+}
+
+// Run renews the certificates if they are due, and restarts the services using them.
+func (c *RenewCertificatesCommand) Run(out io.Writer) error {
+	ctx := context.Background()
+
+	b, err := ioutil.ReadFile(c.ConfigLocation)
+	if err != nil {
+		return fmt.Errorf("error loading configuration %q: %v", c.ConfigLocation, err)
+	}
+	var config nodeup.CertificateRenewalConfig
+	if err := utils.YamlUnmarshal(b, &config); err != nil {
+		return fmt.Errorf("error parsing configuration %q: %v", c.ConfigLocation, err)
+	}
+
+	renewBefore := DefaultCertificateRenewBefore
+	if config.RenewBefore != "" {
+		renewBefore, err = time.ParseDuration(config.RenewBefore)
+		if err != nil {
+			return fmt.Errorf("error parsing renewBefore %q: %v", config.RenewBefore, err)
+		}
+	}
+
+	if c.Force {
+		klog.Infof("renewing certificates: forced")
+	} else {
+		reason, err := certificateRenewalReason(&config, renewBefore, time.Now())
+		if err != nil {
+			return err
+		}
+		if reason == "" {
+			fmt.Fprintf(out, "certificates are not due for renewal\n")
+			return nil
+		}
+		klog.Infof("renewing certificates: %s", reason)
+	}
+
+	var authenticator fi.Authenticator
+	switch api.CloudProviderID(config.CloudProvider) {
+	case api.CloudProviderAWS:
+		authenticator, err = awsup.NewAWSAuthenticator(config.Region)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported cloud provider %s", config.CloudProvider)
+	}
+
+	u, err := url.Parse(config.Server)
+	if err != nil {
+		return fmt.Errorf("unable to parse kops-controller url %q: %w", config.Server, err)
+	}
+	client := &nodetasks.KopsBootstrapClient{
+		Authenticator: authenticator,
+		CAs:           []byte(config.CACertificates),
+		BaseURL:       *u,
+	}
+
+	req := &nodeup.BootstrapRequest{
+		APIVersion: nodeup.BootstrapAPIVersion,
+		Certs:      map[string]string{},
+	}
+	keys := map[string]*pki.PrivateKey{}
+	for _, renewable := range config.Certificates {
+		key, err := pki.GeneratePrivateKey()
+		if err != nil {
+			return fmt.Errorf("generating private key: %v", err)
+		}
+		pkData, err := x509.MarshalPKIXPublicKey(key.Key.Public())
+		if err != nil {
+			return fmt.Errorf("marshalling public key: %v", err)
+		}
+		req.Certs[renewable.Name] = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkData}))
+		keys[renewable.Name] = key
+	}
+
+	resp, err := client.QueryRenew(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	// Parse all the certificates before writing any, so that the node is not left with a mix of old and new certificates
+	certificates := map[string]*pki.Certificate{}
+	for _, renewable := range config.Certificates {
+		cert, ok := resp.Certs[renewable.Name]
+		if !ok {
+			return fmt.Errorf("kops-controller did not return a %q certificate", renewable.Name)
+		}
+		certificate, err := pki.ParsePEMCertificate([]byte(cert))
+		if err != nil {
+			return fmt.Errorf("parsing %q certificate: %v", renewable.Name, err)
+		}
+		certificates[renewable.Name] = certificate
+
+		// kops-controller generated the key if the signer could not issue a certificate for ours
+		if key, ok := resp.Keys[renewable.Name]; ok {
+			keys[renewable.Name], err = pki.ParsePEMPrivateKey([]byte(key))
+			if err != nil {
+				return fmt.Errorf("parsing %q key: %v", renewable.Name, err)
+			}
+		}
+	}
+
+	for i := range config.Certificates {
+		renewable := &config.Certificates[i]
+		if err := writeRenewedCertificate(renewable, certificates[renewable.Name], keys[renewable.Name]); err != nil {
+			return fmt.Errorf("writing %q certificate: %v", renewable.Name, err)
+		}
+		klog.Infof("renewed %q certificate, valid until %s", renewable.Name, certificates[renewable.Name].Certificate.NotAfter)
+	}
+
+	for _, service := range config.Services {
+		klog.Infof("restarting service %q", service)
+		cmd := exec.Command("systemctl", "restart", service)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error restarting service %q: %v\nOutput: %s", service, err, output)
+		}
+	}
+
+	for _, renewable := range config.Certificates {
+		for _, container := range renewable.Containers {
+			if err := stopContainers(config.ContainerRuntime, container); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprintf(out, "renewed %d certificates\n", len(config.Certificates))
+	return nil
+}
+
+// stopContainers stops the running containers named name, so that kubelet restarts them with the renewed certificates.
+func stopContainers(containerRuntime string, name string) error {
+	var list []string
+	var stop []string
+	switch containerRuntime {
+	case "containerd":
+		list = []string{"crictl", "ps", "--quiet", "--name", "^" + name + "$"}
+		stop = []string{"crictl", "stop"}
+	case "docker":
+		list = []string{"docker", "ps", "--quiet", "--filter", "label=io.kubernetes.container.name=" + name}
+		stop = []string{"docker", "stop"}
+	default:
+		return fmt.Errorf("unable to restart container %q with unknown container runtime %q", name, containerRuntime)
+	}
+
+	output, err := exec.Command(list[0], list[1:]...).Output()
+	if err != nil {
+		return fmt.Errorf("error listing containers %q: %v", name, err)
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return nil
+	}
+
+	klog.Infof("restarting containers %q", name)
+	cmd := exec.Command(stop[0], append(stop[1:], ids...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error stopping containers %q: %v\nOutput: %s", name, err, output)
+	}
+	return nil
+}
+
+// certificateRenewalReason returns why the certificates need to be renewed, or an empty string if they do not.
+// The trusted CAs are those of the configuration, which nodeup only updates when it runs, so a rotation of
+// the CA is not noticed until then.
+func certificateRenewalReason(config *nodeup.CertificateRenewalConfig, renewBefore time.Duration, now time.Time) (string, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(config.CACertificates)) {
+		return "", fmt.Errorf("no CA certificates configured")
+	}
+
+	for i := range config.Certificates {
+		renewable := &config.Certificates[i]
+		certPEM, err := readRenewableCertificate(renewable)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Sprintf("certificate %q not found", renewable.Name), nil
+			}
+			return "", fmt.Errorf("reading %q certificate: %v", renewable.Name, err)
+		}
+		cert, err := pki.ParsePEMCertificate(certPEM)
+		if err != nil {
+			return "", fmt.Errorf("parsing %q certificate: %v", renewable.Name, err)
+		}
+
+		if cert.Certificate.NotAfter.Before(now.Add(renewBefore)) {
+			return fmt.Sprintf("certificate %q expires at %s", renewable.Name, cert.Certificate.NotAfter), nil
+		}
+		if _, err := cert.Certificate.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}); err != nil {
+			return fmt.Sprintf("certificate %q is not signed by a trusted CA: %v", renewable.Name, err), nil
+		}
+	}
+
+	return "", nil
+}
+
+// readRenewableCertificate returns the PEM-encoded certificate currently on the node.
+func readRenewableCertificate(renewable *nodeup.RenewableCertificate) ([]byte, error) {
+	if renewable.Kubeconfig == "" {
+		return ioutil.ReadFile(renewable.CertPath)
+	}
+
+	config, err := readKubeconfig(renewable.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Users) == 0 {
+		return nil, fmt.Errorf("kubeconfig %q has no users", renewable.Kubeconfig)
+	}
+	return config.Users[0].User.ClientCertificateData, nil
+}
+
+// writeRenewedCertificate replaces the certificate and key on the node.
+func writeRenewedCertificate(renewable *nodeup.RenewableCertificate, certificate *pki.Certificate, key *pki.PrivateKey) error {
+	certBytes, err := certificate.AsBytes()
+	if err != nil {
+		return err
+	}
+	keyBytes, err := key.AsBytes()
+	if err != nil {
+		return err
+	}
+
+	if renewable.Kubeconfig == "" {
+		if err := fi.WriteFile(renewable.KeyPath, fi.NewBytesResource(keyBytes), 0400, 0755, "", ""); err != nil {
+			return err
+		}
+		return fi.WriteFile(renewable.CertPath, fi.NewBytesResource(certBytes), 0644, 0755, "", "")
+	}
+
+	config, err := readKubeconfig(renewable.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	for _, user := range config.Users {
+		user.User.ClientCertificateData = certBytes
+		user.User.ClientKeyData = keyBytes
+	}
+	b, err := api.ToRawYaml(config)
+	if err != nil {
+		return fmt.Errorf("error marshaling kubeconfig to yaml: %v", err)
+	}
+	return fi.WriteFile(renewable.Kubeconfig, fi.NewBytesResource(b), 0400, 0755, "", "")
+}
+
+func readKubeconfig(p string) (*kubeconfig.KubectlConfig, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	config := &kubeconfig.KubectlConfig{}
+	if err := utils.YamlUnmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("error parsing kubeconfig %q: %v", p, err)
+	}
+	return config, nil
+}
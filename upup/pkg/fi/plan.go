@@ -0,0 +1,365 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// PlanVersion is the version of the plan format written by this version of kOps.
+const PlanVersion = 1
+
+// PlanActualAbsent is the actual fingerprint of a task whose object does not exist.
+const PlanActualAbsent = "absent"
+
+// Plan records the changes that a dry run would make, along with fingerprints of the
+// expected and the actual state of every task, so that a later apply can detect
+// whether the state store or the cloud have drifted since the plan was made.
+type Plan struct {
+	// Version is the version of the plan format.
+	Version int `json:"version"`
+	// ClusterName is the name of the cluster the plan was made for.
+	ClusterName string `json:"clusterName"`
+	// CreatedAt is when the plan was made.
+	CreatedAt time.Time `json:"createdAt"`
+	// Tasks holds the fingerprints of each task, by task key.
+	Tasks map[string]*PlanTask `json:"tasks"`
+	// Changes are the objects that will be created or modified.
+	Changes []*PlanChange `json:"changes,omitempty"`
+	// Deletions are the objects that will be deleted.
+	Deletions []*PlanDeletion `json:"deletions,omitempty"`
+}
+
+// PlanTask holds the fingerprints of a single task.
+type PlanTask struct {
+	// Expected is the fingerprint of the state the task wants.
+	Expected string `json:"expected"`
+	// Actual is the fingerprint of the state that was found, PlanActualAbsent if the object
+	// does not exist, or empty if the task does not report its actual state.
+	Actual string `json:"actual,omitempty"`
+}
+
+// PlanChange is an object that will be created or modified.
+type PlanChange struct {
+	// Task is the key of the task.
+	Task string `json:"task"`
+	// Action is either "create" or "modify".
+	Action string `json:"action"`
+	// Fields are the fields that will be set.
+	Fields []PlanField `json:"fields,omitempty"`
+}
+
+// PlanField is a field that will be set on an object.
+type PlanField struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PlanDeletion is an object that will be deleted.
+type PlanDeletion struct {
+	Task string `json:"task"`
+	Item string `json:"item"`
+}
+
+// BuildPlan builds a plan from the tasks that were run against the DryRunTarget.
+func (t *DryRunTarget) BuildPlan(clusterName string, taskMap map[string]Task) (*Plan, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	plan := &Plan{
+		Version:     PlanVersion,
+		ClusterName: clusterName,
+		CreatedAt:   time.Now().UTC(),
+		Tasks:       make(map[string]*PlanTask),
+	}
+
+	keys := make(map[Task]string)
+	for key, task := range taskMap {
+		keys[task] = key
+
+		planTask := &PlanTask{
+			Expected: fingerprintTask(task),
+		}
+		if a, found := t.observed[task]; found {
+			if a == nil {
+				planTask.Actual = PlanActualAbsent
+			} else {
+				planTask.Actual = fingerprintTask(a)
+			}
+		}
+		plan.Tasks[key] = planTask
+	}
+
+	for _, r := range t.changes {
+		planChange := &PlanChange{
+			Task: keys[r.e],
+		}
+		if planChange.Task == "" {
+			planChange.Task = buildTaskKey(r.e)
+		}
+		if _, found := t.observed[r.e]; !found {
+			// Without the actual state, a later apply could not detect that the object has drifted
+			return nil, fmt.Errorf("%s would be changed, but does not report the state it found, so the changes cannot be planned", planChange.Task)
+		}
+
+		var changeList []change
+		if r.aIsNil {
+			planChange.Action = string(DryRunActionCreate)
+			changeList = buildCreateList(r.changes)
+		} else {
+			planChange.Action = string(DryRunActionModify)
+			var err error
+			changeList, err = buildChangeList(r.a, r.e, r.changes)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range changeList {
+			planChange.Fields = append(planChange.Fields, PlanField{Name: c.FieldName, Description: c.Description})
+		}
+		plan.Changes = append(plan.Changes, planChange)
+	}
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].Task < plan.Changes[j].Task
+	})
+
+	for _, d := range t.deletions {
+		plan.Deletions = append(plan.Deletions, &PlanDeletion{Task: d.TaskName(), Item: d.Item()})
+	}
+	sort.Slice(plan.Deletions, func(i, j int) bool {
+		if plan.Deletions[i].Task != plan.Deletions[j].Task {
+			return plan.Deletions[i].Task < plan.Deletions[j].Task
+		}
+		return plan.Deletions[i].Item < plan.Deletions[j].Item
+	})
+
+	return plan, nil
+}
+
+// VerifyPlan checks that the tasks that were run against the DryRunTarget would make the
+// changes of the plan, returning an error describing any drift since the plan was made.
+func (t *DryRunTarget) VerifyPlan(plan *Plan, clusterName string, taskMap map[string]Task) error {
+	current, err := t.BuildPlan(clusterName, taskMap)
+	if err != nil {
+		return err
+	}
+	if err := plan.Verify(current); err != nil {
+		return fmt.Errorf("%v\nrun `kops update cluster --out-plan` to make a new plan", err)
+	}
+	return nil
+}
+
+// WritePlan serializes the plan to w.
+func WritePlan(w io.Writer, plan *Plan) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		return fmt.Errorf("error writing plan: %v", err)
+	}
+	return nil
+}
+
+// ReadPlan parses a plan written by WritePlan.
+func ReadPlan(r io.Reader) (*Plan, error) {
+	plan := &Plan{}
+	if err := json.NewDecoder(r).Decode(plan); err != nil {
+		return nil, fmt.Errorf("error parsing plan: %v", err)
+	}
+	if plan.Version != PlanVersion {
+		return nil, fmt.Errorf("unsupported plan version %d, expected %d", plan.Version, PlanVersion)
+	}
+	return plan, nil
+}
+
+// Verify compares the plan with a plan made now, returning an error describing
+// any drift of the state store or the cloud since the plan was made.
+func (p *Plan) Verify(current *Plan) error {
+	if p.ClusterName != current.ClusterName {
+		return fmt.Errorf("plan was made for cluster %q, not %q", p.ClusterName, current.ClusterName)
+	}
+
+	var drift []string
+
+	keys := make(map[string]bool)
+	for key := range p.Tasks {
+		keys[key] = true
+	}
+	for key := range current.Tasks {
+		keys[key] = true
+	}
+	var sortedKeys []string
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		planned := p.Tasks[key]
+		now := current.Tasks[key]
+		switch {
+		case planned == nil:
+			drift = append(drift, fmt.Sprintf("%s was added to the cluster configuration", key))
+		case now == nil:
+			drift = append(drift, fmt.Sprintf("%s was removed from the cluster configuration", key))
+		case planned.Expected != now.Expected:
+			drift = append(drift, fmt.Sprintf("%s: cluster configuration changed", key))
+		case planned.Actual != now.Actual:
+			drift = append(drift, fmt.Sprintf("%s: cloud resources changed", key))
+		}
+	}
+
+	plannedDeletions := make(map[PlanDeletion]bool)
+	for _, d := range p.Deletions {
+		plannedDeletions[*d] = true
+	}
+	for _, d := range current.Deletions {
+		if !plannedDeletions[*d] {
+			drift = append(drift, fmt.Sprintf("%s %s would now also be deleted", d.Task, d.Item))
+		}
+		delete(plannedDeletions, *d)
+	}
+	for _, d := range p.Deletions {
+		if plannedDeletions[*d] {
+			drift = append(drift, fmt.Sprintf("%s %s would no longer be deleted", d.Task, d.Item))
+		}
+	}
+
+	if len(drift) != 0 {
+		return fmt.Errorf("cluster has drifted since the plan was made at %s:\n  %s", p.CreatedAt.Format(time.RFC3339), strings.Join(drift, "\n  "))
+	}
+	return nil
+}
+
+// fingerprintTask returns a stable hash of the exported fields of a task.
+// Other tasks referenced by the task contribute only their id or name.
+func fingerprintTask(task Task) string {
+	var b strings.Builder
+	v := reflect.ValueOf(task)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	fmt.Fprintf(&b, "%s{", v.Type().Name())
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Name == "Lifecycle" {
+				continue
+			}
+			fmt.Fprintf(&b, "%s:", field.Name)
+			writeFingerprintValue(&b, v.Field(i))
+			b.WriteString(",")
+		}
+	}
+	b.WriteString("}")
+
+	hash := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+func writeFingerprintValue(b *strings.Builder, v reflect.Value) {
+	if !v.IsValid() {
+		b.WriteString("<nil>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			b.WriteString("<nil>")
+			return
+		}
+	}
+
+	if v.CanInterface() {
+		switch o := v.Interface().(type) {
+		case Resource:
+			s, err := ResourceAsString(o)
+			if err != nil {
+				klog.V(4).Infof("unable to fingerprint resource: %v", err)
+				b.WriteString("<unresolved>")
+				return
+			}
+			hash := sha256.Sum256([]byte(s))
+			b.WriteString(hex.EncodeToString(hash[:]))
+			return
+		case time.Time:
+			b.WriteString(o.UTC().Format(time.RFC3339Nano))
+			return
+		case Task:
+			if compareWithID, ok := o.(CompareWithID); ok {
+				if id := compareWithID.CompareWithID(); id != nil {
+					fmt.Fprintf(b, "id:%s", *id)
+					return
+				}
+			}
+			if hasName, ok := o.(HasName); ok {
+				fmt.Fprintf(b, "name:%s", StringValue(hasName.GetName()))
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		writeFingerprintValue(b, v.Elem())
+
+	case reflect.Slice, reflect.Array:
+		b.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			writeFingerprintValue(b, v.Index(i))
+			b.WriteString(",")
+		}
+		b.WriteString("]")
+
+	case reflect.Map:
+		var entries []string
+		for _, key := range v.MapKeys() {
+			var entry strings.Builder
+			writeFingerprintValue(&entry, key)
+			entry.WriteString("=")
+			writeFingerprintValue(&entry, v.MapIndex(key))
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		fmt.Fprintf(b, "{%s}", strings.Join(entries, ","))
+
+	case reflect.Struct:
+		b.WriteString("{")
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			fmt.Fprintf(b, "%s:", v.Type().Field(i).Name)
+			writeFingerprintValue(b, v.Field(i))
+			b.WriteString(",")
+		}
+		b.WriteString("}")
+
+	default:
+		fmt.Fprintf(b, "%v", v.Interface())
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+)
+
+func Test_fingerprintTask(t *testing.T) {
+	base := &testTask{
+		Name:      String("TestName"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"a": "1", "b": "2", "c": "3"},
+	}
+	same := &testTask{
+		Name:      String("TestName"),
+		Lifecycle: LifecycleExistsAndWarnIfChanges,
+		Tags:      map[string]string{"c": "3", "b": "2", "a": "1"},
+	}
+	changed := &testTask{
+		Name:      String("TestName"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"a": "1", "b": "2", "c": "4"},
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, fingerprintTask(base), fingerprintTask(same), "map order and lifecycle should not matter")
+	}
+	assert.NotEqual(t, fingerprintTask(base), fingerprintTask(changed))
+}
+
+func newPlanTestTarget() *DryRunTarget {
+	builder := assets.NewAssetBuilder(&api.Cluster{
+		Spec: api.ClusterSpec{
+			KubernetesVersion: "1.21.0",
+		},
+	}, false)
+	return NewDryRunTarget(builder, ioutil.Discard)
+}
+
+// runDryRun simulates a dry run in which the task is found with the given actual tags, or not found if nil.
+func runDryRun(t *testing.T, expected map[string]string, actual map[string]string) *Plan {
+	target := newPlanTestTarget()
+
+	e := &testTask{
+		Name:      String("TestName"),
+		Lifecycle: LifecycleSync,
+		Tags:      expected,
+	}
+	tasks := map[string]Task{"testTask/TestName": e}
+
+	var a *testTask
+	if actual != nil {
+		a = &testTask{
+			Name:      String("TestName"),
+			Lifecycle: LifecycleSync,
+			Tags:      actual,
+		}
+		target.observe(a, e)
+	} else {
+		target.observe(nil, e)
+	}
+
+	changes := reflect.New(reflect.TypeOf(e).Elem()).Interface().(Task)
+	if BuildChanges(a, e, changes) {
+		require.NoError(t, target.Render(a, e, changes))
+	}
+
+	plan, err := target.BuildPlan("cluster.example.com", tasks)
+	require.NoError(t, err)
+	return plan
+}
+
+func Test_BuildPlan(t *testing.T) {
+	plan := runDryRun(t, map[string]string{"key": "new"}, map[string]string{"key": "old"})
+
+	assert.Equal(t, "cluster.example.com", plan.ClusterName)
+	require.Contains(t, plan.Tasks, "testTask/TestName")
+	task := plan.Tasks["testTask/TestName"]
+	assert.NotEmpty(t, task.Expected)
+	assert.NotEmpty(t, task.Actual)
+	assert.NotEqual(t, PlanActualAbsent, task.Actual)
+
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, "testTask/TestName", plan.Changes[0].Task)
+	assert.Equal(t, "modify", plan.Changes[0].Action)
+	require.Len(t, plan.Changes[0].Fields, 1)
+	assert.Equal(t, "Tags", plan.Changes[0].Fields[0].Name)
+
+	plan = runDryRun(t, map[string]string{"key": "new"}, nil)
+	assert.Equal(t, PlanActualAbsent, plan.Tasks["testTask/TestName"].Actual)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, "create", plan.Changes[0].Action)
+}
+
+// notCheckedTask is a task that never looks for an existing object.
+type notCheckedTask struct {
+	Name      *string
+	Lifecycle Lifecycle
+	Tags      map[string]string
+}
+
+var _ Task = &notCheckedTask{}
+var _ HasLifecycle = &notCheckedTask{}
+var _ HasCheckExisting = &notCheckedTask{}
+
+func (e *notCheckedTask) Run(c *Context) error {
+	return DefaultDeltaRunMethod(e, c)
+}
+
+func (e *notCheckedTask) GetLifecycle() Lifecycle {
+	return e.Lifecycle
+}
+
+func (e *notCheckedTask) SetLifecycle(lifecycle Lifecycle) {
+	e.Lifecycle = lifecycle
+}
+
+func (e *notCheckedTask) Find(c *Context) (*notCheckedTask, error) {
+	panic("not implemented")
+}
+
+func (e *notCheckedTask) CheckExisting(c *Context) bool {
+	return false
+}
+
+func (e *notCheckedTask) CheckChanges(a, ex, changes *notCheckedTask) error {
+	return nil
+}
+
+func Test_BuildPlanTaskNotChecked(t *testing.T) {
+	target := newPlanTestTarget()
+	e := &notCheckedTask{
+		Name:      String("TestName"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "new"},
+	}
+	tasks := map[string]Task{"notCheckedTask/TestName": e}
+
+	c, err := NewContext(target, &api.Cluster{}, nil, nil, nil, nil, true, tasks)
+	require.NoError(t, err)
+	defer c.Close()
+	require.NoError(t, e.Run(c))
+
+	plan, err := target.BuildPlan("cluster.example.com", tasks)
+	require.NoError(t, err)
+	assert.Equal(t, PlanActualAbsent, plan.Tasks["notCheckedTask/TestName"].Actual)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, "create", plan.Changes[0].Action)
+}
+
+func Test_BuildPlanRejectsUnreportedChanges(t *testing.T) {
+	target := newPlanTestTarget()
+	e := &testTask{
+		Name:      String("TestName"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "new"},
+	}
+	tasks := map[string]Task{"testTask/TestName": e}
+
+	// A task with its own Run method that renders without reporting what it found
+	require.NoError(t, target.Render(&testTask{}, e, &testTask{Tags: e.Tags}))
+
+	_, err := target.BuildPlan("cluster.example.com", tasks)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "testTask/TestName would be changed")
+}
+
+func Test_PlanRoundTrip(t *testing.T) {
+	plan := runDryRun(t, map[string]string{"key": "new"}, map[string]string{"key": "old"})
+	plan.Deletions = []*PlanDeletion{{Task: "LaunchTemplate", Item: "nodes-1"}}
+
+	var b bytes.Buffer
+	require.NoError(t, WritePlan(&b, plan))
+	read, err := ReadPlan(&b)
+	require.NoError(t, err)
+
+	assert.Equal(t, plan.Tasks, read.Tasks)
+	assert.Equal(t, plan.Changes, read.Changes)
+	assert.Equal(t, plan.Deletions, read.Deletions)
+	assert.True(t, plan.CreatedAt.Equal(read.CreatedAt))
+
+	_, err = ReadPlan(bytes.NewBufferString(`{"version": 99}`))
+	assert.Error(t, err)
+}
+
+func Test_PlanVerify(t *testing.T) {
+	planned := runDryRun(t, map[string]string{"key": "new"}, map[string]string{"key": "old"})
+
+	grid := []struct {
+		name     string
+		expected map[string]string
+		actual   map[string]string
+		mutate   func(p *Plan)
+		drift    string
+	}{
+		{
+			name:     "unchanged",
+			expected: map[string]string{"key": "new"},
+			actual:   map[string]string{"key": "old"},
+		},
+		{
+			name:     "state store changed",
+			expected: map[string]string{"key": "newer"},
+			actual:   map[string]string{"key": "old"},
+			drift:    "testTask/TestName: cluster configuration changed",
+		},
+		{
+			name:     "cloud changed",
+			expected: map[string]string{"key": "new"},
+			actual:   map[string]string{"key": "other"},
+			drift:    "testTask/TestName: cloud resources changed",
+		},
+		{
+			name:     "cloud object deleted",
+			expected: map[string]string{"key": "new"},
+			drift:    "testTask/TestName: cloud resources changed",
+		},
+		{
+			name:     "task added",
+			expected: map[string]string{"key": "new"},
+			actual:   map[string]string{"key": "old"},
+			mutate: func(p *Plan) {
+				p.Tasks["testTask/Other"] = &PlanTask{Expected: "x"}
+			},
+			drift: "testTask/Other was added to the cluster configuration",
+		},
+		{
+			name:     "deletion added",
+			expected: map[string]string{"key": "new"},
+			actual:   map[string]string{"key": "old"},
+			mutate: func(p *Plan) {
+				p.Deletions = append(p.Deletions, &PlanDeletion{Task: "LaunchTemplate", Item: "nodes-1"})
+			},
+			drift: "LaunchTemplate nodes-1 would now also be deleted",
+		},
+		{
+			name:     "other cluster",
+			expected: map[string]string{"key": "new"},
+			actual:   map[string]string{"key": "old"},
+			mutate: func(p *Plan) {
+				p.ClusterName = "other.example.com"
+			},
+			drift: `plan was made for cluster "cluster.example.com", not "other.example.com"`,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			current := runDryRun(t, g.expected, g.actual)
+			if g.mutate != nil {
+				g.mutate(current)
+			}
+			err := planned.Verify(current)
+			if g.drift == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), g.drift)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/stateencryption"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -79,7 +80,7 @@ func (c *VFSSecretStore) MirrorTo(basedir vfs.Path) error {
 
 		klog.Infof("mirroring secret %s -> %s", name, p)
 
-		err = createSecret(secret, p, acl, true)
+		err = createSecret(secret, p, acl, true, c.encryptionKey())
 		if err != nil {
 			return fmt.Errorf("error writing secret %q for mirror: %v", name, err)
 		}
@@ -156,7 +157,7 @@ func (c *VFSSecretStore) GetOrCreateSecret(id string, secret *fi.Secret) (*fi.Se
 			return nil, false, err
 		}
 
-		err = createSecret(secret, p, acl, false)
+		err = createSecret(secret, p, acl, false, c.encryptionKey())
 		if err != nil {
 			if os.IsExist(err) && i == 0 {
 				klog.Infof("Got already-exists error when writing secret; likely due to concurrent creation.  Will retry")
@@ -188,7 +189,7 @@ func (c *VFSSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret
 		return nil, err
 	}
 
-	err = createSecret(secret, p, acl, true)
+	err = createSecret(secret, p, acl, true, c.encryptionKey())
 	if err != nil {
 		return nil, fmt.Errorf("unable to write secret: %v", err)
 	}
@@ -213,12 +214,30 @@ func (c *VFSSecretStore) loadSecret(p vfs.Path) (*fi.Secret, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing secret from %q: %v", p, err)
 	}
+	s.Data, err = stateencryption.Decrypt(s.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting secret from %q: %v", p, err)
+	}
 	return s, nil
 }
 
-// createSecret will create the Secret, overwriting an existing secret if replace is true
-func createSecret(s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool) error {
-	data, err := json.Marshal(s)
+// encryptionKey returns the key secrets are encrypted with, or "" if they are stored in plaintext.
+func (c *VFSSecretStore) encryptionKey() string {
+	if c.cluster == nil {
+		return ""
+	}
+	return c.cluster.Spec.StateEncryptionKey
+}
+
+// createSecret will create the Secret, overwriting an existing secret if replace is true.
+// The secret data is encrypted with encryptionKey, unless it is empty.
+func createSecret(s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool, encryptionKey string) error {
+	encrypted, err := stateencryption.Encrypt(encryptionKey, s.Data)
+	if err != nil {
+		return fmt.Errorf("error encrypting secret: %v", err)
+	}
+
+	data, err := json.Marshal(&fi.Secret{Data: encrypted})
 	if err != nil {
 		return fmt.Errorf("error serializing secret: %v", err)
 	}
@@ -24,3 +24,14 @@ type Target interface {
 	// Some providers (e.g. Terraform) actively keep state, and will delete resources automatically
 	ProcessDeletions() bool
 }
+
+// ObservingTarget is a Target that renders every task whether or not it exists,
+// but is also told of the existing objects, e.g. to adopt them.
+type ObservingTarget interface {
+	Target
+
+	// ObservesExisting returns true if the existing objects should be found and passed to ObserveExisting
+	ObservesExisting() bool
+	// ObserveExisting records the existing object a found for the task e
+	ObserveExisting(a, e Task)
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultstore
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/pki"
+)
+
+// fakeVault serves the parts of the Vault API the keystore uses: listing secrets engines,
+// a KV version 2 secrets engine mounted at "secret" and PKI secrets engines.
+type fakeVault struct {
+	mutex sync.Mutex
+	// kv holds the data of the KV secrets engine, by path
+	kv map[string]map[string]interface{}
+	// pkiMounts holds the PKI secrets engines, by mount point
+	pkiMounts map[string]*fakePKIMount
+}
+
+type fakePKIMount struct {
+	caCertificate *pki.Certificate
+	caKey         *pki.PrivateKey
+}
+
+func newFakeVault(t *testing.T, pkiMounts ...string) *httptest.Server {
+	f := &fakeVault{
+		kv:        make(map[string]map[string]interface{}),
+		pkiMounts: make(map[string]*fakePKIMount),
+	}
+	for _, mount := range pkiMounts {
+		f.pkiMounts[mount] = &fakePKIMount{}
+	}
+	server := httptest.NewTLSServer(f)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	p := strings.TrimPrefix(r.URL.Path, "/v1/")
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var data map[string]interface{}
+	var err error
+	switch {
+	case p == "sys/mounts":
+		data = f.listMounts()
+	case strings.HasPrefix(p, "secret/data/"):
+		data, err = f.kvData(r.Method, strings.TrimPrefix(p, "secret/data/"), body)
+	case strings.HasPrefix(p, "secret/metadata/") && r.URL.Query().Get("list") == "true":
+		data = f.kvList(strings.TrimPrefix(p, "secret/metadata/"))
+	default:
+		data, err = f.pki(r.Method, p, body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{err.Error()}})
+		return
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func (f *fakeVault) listMounts() map[string]interface{} {
+	mounts := map[string]interface{}{
+		"secret/": map[string]interface{}{"type": "kv"},
+	}
+	for mount := range f.pkiMounts {
+		mounts[mount+"/"] = map[string]interface{}{"type": "pki"}
+	}
+	return mounts
+}
+
+func (f *fakeVault) kvData(method string, p string, body map[string]interface{}) (map[string]interface{}, error) {
+	switch method {
+	case http.MethodGet:
+		if f.kv[p] == nil {
+			return nil, nil
+		}
+		return map[string]interface{}{"data": f.kv[p]}, nil
+	case http.MethodPut, http.MethodPost:
+		data, ok := body["data"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("no data to write to %q", p)
+		}
+		f.kv[p] = data
+		return map[string]interface{}{"version": 1}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s of %q", method, p)
+	}
+}
+
+// kvList lists the keys directly under p, with a trailing slash for directories.
+func (f *fakeVault) kvList(p string) map[string]interface{} {
+	prefix := strings.TrimSuffix(p, "/") + "/"
+	keys := make(map[string]bool)
+	for k := range f.kv {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		tokens := strings.SplitN(strings.TrimPrefix(k, prefix), "/", 2)
+		if len(tokens) == 2 {
+			keys[tokens[0]+"/"] = true
+		} else {
+			keys[tokens[0]] = true
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	var list []interface{}
+	for k := range keys {
+		list = append(list, k)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].(string) < list[j].(string) })
+	return map[string]interface{}{"keys": list}
+}
+
+func (f *fakeVault) pki(method string, p string, body map[string]interface{}) (map[string]interface{}, error) {
+	for mountPath, mount := range f.pkiMounts {
+		if !strings.HasPrefix(p, mountPath+"/") {
+			continue
+		}
+		switch endpoint := strings.TrimPrefix(p, mountPath+"/"); {
+		case endpoint == "cert/ca" && method == http.MethodGet:
+			if mount.caCertificate == nil {
+				return map[string]interface{}{"certificate": ""}, nil
+			}
+			certificate, err := mount.caCertificate.AsString()
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"certificate": certificate}, nil
+		case endpoint == "root/generate/internal" && method == http.MethodPut:
+			return mount.generateRoot(body)
+		case endpoint == "sign-verbatim" && method == http.MethodPut:
+			return mount.signVerbatim(body)
+		default:
+			return nil, fmt.Errorf("unexpected %s of %q", method, p)
+		}
+	}
+	return nil, nil
+}
+
+func (m *fakePKIMount) generateRoot(body map[string]interface{}) (map[string]interface{}, error) {
+	if m.caCertificate != nil {
+		return nil, fmt.Errorf("the PKI secrets engine already has a CA")
+	}
+	subject := pkix.Name{CommonName: body["common_name"].(string)}
+	if organization, ok := body["organization"].(string); ok {
+		subject.Organization = strings.Split(organization, ",")
+	}
+	var err error
+	m.caCertificate, m.caKey, _, err = pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: subject,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := m.caCertificate.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"certificate": certificate}, nil
+}
+
+// signVerbatim signs a certificate request with its subject and alternate names, like Vault does.
+func (m *fakePKIMount) signVerbatim(body map[string]interface{}) (map[string]interface{}, error) {
+	if m.caCertificate == nil {
+		return nil, fmt.Errorf("the PKI secrets engine has no CA")
+	}
+	block, _ := pem.Decode([]byte(body["csr"].(string)))
+	if block == nil {
+		return nil, fmt.Errorf("csr is not PEM encoded")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		IPAddresses:  csr.IPAddresses,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+	}
+	if ttl, ok := body["ttl"].(string); ok {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, err
+		}
+		template.NotAfter = time.Now().Add(d)
+	}
+	for _, name := range body["key_usage"].([]interface{}) {
+		for _, u := range keyUsages {
+			if u.name == name {
+				template.KeyUsage |= u.usage
+			}
+		}
+	}
+	for _, name := range body["ext_key_usage"].([]interface{}) {
+		for usage, n := range extKeyUsageNamesByUsage {
+			if n == name {
+				template.ExtKeyUsage = append(template.ExtKeyUsage, usage)
+			}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, m.caCertificate.Certificate, csr.PublicKey, m.caKey.Key)
+	if err != nil {
+		return nil, err
+	}
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	issuingCA, err := m.caCertificate.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"certificate": certificate, "issuing_ca": issuingCA}, nil
+}
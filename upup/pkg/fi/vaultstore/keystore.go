@@ -0,0 +1,364 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vaultstore implements a keystore backed by Vault.
+//
+// Keysets are kept in a KV version 2 secrets engine, like in any other VFS keystore, except for
+// the keysets that have a PKI secrets engine mounted for them. The private keys of those CAs never
+// leave Vault: certificates are issued by sending certificate requests to the PKI secrets engine.
+package vaultstore
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// PKIQueryParameter is the query parameter of a vault:// keystore location that names the prefix of the
+// mount points of the PKI secrets engines, e.g. vault://vault.example.com:8200/kv/clusters/example/keys?pki=pki/example
+// uses the PKI secrets engine mounted at pki/example/kubernetes-ca for the kubernetes-ca keyset.
+const PKIQueryParameter = "pki"
+
+// VaultCAStore is a keystore backed by Vault.
+type VaultCAStore struct {
+	*fi.VFSCAStore
+
+	client    *vault.Client
+	pkiPrefix string
+
+	mutex     sync.Mutex
+	pkiMounts map[string]bool
+}
+
+var _ fi.CAStore = &VaultCAStore{}
+var _ fi.SSHCredentialStore = &VaultCAStore{}
+var _ pki.RemoteSigner = &VaultCAStore{}
+
+// IsVaultLocation returns true if location is a vault:// keystore or secret store location.
+func IsVaultLocation(location string) bool {
+	return strings.HasPrefix(location, "vault://")
+}
+
+// NewKeyStore builds the keystore for a vault:// location.
+func NewKeyStore(cluster *kops.Cluster, location string) (*VaultCAStore, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault keystore location %q: %v", location, err)
+	}
+
+	p, err := vfs.Context.BuildVfsPath(location)
+	if err != nil {
+		return nil, err
+	}
+	vaultPath, ok := p.(*vfs.VaultPath)
+	if !ok {
+		return nil, fmt.Errorf("keystore location %q is not a vault location", location)
+	}
+
+	return NewVaultCAStore(cluster, vaultPath, strings.Trim(u.Query().Get(PKIQueryParameter), "/")), nil
+}
+
+// NewVaultCAStore builds a keystore that keeps keysets at basedir, and uses the PKI secrets engines
+// mounted under pkiPrefix for the keysets that have one. If pkiPrefix is empty, all keysets are kept at basedir.
+func NewVaultCAStore(cluster *kops.Cluster, basedir *vfs.VaultPath, pkiPrefix string) *VaultCAStore {
+	return &VaultCAStore{
+		VFSCAStore: fi.NewVFSCAStore(cluster, basedir),
+		client:     basedir.Client(),
+		pkiPrefix:  pkiPrefix,
+	}
+}
+
+// pkiMount returns the mount point of the PKI secrets engine for the named keyset.
+func (c *VaultCAStore) pkiMount(name string) string {
+	return path.Join(c.pkiPrefix, name)
+}
+
+// IsRemoteSigner implements pki.RemoteSigner::IsRemoteSigner.
+// The private key of a keyset is held remotely if a PKI secrets engine is mounted for it.
+func (c *VaultCAStore) IsRemoteSigner(name string) (bool, error) {
+	if c.pkiPrefix == "" {
+		return false, nil
+	}
+
+	mounts, err := c.listPKIMounts()
+	if err != nil {
+		return false, err
+	}
+	return mounts[c.pkiMount(name)], nil
+}
+
+// listPKIMounts returns the mount points of the PKI secrets engines under the prefix.
+func (c *VaultCAStore) listPKIMounts() (map[string]bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.pkiMounts != nil {
+		return c.pkiMounts, nil
+	}
+
+	mounts, err := c.client.Sys().ListMounts()
+	if err != nil {
+		return nil, fmt.Errorf("error listing vault secrets engines: %v", err)
+	}
+
+	pkiMounts := make(map[string]bool)
+	for mountPath, mount := range mounts {
+		mountPath = strings.TrimSuffix(mountPath, "/")
+		if mount.Type == "pki" && strings.HasPrefix(mountPath, c.pkiPrefix+"/") {
+			pkiMounts[mountPath] = true
+		}
+	}
+	c.pkiMounts = pkiMounts
+	return pkiMounts, nil
+}
+
+// FindKeyset implements fi.Keystore::FindKeyset
+func (c *VaultCAStore) FindKeyset(name string) (*fi.Keyset, error) {
+	remote, err := c.IsRemoteSigner(name)
+	if err != nil {
+		return nil, err
+	}
+	if !remote {
+		return c.VFSCAStore.FindKeyset(name)
+	}
+
+	caCertificate, err := c.readPKICA(name)
+	if err != nil || caCertificate == nil {
+		return nil, err
+	}
+
+	item := &fi.KeysetItem{
+		Id:          caCertificate.Certificate.SerialNumber.String(),
+		Certificate: caCertificate,
+	}
+	return &fi.Keyset{
+		Items:   map[string]*fi.KeysetItem{item.Id: item},
+		Primary: item,
+	}, nil
+}
+
+// FindPrimaryKeypair implements pki.Keystore::FindPrimaryKeypair
+func (c *VaultCAStore) FindPrimaryKeypair(name string) (*pki.Certificate, *pki.PrivateKey, error) {
+	return fi.FindPrimaryKeypair(c, name)
+}
+
+// FindCert implements fi.CAStore::FindCert
+func (c *VaultCAStore) FindCert(name string) (*pki.Certificate, error) {
+	remote, err := c.IsRemoteSigner(name)
+	if err != nil {
+		return nil, err
+	}
+	if !remote {
+		return c.VFSCAStore.FindCert(name)
+	}
+	return c.readPKICA(name)
+}
+
+// FindPrivateKey implements fi.CAStore::FindPrivateKey
+// The private keys of the keysets held by PKI secrets engines are never returned.
+func (c *VaultCAStore) FindPrivateKey(name string) (*pki.PrivateKey, error) {
+	remote, err := c.IsRemoteSigner(name)
+	if err != nil {
+		return nil, err
+	}
+	if !remote {
+		return c.VFSCAStore.FindPrivateKey(name)
+	}
+	return nil, nil
+}
+
+// ListKeysets implements fi.CAStore::ListKeysets
+func (c *VaultCAStore) ListKeysets() (map[string]*fi.Keyset, error) {
+	keysets, err := c.VFSCAStore.ListKeysets()
+	if err != nil {
+		return nil, err
+	}
+	if c.pkiPrefix == "" {
+		return keysets, nil
+	}
+
+	mounts, err := c.listPKIMounts()
+	if err != nil {
+		return nil, err
+	}
+	for mountPath := range mounts {
+		name := strings.TrimPrefix(mountPath, c.pkiPrefix+"/")
+		keyset, err := c.FindKeyset(name)
+		if err != nil {
+			return nil, err
+		}
+		if keyset != nil {
+			keysets[name] = keyset
+		}
+	}
+	return keysets, nil
+}
+
+// StoreKeyset implements fi.Keystore::StoreKeyset
+// A PKI secrets engine holds a single CA, which it generates itself: storing a keyset held by one
+// generates the CA in Vault if it does not have one yet, using the subject and expiry of the primary
+// certificate. The private key of the keyset is discarded.
+func (c *VaultCAStore) StoreKeyset(name string, keyset *fi.Keyset) error {
+	remote, err := c.IsRemoteSigner(name)
+	if err != nil {
+		return err
+	}
+	if !remote {
+		return c.VFSCAStore.StoreKeyset(name, keyset)
+	}
+
+	if keyset.Primary == nil || keyset.Primary.Certificate == nil {
+		return fmt.Errorf("keyset must have a primary certificate")
+	}
+
+	existing, err := c.readPKICA(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.Certificate.SerialNumber.Cmp(keyset.Primary.Certificate.Certificate.SerialNumber) == 0 {
+			return nil
+		}
+		return fmt.Errorf("keyset %q is held by the vault PKI secrets engine at %q, which already has a CA", name, c.pkiMount(name))
+	}
+
+	primary := keyset.Primary.Certificate.Certificate
+	klog.Infof("generating CA for keyset %q in the vault PKI secrets engine at %q", name, c.pkiMount(name))
+	data := map[string]interface{}{
+		"common_name": primary.Subject.CommonName,
+		"key_type":    "rsa",
+		"key_bits":    2048,
+	}
+	if len(primary.Subject.Organization) != 0 {
+		data["organization"] = strings.Join(primary.Subject.Organization, ",")
+	}
+	if ttl := time.Until(primary.NotAfter); ttl > 0 {
+		data["ttl"] = formatTTL(ttl)
+	}
+	if _, err := c.client.Logical().Write(c.pkiMount(name)+"/root/generate/internal", data); err != nil {
+		return fmt.Errorf("error generating CA in the vault PKI secrets engine at %q: %v", c.pkiMount(name), err)
+	}
+	return nil
+}
+
+// readPKICA reads the CA certificate of the PKI secrets engine of the named keyset.
+// It returns nil if the secrets engine has no CA yet.
+func (c *VaultCAStore) readPKICA(name string) (*pki.Certificate, error) {
+	secret, err := c.client.Logical().Read(c.pkiMount(name) + "/cert/ca")
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA from the vault PKI secrets engine at %q: %v", c.pkiMount(name), err)
+	}
+	if secret == nil {
+		return nil, nil
+	}
+	certificate, _ := secret.Data["certificate"].(string)
+	if strings.TrimSpace(certificate) == "" {
+		return nil, nil
+	}
+	return pki.ParsePEMCertificate([]byte(certificate))
+}
+
+// SignCertificateRequest implements pki.RemoteSigner::SignCertificateRequest
+func (c *VaultCAStore) SignCertificateRequest(name string, csr *x509.CertificateRequest, template *x509.Certificate) (*pki.Certificate, *pki.Certificate, error) {
+	data := map[string]interface{}{
+		"csr":           string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr.Raw})),
+		"key_usage":     keyUsageNames(template.KeyUsage),
+		"ext_key_usage": extKeyUsageNames(template.ExtKeyUsage),
+	}
+	if !template.NotAfter.IsZero() {
+		data["ttl"] = formatTTL(time.Until(template.NotAfter))
+	}
+
+	secret, err := c.client.Logical().Write(c.pkiMount(name)+"/sign-verbatim", data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if secret == nil {
+		return nil, nil, fmt.Errorf("vault PKI secrets engine at %q returned no certificate", c.pkiMount(name))
+	}
+
+	certificatePEM, _ := secret.Data["certificate"].(string)
+	certificate, err := pki.ParsePEMCertificate([]byte(certificatePEM))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing issued certificate: %v", err)
+	}
+	caPEM, _ := secret.Data["issuing_ca"].(string)
+	caCertificate, err := pki.ParsePEMCertificate([]byte(caPEM))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing issuing CA certificate: %v", err)
+	}
+	return certificate, caCertificate, nil
+}
+
+// formatTTL formats a duration as a Vault TTL in whole seconds.
+func formatTTL(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(d/time.Second))
+}
+
+var keyUsages = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "DigitalSignature"},
+	{x509.KeyUsageContentCommitment, "ContentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "KeyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "DataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "KeyAgreement"},
+	{x509.KeyUsageCertSign, "CertSign"},
+	{x509.KeyUsageCRLSign, "CRLSign"},
+	{x509.KeyUsageEncipherOnly, "EncipherOnly"},
+	{x509.KeyUsageDecipherOnly, "DecipherOnly"},
+}
+
+// keyUsageNames returns the names Vault uses for the key usages.
+func keyUsageNames(usage x509.KeyUsage) []string {
+	var names []string
+	for _, u := range keyUsages {
+		if usage&u.usage != 0 {
+			names = append(names, u.name)
+		}
+	}
+	return names
+}
+
+var extKeyUsageNamesByUsage = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:        "Any",
+	x509.ExtKeyUsageServerAuth: "ServerAuth",
+	x509.ExtKeyUsageClientAuth: "ClientAuth",
+}
+
+// extKeyUsageNames returns the names Vault uses for the extended key usages.
+func extKeyUsageNames(usages []x509.ExtKeyUsage) []string {
+	var names []string
+	for _, u := range usages {
+		if name, ok := extKeyUsageNamesByUsage[u]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
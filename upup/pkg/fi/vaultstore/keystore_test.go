@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultstore
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// createKeyStore builds a keystore against a fake vault server, with a PKI secrets engine mounted for the kubernetes-ca keyset.
+func createKeyStore(t *testing.T) *VaultCAStore {
+	server := newFakeVault(t, "pki/test/kubernetes-ca")
+
+	client, err := vault.NewClient(&vault.Config{Address: server.URL, HttpClient: server.Client()})
+	require.NoError(t, err)
+	client.SetToken("test")
+
+	basedir, err := vfs.NewVaultPath(client, "https://", "/secret/clusters/test/pki")
+	require.NoError(t, err)
+
+	return NewVaultCAStore(&kops.Cluster{}, basedir, "pki/test")
+}
+
+func TestVaultCAStore_IssueCert(t *testing.T) {
+	keyStore := createKeyStore(t)
+
+	remote, err := keyStore.IsRemoteSigner("kubernetes-ca")
+	require.NoError(t, err)
+	assert.True(t, remote, "kubernetes-ca is remote")
+
+	remote, err = keyStore.IsRemoteSigner("etcd-clients-ca")
+	require.NoError(t, err)
+	assert.False(t, remote, "etcd-clients-ca is remote")
+
+	keyset, err := keyStore.FindKeyset("kubernetes-ca")
+	require.NoError(t, err)
+	assert.Nil(t, keyset, "keyset before the CA is generated")
+
+	caCertificate, caPrivateKey, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+	}, nil)
+	require.NoError(t, err)
+	localKeyset, err := fi.NewKeyset(caCertificate, caPrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.StoreKeyset("kubernetes-ca", localKeyset))
+
+	keyset, err = keyStore.FindKeyset("kubernetes-ca")
+	require.NoError(t, err)
+	require.NotNil(t, keyset, "keyset after the CA is generated")
+	require.NotNil(t, keyset.Primary)
+	vaultCA := keyset.Primary.Certificate
+	assert.Equal(t, "kubernetes-ca", vaultCA.Subject.CommonName, "CA subject")
+	assert.True(t, vaultCA.IsCA, "CA is a CA")
+	assert.Nil(t, keyset.Primary.PrivateKey, "CA private key")
+
+	// Storing the keyset Vault generated is a no-op; storing another one fails
+	assert.NoError(t, keyStore.StoreKeyset("kubernetes-ca", keyset))
+	assert.Error(t, keyStore.StoreKeyset("kubernetes-ca", localKeyset))
+
+	certificate, privateKey, issuingCA, err := pki.IssueCert(&pki.IssueCertRequest{
+		Signer:         "kubernetes-ca",
+		Type:           "client",
+		Subject:        pkix.Name{CommonName: "kubelet", Organization: []string{"system:nodes"}},
+		AlternateNames: []string{"node.example.com"},
+		Validity:       24 * time.Hour,
+	}, keyStore)
+	require.NoError(t, err)
+	require.NotNil(t, privateKey)
+
+	cert := certificate.Certificate
+	assert.NoError(t, cert.CheckSignatureFrom(vaultCA.Certificate), "check signature")
+	assert.Equal(t, vaultCA.Certificate.Raw, issuingCA.Certificate.Raw, "issuing CA")
+	assert.Equal(t, "kubelet", cert.Subject.CommonName, "Subject")
+	assert.Equal(t, []string{"system:nodes"}, cert.Subject.Organization, "Organization")
+	assert.Equal(t, []string{"node.example.com"}, cert.DNSNames, "DNSNames")
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage, "ExtKeyUsage")
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), cert.NotAfter, 5*time.Minute, "NotAfter")
+
+	// Keysets without a PKI secrets engine are kept in the KV secrets engine
+	etcdCertificate, etcdPrivateKey, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "etcd-clients-ca"},
+	}, nil)
+	require.NoError(t, err)
+	etcdKeyset, err := fi.NewKeyset(etcdCertificate, etcdPrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.StoreKeyset("etcd-clients-ca", etcdKeyset))
+	privateKey, err = keyStore.FindPrivateKey("etcd-clients-ca")
+	require.NoError(t, err)
+	assert.NotNil(t, privateKey, "etcd-clients-ca private key")
+
+	privateKey, err = keyStore.FindPrivateKey("kubernetes-ca")
+	require.NoError(t, err)
+	assert.Nil(t, privateKey, "kubernetes-ca private key")
+
+	keysets, err := keyStore.ListKeysets()
+	require.NoError(t, err)
+	assert.Contains(t, keysets, "kubernetes-ca")
+	assert.Contains(t, keysets, "etcd-clients-ca")
+}
+
+func TestKeyUsageNames(t *testing.T) {
+	assert.Equal(t, []string{"DigitalSignature", "KeyEncipherment"}, keyUsageNames(x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment))
+	assert.Equal(t, []string{"ServerAuth", "ClientAuth"}, extKeyUsageNames([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}))
+	assert.Nil(t, keyUsageNames(0))
+}
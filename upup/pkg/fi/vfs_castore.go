@@ -33,6 +33,7 @@ import (
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/pkg/stateencryption"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -117,6 +118,10 @@ func (c *VFSCAStore) loadKeyset(p vfs.Path) (*Keyset, error) {
 		return nil, fmt.Errorf("error parsing bundle %q: %v", p, err)
 	}
 
+	if err := decryptPrivateMaterial(o); err != nil {
+		return nil, fmt.Errorf("error decrypting bundle %q: %v", p, err)
+	}
+
 	keyset, err := parseKeyset(o)
 	if err != nil {
 		return nil, fmt.Errorf("error mapping bundle %q: %v", p, err)
@@ -184,6 +189,10 @@ func writeKeysetBundle(cluster *kops.Cluster, p vfs.Path, name string, keyset *K
 		return err
 	}
 
+	if err := encryptPrivateMaterial(cluster, o); err != nil {
+		return err
+	}
+
 	objectData, err := serializeKeysetBundle(o)
 	if err != nil {
 		return err
@@ -196,6 +205,39 @@ func writeKeysetBundle(cluster *kops.Cluster, p vfs.Path, name string, keyset *K
 	return p.WriteFile(bytes.NewReader(objectData), acl)
 }
 
+// encryptPrivateMaterial encrypts the private keys of a Keyset bundle with the state encryption key of the cluster, if any.
+func encryptPrivateMaterial(cluster *kops.Cluster, o *kops.Keyset) error {
+	if cluster == nil || cluster.Spec.StateEncryptionKey == "" {
+		return nil
+	}
+	keyURL := cluster.Spec.StateEncryptionKey
+	for i := range o.Spec.Keys {
+		key := &o.Spec.Keys[i]
+		if len(key.PrivateMaterial) == 0 {
+			continue
+		}
+		encrypted, err := stateencryption.Encrypt(keyURL, key.PrivateMaterial)
+		if err != nil {
+			return fmt.Errorf("error encrypting private key %q: %v", key.Id, err)
+		}
+		key.PrivateMaterial = encrypted
+	}
+	return nil
+}
+
+// decryptPrivateMaterial decrypts the private keys of a Keyset bundle that were encrypted by encryptPrivateMaterial.
+func decryptPrivateMaterial(o *kops.Keyset) error {
+	for i := range o.Spec.Keys {
+		key := &o.Spec.Keys[i]
+		decrypted, err := stateencryption.Decrypt(key.PrivateMaterial)
+		if err != nil {
+			return fmt.Errorf("error decrypting private key %q: %v", key.Id, err)
+		}
+		key.PrivateMaterial = decrypted
+	}
+	return nil
+}
+
 // serializeKeysetBundle converts a Keyset bundle to yaml, for writing to VFS.
 func serializeKeysetBundle(o *kops.Keyset) ([]byte, error) {
 	var objectData bytes.Buffer
@@ -24,7 +24,9 @@ import (
 	"testing"
 	"time"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/stateencryption"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -200,6 +202,69 @@ spec:
 	}
 }
 
+func TestVFSCAStoreEncryptedRoundTrip(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	stateencryption.UseFakeKeyForTesting()
+	cluster := &kops.Cluster{}
+	cluster.Spec.StateEncryptionKey = stateencryption.FakeKeyURL
+	s := NewVFSCAStore(cluster, basePath)
+
+	privateKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+	privateKeyData, err := privateKey.AsString()
+	if err != nil {
+		t.Fatalf("error serializing private key: %v", err)
+	}
+
+	item := &KeysetItem{
+		Id:         "1",
+		PrivateKey: privateKey,
+	}
+	err = s.StoreKeyset("service-account", &Keyset{
+		Items:   map[string]*KeysetItem{"1": item},
+		Primary: item,
+	})
+	if err != nil {
+		t.Fatalf("error from StoreKeyset: %v", err)
+	}
+
+	privateKeysetYaml, err := basePath.Join("private", "service-account", "keyset.yaml").ReadFile()
+	if err != nil {
+		t.Fatalf("error reading private keyset: %v", err)
+	}
+	o, _, err := s.parseKeysetYaml(privateKeysetYaml)
+	if err != nil {
+		t.Fatalf("error parsing private keyset: %v", err)
+	}
+	if len(o.Spec.Keys) != 1 || !stateencryption.IsEncrypted(o.Spec.Keys[0].PrivateMaterial) {
+		t.Fatalf("expected the private key to be encrypted: %q", string(privateKeysetYaml))
+	}
+
+	// Reading needs no configuration, as the envelope names its key
+	key, err := s.FindPrivateKey("service-account")
+	if err != nil {
+		t.Fatalf("error reading private key: %v", err)
+	}
+	if key == nil {
+		t.Fatalf("private key was nil")
+	}
+	roundTrip, err := key.AsString()
+	if err != nil {
+		t.Fatalf("error serializing private key: %v", err)
+	}
+	if roundTrip != privateKeyData {
+		t.Fatalf("unexpected round-tripped private key data: %q", roundTrip)
+	}
+}
+
 func TestVFSCAStoreRoundTripWithVault(t *testing.T) {
 
 	token := os.Getenv("VAULT_DEV_ROOT_TOKEN_ID")
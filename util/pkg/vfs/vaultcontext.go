@@ -32,6 +32,14 @@ import (
 	vault "github.com/hashicorp/vault/api"
 )
 
+const (
+	// VaultRoleEnv is the environment variable naming the Vault role to log in as, when VAULT_TOKEN is not set.
+	VaultRoleEnv = "KOPS_VAULT_ROLE"
+
+	// kubernetesServiceAccountTokenPath is where the token of the pod's service account is mounted.
+	kubernetesServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
 func newVaultClient(scheme string, host string, port string) (*vault.Client, error) {
 	addr := scheme + host
 	if port != "" {
@@ -48,9 +56,17 @@ func newVaultClient(scheme string, host string, port string) (*vault.Client, err
 
 	token := os.Getenv("VAULT_TOKEN")
 	if token == "" {
-		token, err = awsAuth(client, host)
-		if err != nil {
-			return nil, fmt.Errorf("error authenticating vault to AWS: %v", err)
+		if _, err := os.Stat(kubernetesServiceAccountTokenPath); err == nil {
+			// Running in a pod, e.g. kops-controller
+			token, err = kubernetesAuth(client, host)
+			if err != nil {
+				return nil, fmt.Errorf("error authenticating vault to Kubernetes: %v", err)
+			}
+		} else {
+			token, err = awsAuth(client, host)
+			if err != nil {
+				return nil, fmt.Errorf("error authenticating vault to AWS: %v", err)
+			}
 		}
 	}
 
@@ -83,7 +99,7 @@ func awsAuth(client *vault.Client, host string) (string, error) {
 	loginData["iam_request_url"] = base64.StdEncoding.EncodeToString([]byte(stsRequest.HTTPRequest.URL.String()))
 	loginData["iam_request_headers"] = base64.StdEncoding.EncodeToString(headersJson)
 	loginData["iam_request_body"] = base64.StdEncoding.EncodeToString(requestBody)
-	loginData["role"] = ""
+	loginData["role"] = os.Getenv(VaultRoleEnv)
 	path := "auth/aws/login"
 	secret, err := client.Logical().Write(path, loginData)
 	if err != nil {
@@ -91,3 +107,24 @@ func awsAuth(client *vault.Client, host string) (string, error) {
 	}
 	return secret.Auth.ClientToken, err
 }
+
+func kubernetesAuth(client *vault.Client, host string) (string, error) {
+	role := os.Getenv(VaultRoleEnv)
+	klog.Infof("Using the Kubernetes service account to authenticate to Vault at %q with role %q", host, role)
+	jwt, err := ioutil.ReadFile(kubernetesServiceAccountTokenPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading service account token: %v", err)
+	}
+	loginData := map[string]interface{}{
+		"jwt":  string(jwt),
+		"role": role,
+	}
+	secret, err := client.Logical().Write("auth/kubernetes/login", loginData)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil || secret.Auth == nil {
+		return "", fmt.Errorf("vault returned no token")
+	}
+	return secret.Auth.ClientToken, nil
+}
@@ -39,6 +39,11 @@ type VaultPath struct {
 
 var _ Path = &VaultPath{}
 
+// NewVaultPath returns the path in Vault, accessed through client.
+func NewVaultPath(client *vault.Client, scheme string, path string) (*VaultPath, error) {
+	return newVaultPath(client, scheme, path)
+}
+
 func newVaultPath(client *vault.Client, scheme string, path string) (*VaultPath, error) {
 	if scheme != "https://" && scheme != "http://" {
 		return nil, fmt.Errorf("scheme must be http:// or https://")
@@ -269,6 +274,11 @@ func (p VaultPath) deleteMetadata() error {
 
 }
 
+// Client returns the Vault client used to access the path.
+func (p *VaultPath) Client() *vault.Client {
+	return p.vaultClient
+}
+
 func (p VaultPath) SetClientToken(token string) {
 	p.vaultClient.SetToken(token)
 }